@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/client"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const usage = `Usage: kubesight-cli <command> [flags]
+
+Commands:
+  query    Execute a query against the engine
+  top-k    Show the heaviest hitters for a metric
+  stats    Show system statistics
+  health   Check server health
+
+Global flags:
+  --server  KubeSight server URL (default "http://localhost:8080", env KUBESIGHT_SERVER)
+  --token   Bearer token for authenticated servers (env KUBESIGHT_TOKEN)
+  --output  Output format: json, table or csv (default "table")
+
+Examples:
+  kubesight-cli query --type count_distinct --metric pod_name
+  kubesight-cli query --type percentile --p 95 --metric cpu_usage --namespace default
+  kubesight-cli top-k --k 10 --metric memory_usage
+  kubesight-cli stats --output json
+`
+
+type globalOptions struct {
+	server string
+	token  string
+	output string
+}
+
+type command struct {
+	flags *flag.FlagSet
+	run   func(ctx context.Context, c *client.Client, opts *globalOptions) error
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	opts := &globalOptions{}
+	commands := map[string]*command{
+		"query":  newQueryCommand(),
+		"top-k":  newTopKCommand(),
+		"stats":  newStatsCommand(),
+		"health": newHealthCommand(),
+	}
+
+	cmd, exists := commands[os.Args[1]]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	registerGlobalFlags(cmd.flags, opts)
+	if err := cmd.flags.Parse(os.Args[2:]); err != nil {
+		os.Exit(2)
+	}
+
+	switch opts.output {
+	case "json", "table", "csv":
+	default:
+		fmt.Fprintf(os.Stderr, "unsupported output format: %s\n", opts.output)
+		os.Exit(2)
+	}
+
+	c := client.NewClient(opts.server)
+	c.Token = opts.token
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := cmd.run(ctx, c, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func registerGlobalFlags(flags *flag.FlagSet, opts *globalOptions) {
+	flags.StringVar(&opts.server, "server", getEnvOrDefault("KUBESIGHT_SERVER", "http://localhost:8080"), "KubeSight server URL")
+	flags.StringVar(&opts.token, "token", os.Getenv("KUBESIGHT_TOKEN"), "Bearer token")
+	flags.StringVar(&opts.output, "output", "table", "Output format: json, table or csv")
+}
+
+func newQueryCommand() *command {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	queryType := flags.String("type", "", "Query type: count_distinct, sum, average, percentile, top_k, membership, frequency_count")
+	metric := flags.String("metric", "", "Metric name (or field for count_distinct)")
+	percentile := flags.Float64("p", 95, "Percentile for percentile queries")
+	k := flags.Int("k", 10, "K for top_k queries")
+	item := flags.String("item", "", "Item for membership and frequency_count queries")
+	namespace := flags.String("namespace", "", "Filter by namespace")
+	cluster := flags.String("cluster", "", "Filter by cluster ID")
+	groupBy := flags.String("group-by", "", "Label to group count_distinct results by")
+
+	return &command{
+		flags: flags,
+		run: func(ctx context.Context, c *client.Client, opts *globalOptions) error {
+			if *queryType == "" {
+				return fmt.Errorf("--type is required")
+			}
+
+			request, err := buildQueryRequest(metrics.QueryType(*queryType), *metric, *percentile, *k, *item)
+			if err != nil {
+				return err
+			}
+			if *namespace != "" {
+				request.Filters["namespace"] = *namespace
+			}
+			if *cluster != "" {
+				request.Filters["cluster_id"] = *cluster
+			}
+			request.GroupByLabel = *groupBy
+
+			result, err := c.ExecuteQuery(ctx, request)
+			if err != nil {
+				return err
+			}
+			return printQueryResult(os.Stdout, opts.output, result)
+		},
+	}
+}
+
+func newTopKCommand() *command {
+	flags := flag.NewFlagSet("top-k", flag.ExitOnError)
+	k := flags.Int("k", 10, "Number of heavy hitters to return")
+	metric := flags.String("metric", "", "Metric name")
+
+	return &command{
+		flags: flags,
+		run: func(ctx context.Context, c *client.Client, opts *globalOptions) error {
+			request, err := buildQueryRequest(metrics.TopK, *metric, 0, *k, "")
+			if err != nil {
+				return err
+			}
+
+			result, err := c.ExecuteQuery(ctx, request)
+			if err != nil {
+				return err
+			}
+			return printQueryResult(os.Stdout, opts.output, result)
+		},
+	}
+}
+
+func newStatsCommand() *command {
+	return &command{
+		flags: flag.NewFlagSet("stats", flag.ExitOnError),
+		run: func(ctx context.Context, c *client.Client, opts *globalOptions) error {
+			stats, err := c.GetStats(ctx)
+			if err != nil {
+				return err
+			}
+
+			if opts.output == "json" {
+				return printJSON(os.Stdout, stats)
+			}
+			return printRows(os.Stdout, opts.output, []string{"FIELD", "VALUE"}, [][]string{
+				{"total_metrics", fmt.Sprintf("%d", stats.TotalMetrics)},
+				{"sampled_metrics", fmt.Sprintf("%d", stats.SampledMetrics)},
+				{"sampling_rate", fmt.Sprintf("%.4f", stats.SamplingRate)},
+				{"processing_rate", fmt.Sprintf("%.2f", stats.ProcessingRate)},
+				{"query_latency_p95_ms", fmt.Sprintf("%.3f", stats.QueryLatencyP95)},
+				{"error_rate", fmt.Sprintf("%.4f", stats.ErrorRate)},
+			})
+		},
+	}
+}
+
+func newHealthCommand() *command {
+	return &command{
+		flags: flag.NewFlagSet("health", flag.ExitOnError),
+		run: func(ctx context.Context, c *client.Client, opts *globalOptions) error {
+			health, err := c.Health(ctx)
+			if err != nil {
+				return err
+			}
+
+			if opts.output == "json" {
+				return printJSON(os.Stdout, health)
+			}
+
+			keys := make([]string, 0, len(health))
+			for key := range health {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			rows := make([][]string, len(keys))
+			for i, key := range keys {
+				rows[i] = []string{key, fmt.Sprintf("%v", health[key])}
+			}
+			return printRows(os.Stdout, opts.output, []string{"FIELD", "VALUE"}, rows)
+		},
+	}
+}
+
+func buildQueryRequest(queryType metrics.QueryType, metric string, percentile float64, k int, item string) (*metrics.QueryRequest, error) {
+	request := &metrics.QueryRequest{
+		ID:        fmt.Sprintf("cli_%d", time.Now().UnixNano()),
+		QueryType: queryType,
+		Filters:   make(map[string]string),
+	}
+
+	switch queryType {
+	case metrics.CountDistinct:
+		if metric == "" {
+			metric = "pod_name"
+		}
+		request.Query = fmt.Sprintf("COUNT_DISTINCT(%s)", metric)
+		return request, nil
+	case metrics.Percentile:
+		request.Query = strings.TrimSpace(fmt.Sprintf("PERCENTILE(%g) %s", percentile, metric))
+	case metrics.TopK:
+		request.Query = strings.TrimSpace(fmt.Sprintf("TOP_K(%d) %s", k, metric))
+	case metrics.Sum, metrics.Average:
+		request.Query = strings.TrimSpace(fmt.Sprintf("%s(value) %s", strings.ToUpper(string(queryType)), metric))
+	case metrics.Membership, metrics.FrequencyCount:
+		if item == "" {
+			return nil, fmt.Errorf("--item is required for %s queries", queryType)
+		}
+		request.Query = fmt.Sprintf("%s('%s')", strings.ToUpper(string(queryType)), item)
+	default:
+		return nil, fmt.Errorf("unsupported query type: %s", queryType)
+	}
+
+	if metric != "" {
+		request.Filters["metric_name"] = metric
+	}
+	return request, nil
+}
+
+func printQueryResult(w io.Writer, output string, result *metrics.QueryResult) error {
+	if output == "json" {
+		return printJSON(w, result)
+	}
+
+	if items, ok := topKItems(result.Result); ok {
+		rows := make([][]string, len(items))
+		for i, item := range items {
+			rows[i] = []string{
+				fmt.Sprintf("%d", i+1),
+				item.Key,
+				fmt.Sprintf("%d", item.Count),
+				fmt.Sprintf("%.4f", item.Frequency),
+			}
+		}
+		return printRows(w, output, []string{"RANK", "KEY", "COUNT", "FREQUENCY"}, rows)
+	}
+
+	rows := [][]string{
+		{"id", result.ID},
+		{"query", result.Query},
+		{"result", formatValue(result.Result)},
+		{"sample_size", fmt.Sprintf("%d", result.SampleSize)},
+		{"approximate", fmt.Sprintf("%v", result.IsApproximate)},
+		{"processing_time", result.ProcessingTime.String()},
+	}
+	if result.Error != nil {
+		rows = append(rows, []string{"error_bound", fmt.Sprintf("%g", *result.Error)})
+	}
+	if result.Confidence != nil {
+		rows = append(rows, []string{"confidence", fmt.Sprintf("%g", *result.Confidence)})
+	}
+	return printRows(w, output, []string{"FIELD", "VALUE"}, rows)
+}
+
+func topKItems(result interface{}) ([]metrics.TopKItem, bool) {
+	fields, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	if _, hasItems := fields["items"]; !hasItems {
+		return nil, false
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false
+	}
+
+	var topK metrics.TopKResult
+	if err := json.Unmarshal(data, &topK); err != nil {
+		return nil, false
+	}
+	return topK.Items, true
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case float64:
+		return fmt.Sprintf("%g", v)
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func printRows(w io.Writer, output string, header []string, rows [][]string) error {
+	if output == "csv" {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
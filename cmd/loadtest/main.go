@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/client"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+type options struct {
+	server       string
+	token        string
+	concurrency  int
+	duration     time.Duration
+	maxErrorRate float64
+	templates    string
+	metric       string
+}
+
+type result struct {
+	queryType metrics.QueryType
+	latency   time.Duration
+	err       error
+}
+
+type typeStats struct {
+	latencies []time.Duration
+	errors    int
+}
+
+func main() {
+	opts := parseFlags()
+
+	templates, err := loadTemplates(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	c := client.NewClient(opts.server)
+	c.Token = opts.token
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	fmt.Printf("Running load test against %s: %d workers for %s across %d query types\n",
+		opts.server, opts.concurrency, opts.duration, len(templates))
+
+	results := make(chan result, opts.concurrency*16)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.concurrency; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			runWorker(ctx, c, worker, templates, results)
+		}(i)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	stats := make(map[metrics.QueryType]*typeStats)
+	for r := range results {
+		entry, exists := stats[r.queryType]
+		if !exists {
+			entry = &typeStats{}
+			stats[r.queryType] = entry
+		}
+		entry.latencies = append(entry.latencies, r.latency)
+		if r.err != nil {
+			entry.errors++
+		}
+	}
+
+	errorRate := printReport(stats)
+	if errorRate > opts.maxErrorRate {
+		fmt.Fprintf(os.Stderr, "Error rate %.4f exceeds maximum %.4f\n", errorRate, opts.maxErrorRate)
+		os.Exit(1)
+	}
+}
+
+func parseFlags() *options {
+	opts := &options{}
+
+	flag.StringVar(&opts.server, "server", getEnvOrDefault("KUBESIGHT_SERVER", "http://localhost:8080"), "KubeSight server URL")
+	flag.StringVar(&opts.token, "token", os.Getenv("KUBESIGHT_TOKEN"), "Bearer token")
+	flag.IntVar(&opts.concurrency, "concurrency", 10, "Number of concurrent workers")
+	flag.DurationVar(&opts.duration, "duration", 30*time.Second, "How long to run the load test")
+	flag.Float64Var(&opts.maxErrorRate, "max-error-rate", 0.01, "Exit non-zero when the overall error rate exceeds this fraction")
+	flag.StringVar(&opts.templates, "templates", "", "JSON file with an array of query request templates (defaults cover every query type)")
+	flag.StringVar(&opts.metric, "metric", "cpu_usage", "Metric used by the default templates")
+	flag.Parse()
+
+	if opts.concurrency <= 0 {
+		fmt.Fprintln(os.Stderr, "--concurrency must be positive")
+		os.Exit(2)
+	}
+	if opts.duration <= 0 {
+		fmt.Fprintln(os.Stderr, "--duration must be positive")
+		os.Exit(2)
+	}
+
+	return opts
+}
+
+func loadTemplates(opts *options) ([]metrics.QueryRequest, error) {
+	if opts.templates == "" {
+		return defaultTemplates(opts.metric, time.Now()), nil
+	}
+
+	data, err := os.ReadFile(opts.templates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates: %v", err)
+	}
+
+	var templates []metrics.QueryRequest
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("failed to parse templates: %v", err)
+	}
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("templates file %s contains no queries", opts.templates)
+	}
+	for i, template := range templates {
+		if template.QueryType == "" {
+			return nil, fmt.Errorf("template %d has no query_type", i)
+		}
+	}
+	return templates, nil
+}
+
+func defaultTemplates(metric string, now time.Time) []metrics.QueryRequest {
+	metricFilter := map[string]string{"metric_name": metric}
+
+	return []metrics.QueryRequest{
+		{QueryType: metrics.CountDistinct, Query: "COUNT_DISTINCT(pod_name)"},
+		{QueryType: metrics.Sum, Query: "SUM(value) " + metric, Filters: metricFilter},
+		{QueryType: metrics.Average, Query: "AVERAGE(value) " + metric, Filters: metricFilter},
+		{QueryType: metrics.Percentile, Query: "PERCENTILE(95) " + metric, Filters: metricFilter},
+		{QueryType: metrics.TopK, Query: "TOP_K(10) " + metric, Filters: metricFilter},
+		{QueryType: metrics.Membership, Query: "MEMBERSHIP('" + metric + "')"},
+		{QueryType: metrics.FrequencyCount, Query: "FREQUENCY_COUNT('" + metric + "')"},
+		{
+			QueryType: metrics.ApproxJoin,
+			Query:     "JOIN cpu_usage memory_usage",
+			Filters:   map[string]string{"left": "cpu_usage", "right": "memory_usage"},
+		},
+		{
+			QueryType: metrics.HistogramIntersection,
+			Query:     "HISTOGRAM_INTERSECTION " + metric,
+			Filters: map[string]string{
+				"metric_name":    metric,
+				"baseline_start": now.Add(-2 * time.Hour).Format(time.RFC3339),
+				"baseline_end":   now.Add(-time.Hour).Format(time.RFC3339),
+			},
+		},
+	}
+}
+
+func runWorker(ctx context.Context, c *client.Client, worker int, templates []metrics.QueryRequest, results chan<- result) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano() + int64(worker)))
+
+	for n := 0; ctx.Err() == nil; n++ {
+		request := templates[rng.Intn(len(templates))]
+		request.ID = fmt.Sprintf("loadtest_%d_%d", worker, n)
+
+		start := time.Now()
+		_, err := c.ExecuteQuery(ctx, &request)
+		latency := time.Since(start)
+
+		if ctx.Err() != nil {
+			return
+		}
+		results <- result{queryType: request.QueryType, latency: latency, err: err}
+	}
+}
+
+func printReport(stats map[metrics.QueryType]*typeStats) float64 {
+	queryTypes := make([]string, 0, len(stats))
+	for queryType := range stats {
+		queryTypes = append(queryTypes, string(queryType))
+	}
+	sort.Strings(queryTypes)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "QUERY TYPE\tREQUESTS\tERRORS\tERROR RATE\tP50\tP95\tP99\tMAX")
+
+	var all []time.Duration
+	totalErrors := 0
+	for _, queryType := range queryTypes {
+		entry := stats[metrics.QueryType(queryType)]
+		all = append(all, entry.latencies...)
+		totalErrors += entry.errors
+		printRow(w, queryType, entry.latencies, entry.errors)
+	}
+	printRow(w, "TOTAL", all, totalErrors)
+	w.Flush()
+
+	if len(all) == 0 {
+		return 0
+	}
+	return float64(totalErrors) / float64(len(all))
+}
+
+func printRow(w *tabwriter.Writer, name string, latencies []time.Duration, errors int) {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	errorRate := 0.0
+	if len(latencies) > 0 {
+		errorRate = float64(errors) / float64(len(latencies))
+	}
+
+	fmt.Fprintf(w, "%s\t%d\t%d\t%.4f\t%s\t%s\t%s\t%s\n",
+		name, len(latencies), errors, errorRate,
+		percentile(latencies, 0.50), percentile(latencies, 0.95),
+		percentile(latencies, 0.99), percentile(latencies, 1))
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx].Round(time.Microsecond)
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
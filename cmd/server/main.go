@@ -3,12 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"math"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
@@ -18,35 +17,74 @@ import (
 	"github.com/asmit27rai/kubesight/internal/api"
 	"github.com/asmit27rai/kubesight/internal/config"
 	"github.com/asmit27rai/kubesight/internal/engine"
+	"github.com/asmit27rai/kubesight/internal/logging"
+	"github.com/asmit27rai/kubesight/internal/middleware"
 	"github.com/asmit27rai/kubesight/internal/sampling"
+	"github.com/asmit27rai/kubesight/internal/slo"
 	"github.com/asmit27rai/kubesight/internal/stream"
 )
 
 func main() {
-	log.Println("🚀 Starting KubeSight Approximate Query Engine...")
-
 	cfg, err := config.LoadConfig("")
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+
+	if err := logging.Setup(os.Stderr, cfg.Server.LogLevel); err != nil {
+		slog.Warn("Falling back to info log level", "error", err)
 	}
 
+	slog.Info("Starting KubeSight Approximate Query Engine", "log_level", cfg.Server.LogLevel)
+
 	engineConfig := engine.QueryEngineConfig{
-		HLLPrecision: uint8(cfg.Storage.HLLPrecision),
-		CMSWidth:     uint32(cfg.Storage.CMSWidth),
-		CMSDepth:     uint32(cfg.Storage.CMSDepth),
-		BloomSize:    uint32(cfg.Storage.BloomSize),
-		BloomHashes:  uint32(cfg.Storage.BloomHashes),
+		HLLPrecision:      uint8(cfg.Storage.HLLPrecision),
+		AutoPrecision:     cfg.Storage.HLLAutoPrecision,
+		CMSWidth:          uint32(cfg.Storage.CMSWidth),
+		CMSDepth:          uint32(cfg.Storage.CMSDepth),
+		BloomSize:         uint32(cfg.Storage.BloomSize),
+		BloomHashes:       uint32(cfg.Storage.BloomHashes),
+		HistogramType:     cfg.Storage.HistogramType,
+		HistogramScale:    int32(cfg.Storage.HistogramScale),
+		QuantileAlgorithm: cfg.Storage.QuantileAlgorithm,
+		PressureWeights:   cfg.Storage.PressureWeights,
+
+		MaxQueryComplexity: cfg.Storage.MaxQueryComplexity,
+		SnapshotPath:       cfg.Storage.SnapshotPath,
 		SamplingConfig: sampling.SamplingConfig{
 			BaseRate:      cfg.Sampling.DefaultRate,
 			AnomalyRate:   cfg.Sampling.IncidentRate,
 			WindowSize:    time.Duration(cfg.Sampling.WindowSizeMin) * time.Minute,
 			ReservoirSize: cfg.Sampling.ReservoirSize,
+
+			MetricPriorities: cfg.Sampling.MetricPriorities,
 		},
+		CardinalityAlertThrottle: time.Duration(cfg.Storage.CardinalityAlertThrottleMinutes) * time.Minute,
+	}
+	if len(cfg.Storage.KeyDimensions) > 0 {
+		if err := engine.ValidateKeyDimensions(cfg.Storage.KeyDimensions); err != nil {
+			slog.Error("Invalid key dimensions", "error", err)
+			os.Exit(1)
+		}
+		engineConfig.KeyDimensions = cfg.Storage.KeyDimensions
+	}
+	for _, minutes := range cfg.Sampling.ResolutionTierMinutes {
+		engineConfig.ResolutionTiers = append(engineConfig.ResolutionTiers, time.Duration(minutes)*time.Minute)
+	}
+
+	for _, alert := range cfg.Storage.CardinalityAlerts {
+		engineConfig.CardinalityAlerts = append(engineConfig.CardinalityAlerts, engine.CardinalityAlert{
+			MetricName:      alert.MetricName,
+			MaxExpectedKeys: alert.MaxExpectedKeys,
+			WebhookURL:      alert.WebhookURL,
+		})
 	}
 
 	queryEngine := engine.NewQueryEngine(engineConfig)
-	log.Printf("Query Engine initialized with HLL precision: %d, CMS: %dx%d",
-		cfg.Storage.HLLPrecision, cfg.Storage.CMSWidth, cfg.Storage.CMSDepth)
+	slog.Info("Query engine initialized",
+		"hll_precision", cfg.Storage.HLLPrecision,
+		"cms_width", cfg.Storage.CMSWidth,
+		"cms_depth", cfg.Storage.CMSDepth)
 
 	streamConfig := stream.ProcessorConfig{
 		KafkaBrokers: cfg.Kafka.Brokers,
@@ -55,33 +93,136 @@ func main() {
 			Logs:    cfg.Kafka.Topics.Logs,
 			Events:  cfg.Kafka.Topics.Events,
 		},
-		QueryEngine: queryEngine,
+		QueryEngine:          queryEngine,
+		PartitionAssignment:  cfg.Kafka.PartitionAssignment,
+		PartitionStartOffset: cfg.Kafka.PartitionStartOffset,
+		Router:               stream.RouterConfig{PartitionByCluster: cfg.Kafka.PartitionByCluster},
+		AllowNegativeValues:  cfg.Kafka.AllowNegativeValues,
+		MetricAllowlist:      cfg.Kafka.MetricAllowlist,
+		MetricBlocklist:      cfg.Kafka.MetricBlocklist,
+		CategoryRules:        cfg.Kafka.CategoryRules,
+		PreAggregateMetrics:  cfg.Kafka.PreAggregateMetrics,
+		SamplingDefaults:     engineConfig.SamplingConfig,
+	}
+
+	for _, group := range cfg.Kafka.ConsumerGroups {
+		streamConfig.ConsumerGroups = append(streamConfig.ConsumerGroups, stream.ConsumerGroupConfig{
+			GroupID:       group.GroupID,
+			Topic:         group.Topic,
+			SamplingRate:  group.SamplingRate,
+			ReservoirSize: group.ReservoirSize,
+		})
+	}
+
+	for _, rule := range cfg.Kafka.NormalizationRules {
+		streamConfig.NormalizationRules = append(streamConfig.NormalizationRules, stream.NormalizationRule{
+			MetricName:  rule.MetricName,
+			Unit:        rule.Unit,
+			TargetUnit:  rule.TargetUnit,
+			ScaleFactor: rule.ScaleFactor,
+		})
+	}
+
+	for _, alias := range cfg.Kafka.MetricAliases {
+		streamConfig.MetricAliases = append(streamConfig.MetricAliases, stream.MetricAlias{
+			From: alias.From,
+			To:   alias.To,
+		})
+	}
+	for _, fanOut := range cfg.Kafka.MetricFanOuts {
+		streamConfig.MetricFanOuts = append(streamConfig.MetricFanOuts, stream.MetricFanOut{
+			From: fanOut.From,
+			To:   fanOut.To,
+		})
+	}
+
+	if cfg.Kafka.NamespaceLabelsFile != "" {
+		namespaceLabels, err := stream.LoadNamespaceLabels(cfg.Kafka.NamespaceLabelsFile)
+		if err != nil {
+			slog.Error("Failed to load namespace labels", "path", cfg.Kafka.NamespaceLabelsFile, "error", err)
+			os.Exit(1)
+		}
+		streamConfig.NamespaceLabels = namespaceLabels
 	}
 
 	processor, err := stream.NewProcessor(streamConfig)
 	if err != nil {
-		log.Fatalf("Failed to create stream processor: %v", err)
+		slog.Error("Failed to create stream processor", "error", err)
+		os.Exit(1)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	processorCtx, processorCancel := context.WithCancel(ctx)
+	defer processorCancel()
+	processorDone := make(chan struct{})
+
 	go func() {
-		log.Println("Starting stream processor...")
-		if err := processor.Start(ctx); err != nil {
-			log.Printf("Stream processor error: %v", err)
+		defer close(processorDone)
+		slog.Info("Starting stream processor")
+		if err := processor.Start(processorCtx); err != nil {
+			slog.Error("Stream processor error", "error", err)
 		}
 	}()
 
+	go queryEngine.StartCompactor(ctx, time.Minute)
+
+	if cfg.Storage.SnapshotPath != "" && cfg.Storage.SnapshotIntervalSeconds > 0 {
+		go queryEngine.StartSnapshotter(ctx, cfg.Storage.SnapshotPath, time.Duration(cfg.Storage.SnapshotIntervalSeconds)*time.Second)
+	}
+
+	sloTracker := slo.NewSLOTracker(queryEngine, time.Minute)
+	for _, sloConfig := range cfg.SLOs {
+		if sloConfig.WindowMinutes <= 0 {
+			sloConfig.WindowMinutes = 30 * 24 * 60
+		}
+		definition := slo.SLO{
+			Name:          sloConfig.Name,
+			MetricName:    sloConfig.MetricName,
+			Percentile:    sloConfig.Percentile,
+			GoodThreshold: sloConfig.GoodThreshold,
+			Target:        sloConfig.Target,
+			Window:        time.Duration(sloConfig.WindowMinutes) * time.Minute,
+		}
+		if err := sloTracker.Register(definition); err != nil {
+			slog.Warn("Skipping invalid SLO", "slo", sloConfig.Name, "error", err)
+		}
+	}
+	go sloTracker.Start(ctx)
+
 	apiHandler := api.NewHandler(queryEngine)
+	apiHandler.SetSLOTracker(sloTracker)
+	apiHandler.SetProcessor(processor)
+	apiHandler.SetAdminToken(cfg.Server.AdminToken)
+	if cfg.Server.DedupFilterFile != "" {
+		if err := apiHandler.SetDedupFilterFile(cfg.Server.DedupFilterFile); err != nil {
+			slog.Error("Failed to load dedup filter", "error", err)
+			os.Exit(1)
+		}
+	}
 	router := mux.NewRouter()
 
+	auditLog, err := middleware.OpenAuditLog(cfg.Server.AuditLogFile)
+	if err != nil {
+		slog.Error("Failed to open audit log", "error", err)
+		os.Exit(1)
+	}
+	if rotating, ok := auditLog.(*middleware.RotatingFile); ok {
+		defer rotating.Close()
+		go rotateOnSignal(ctx, rotating)
+	}
+
 	apiRouter := router.PathPrefix("/api/v1").Subrouter()
+	apiRouter.Use(middleware.RequestLogger)
+	apiRouter.Use(middleware.AuditLogger(auditLog))
 	api.RegisterRoutes(apiRouter, apiHandler)
 
 	router.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir("web/static/"))))
 	router.HandleFunc("/", serveDashboard)
-	router.HandleFunc("/health", healthCheck)
+	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		healthCheck(w, r, apiHandler.IsDraining())
+	})
 
 	c := cors.New(cors.Options{
 		AllowedOrigins: []string{"*"},
@@ -100,12 +241,14 @@ func main() {
 	}
 
 	go func() {
-		log.Printf("HTTP server starting on %s:%d", cfg.Server.Host, cfg.Server.Port)
-		log.Printf("Dashboard available at: http://%s:%d", cfg.Server.Host, cfg.Server.Port)
-		log.Printf("API available at: http://%s:%d/api/v1", cfg.Server.Host, cfg.Server.Port)
+		slog.Info("HTTP server starting",
+			"addr", server.Addr,
+			"dashboard", fmt.Sprintf("http://%s/", server.Addr),
+			"api", fmt.Sprintf("http://%s/api/v1", server.Addr))
 
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("HTTP server failed: %v", err)
+			slog.Error("HTTP server failed", "error", err)
+			os.Exit(1)
 		}
 	}()
 
@@ -115,7 +258,32 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("Draining server before shutdown")
+	apiHandler.StartDraining()
+
+	drainDeadline := time.Now().Add(cfg.Server.ShutdownDrainTimeout)
+
+	processorCancel()
+	select {
+	case <-processorDone:
+		slog.Info("Stream processor stopped")
+	case <-time.After(time.Until(drainDeadline)):
+		slog.Warn("Timed out waiting for stream processor to stop")
+	}
+
+	if err := queryEngine.Drain(time.Until(drainDeadline)); err != nil {
+		slog.Warn("Drain incomplete", "error", err)
+	} else {
+		slog.Info("All in-flight metrics processed")
+	}
+
+	if cfg.Storage.SnapshotPath != "" {
+		if err := queryEngine.SaveSnapshot(cfg.Storage.SnapshotPath); err != nil {
+			slog.Error("Failed to save engine snapshot", "error", err)
+		}
+	}
+
+	slog.Info("Shutting down server")
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
@@ -123,41 +291,60 @@ func main() {
 	cancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
-		log.Printf("Server forced to shutdown: %v", err)
+		slog.Error("Server forced to shutdown", "error", err)
 	}
 
-	log.Println("Server exited")
+	slog.Info("Server exited")
+}
+
+func rotateOnSignal(ctx context.Context, auditLog *middleware.RotatingFile) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR1)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			if err := auditLog.Rotate(); err != nil {
+				slog.Error("Audit log rotation failed", "error", err)
+			} else {
+				slog.Info("Audit log rotated")
+			}
+		}
+	}
 }
 
 func serveDashboard(w http.ResponseWriter, r *http.Request) {
 	http.ServeFile(w, r, "web/dashboard.html")
 }
 
-func healthCheck(w http.ResponseWriter, r *http.Request) {
+func healthCheck(w http.ResponseWriter, r *http.Request, draining bool) {
 	w.Header().Set("Content-Type", "application/json")
+	if draining {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, `{"status": "draining", "timestamp": "%s"}`, time.Now().Format(time.RFC3339))
+		return
+	}
 	w.WriteHeader(http.StatusOK)
 	fmt.Fprintf(w, `{"status": "healthy", "timestamp": "%s"}`, time.Now().Format(time.RFC3339))
 }
 
 func printStartupSummary(cfg *config.Config) {
-	log.Println("\n" + strings.Repeat("=", 60))
-	log.Println("KubeSight Approximate Query Engine")
-	log.Println(strings.Repeat("=", 60))
-	log.Printf("Server: http://%s:%d", cfg.Server.Host, cfg.Server.Port)
-	log.Printf("Kafka Brokers: %v", cfg.Kafka.Brokers)
-	log.Printf("Default Sampling Rate: %.2f%%", cfg.Sampling.DefaultRate*100)
-	log.Printf("Anomaly Sampling Rate: %.2f%%", cfg.Sampling.IncidentRate*100)
-	log.Printf("HyperLogLog Precision: %d (±%.2f%% error)",
-		cfg.Storage.HLLPrecision,
-		1.04/math.Sqrt(math.Pow(2, float64(cfg.Storage.HLLPrecision)))*100)
-	log.Printf("Count-Min Sketch: %dx%d", cfg.Storage.CMSWidth, cfg.Storage.CMSDepth)
-	log.Printf("Bloom Filter: %d elements, %d hash functions",
-		cfg.Storage.BloomSize, cfg.Storage.BloomHashes)
-	log.Println(strings.Repeat("=", 60))
-	log.Println("Ready to process approximate queries!")
-	log.Println("Try these sample queries:")
-	log.Println("   • GET /api/v1/query?type=count_distinct&metric=pod_name")
-	log.Println("   • GET /api/v1/query?type=percentile&metric=cpu_usage&p=95")
-	log.Println("   • GET /api/v1/query?type=top_k&metric=memory_usage&k=10")
-	log.Println(strings.Repeat("=", 60) + "\n")
+	slog.Info("KubeSight Approximate Query Engine ready",
+		"server", fmt.Sprintf("http://%s:%d", cfg.Server.Host, cfg.Server.Port),
+		"kafka_brokers", cfg.Kafka.Brokers,
+		"default_sampling_rate", cfg.Sampling.DefaultRate,
+		"anomaly_sampling_rate", cfg.Sampling.IncidentRate,
+		"hll_precision", cfg.Storage.HLLPrecision,
+		"hll_error", 1.04/math.Sqrt(math.Pow(2, float64(cfg.Storage.HLLPrecision))),
+		"cms_width", cfg.Storage.CMSWidth,
+		"cms_depth", cfg.Storage.CMSDepth,
+		"bloom_size", cfg.Storage.BloomSize,
+		"bloom_hashes", cfg.Storage.BloomHashes)
+	slog.Debug("Sample queries",
+		"count_distinct", "GET /api/v1/query?type=count_distinct&metric=pod_name",
+		"percentile", "GET /api/v1/query?type=percentile&metric=cpu_usage&p=95",
+		"top_k", "GET /api/v1/query?type=top_k&metric=memory_usage&k=10")
 }
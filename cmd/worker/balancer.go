@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/segmentio/kafka-go"
+
+	"github.com/asmit27rai/kubesight/internal/stream"
+)
+
+type ConsistentHashBalancer struct {
+	router *stream.ConsistentHashRouter
+}
+
+func NewConsistentHashBalancer(virtualNodes int) *ConsistentHashBalancer {
+	return &ConsistentHashBalancer{
+		router: stream.NewConsistentHashRouter(0, virtualNodes),
+	}
+}
+
+func (b *ConsistentHashBalancer) Balance(msg kafka.Message, partitions ...int) int {
+	if len(partitions) == 0 {
+		return 0
+	}
+	if b.router.Shards() != len(partitions) {
+		b.router.Resize(len(partitions))
+	}
+	return partitions[b.router.Route(string(msg.Key))]
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func balancerTestMessages(n int) []kafka.Message {
+	messages := make([]kafka.Message, n)
+	for i := range messages {
+		messages[i] = kafka.Message{Key: []byte(fmt.Sprintf("cluster-%d/ns-%d/pod-%d/main/cpu_usage", i%3, i%17, i))}
+	}
+	return messages
+}
+
+func TestConsistentHashBalancerSpreadsAcrossPartitions(t *testing.T) {
+	const keyCount = 50_000
+	partitions := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	balancer := NewConsistentHashBalancer(0)
+
+	loads := make(map[int]int, len(partitions))
+	for _, msg := range balancerTestMessages(keyCount) {
+		partition := balancer.Balance(msg, partitions...)
+		if first := balancer.Balance(msg, partitions...); first != partition {
+			t.Fatalf("key %q routed to %d then %d", msg.Key, partition, first)
+		}
+		loads[partition]++
+	}
+
+	mean := float64(keyCount) / float64(len(partitions))
+	for _, partition := range partitions {
+		if load := float64(loads[partition]); load < 0.8*mean || load > 1.2*mean {
+			t.Errorf("partition %d got %.0f keys, want within 20%% of %.0f", partition, load, mean)
+		}
+	}
+}
+
+func TestConsistentHashBalancerMapsToGivenPartitions(t *testing.T) {
+	balancer := NewConsistentHashBalancer(0)
+
+	if got := balancer.Balance(kafka.Message{Key: []byte("key")}); got != 0 {
+		t.Errorf("Balance() with no partitions = %d, want 0", got)
+	}
+
+	// Partition IDs need not be contiguous; the ring indexes into the list.
+	partitions := []int{3, 7, 11}
+	for _, msg := range balancerTestMessages(1000) {
+		got := balancer.Balance(msg, partitions...)
+		if got != 3 && got != 7 && got != 11 {
+			t.Fatalf("Balance(%q) = %d, want one of %v", msg.Key, got, partitions)
+		}
+	}
+}
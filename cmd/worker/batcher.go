@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+type BatcherConfig struct {
+	BatchSize          int
+	BatchTimeout       time.Duration
+	MaxPendingMetrics  int
+	PartitionByCluster bool
+}
+
+type MetricBatcher struct {
+	config    BatcherConfig
+	writer    *kafka.Writer
+	batches   chan []*metrics.MetricPoint
+	pending   []*metrics.MetricPoint
+	lastFlush time.Time
+	mutex     sync.Mutex
+	done      chan struct{}
+
+	sentMetrics   uint64
+	failedMetrics uint64
+	sentBatches   uint64
+}
+
+func NewMetricBatcher(writer *kafka.Writer, config BatcherConfig) *MetricBatcher {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.BatchTimeout <= 0 {
+		config.BatchTimeout = 100 * time.Millisecond
+	}
+	if config.MaxPendingMetrics < config.BatchSize {
+		config.MaxPendingMetrics = config.BatchSize * 10
+	}
+
+	return &MetricBatcher{
+		config:    config,
+		writer:    writer,
+		batches:   make(chan []*metrics.MetricPoint, config.MaxPendingMetrics/config.BatchSize),
+		pending:   make([]*metrics.MetricPoint, 0, config.BatchSize),
+		lastFlush: time.Now(),
+		done:      make(chan struct{}),
+	}
+}
+
+func (b *MetricBatcher) Start(ctx context.Context) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.config.BatchTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case batch, ok := <-b.batches:
+			if !ok {
+				return
+			}
+			b.write(ctx, batch)
+		case <-ticker.C:
+			if batch := b.takeStale(); len(batch) > 0 {
+				b.write(ctx, batch)
+			}
+		}
+	}
+}
+
+func (b *MetricBatcher) Add(ctx context.Context, metric *metrics.MetricPoint) error {
+	b.mutex.Lock()
+	b.pending = append(b.pending, metric)
+	if len(b.pending) < b.config.BatchSize {
+		b.mutex.Unlock()
+		return nil
+	}
+
+	batch := b.pending
+	b.pending = make([]*metrics.MetricPoint, 0, b.config.BatchSize)
+	b.lastFlush = time.Now()
+	b.mutex.Unlock()
+
+	select {
+	case b.batches <- batch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *MetricBatcher) Close() {
+	b.mutex.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.mutex.Unlock()
+
+	if len(batch) > 0 {
+		b.batches <- batch
+	}
+	close(b.batches)
+	<-b.done
+
+	slog.Info("Batcher closed",
+		"sent_metrics", atomic.LoadUint64(&b.sentMetrics),
+		"sent_batches", atomic.LoadUint64(&b.sentBatches),
+		"failed_metrics", atomic.LoadUint64(&b.failedMetrics))
+}
+
+func (b *MetricBatcher) takeStale() []*metrics.MetricPoint {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if len(b.pending) == 0 || time.Since(b.lastFlush) < b.config.BatchTimeout {
+		return nil
+	}
+
+	batch := b.pending
+	b.pending = make([]*metrics.MetricPoint, 0, b.config.BatchSize)
+	b.lastFlush = time.Now()
+	return batch
+}
+
+func (b *MetricBatcher) write(ctx context.Context, batch []*metrics.MetricPoint) {
+	messages := make([]kafka.Message, 0, len(batch))
+	for _, metric := range batch {
+		message, err := encodeMetric(metric, b.config.PartitionByCluster)
+		if err != nil {
+			slog.Warn("Dropping metric", "error", err)
+			atomic.AddUint64(&b.failedMetrics, 1)
+			continue
+		}
+		messages = append(messages, message)
+	}
+
+	writeCtx := ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		writeCtx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+	}
+
+	if err := b.writer.WriteMessages(writeCtx, messages...); err != nil {
+		slog.Error("Error sending batch", "size", len(messages), "error", err)
+		atomic.AddUint64(&b.failedMetrics, uint64(len(messages)))
+		return
+	}
+
+	atomic.AddUint64(&b.sentMetrics, uint64(len(messages)))
+	atomic.AddUint64(&b.sentBatches, 1)
+}
+
+func encodeMetric(metric *metrics.MetricPoint, partitionByCluster bool) (kafka.Message, error) {
+	data, err := json.Marshal(metric)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("failed to marshal metric: %v", err)
+	}
+
+	key := metric.GetKey()
+	if partitionByCluster {
+		key = metric.ClusterID
+	}
+
+	return kafka.Message{
+		Key:   []byte(key),
+		Value: data,
+		Time:  metric.Timestamp,
+	}, nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go/protocol"
+	metadataAPI "github.com/segmentio/kafka-go/protocol/metadata"
+	produceAPI "github.com/segmentio/kafka-go/protocol/produce"
+)
+
+// discardTransport acknowledges every produce request without touching the
+// network, so the benchmarks measure the generator and writer overhead only.
+type discardTransport struct{}
+
+func (discardTransport) RoundTrip(ctx context.Context, addr net.Addr, req protocol.Message) (protocol.Message, error) {
+	switch req := req.(type) {
+	case *metadataAPI.Request:
+		topics := make([]metadataAPI.ResponseTopic, len(req.TopicNames))
+		for i, name := range req.TopicNames {
+			topics[i] = metadataAPI.ResponseTopic{
+				Name:       name,
+				Partitions: []metadataAPI.ResponsePartition{{PartitionIndex: 0}},
+			}
+		}
+		return &metadataAPI.Response{Topics: topics}, nil
+	case *produceAPI.Request:
+		topics := make([]produceAPI.ResponseTopic, len(req.Topics))
+		for i, topic := range req.Topics {
+			topics[i] = produceAPI.ResponseTopic{
+				Topic:      topic.Topic,
+				Partitions: []produceAPI.ResponsePartition{{Partition: topic.Partitions[0].Partition}},
+			}
+		}
+		return &produceAPI.Response{Topics: topics}, nil
+	}
+	return nil, protocol.ErrNoRecord
+}
+
+func newBenchGenerator(b *testing.B) *MockDataGenerator {
+	b.Helper()
+
+	return newBenchGeneratorWithBatchSize(b, 1)
+}
+
+func newBenchGeneratorWithBatchSize(b *testing.B, batchSize int) *MockDataGenerator {
+	b.Helper()
+
+	g := NewMockDataGenerator(Config{
+		KafkaBrokers:   []string{"localhost:9092"},
+		ClusterCount:   3,
+		NamespaceCount: 5,
+		PodCount:       20,
+		BatchSize:      batchSize,
+		BatchTimeout:   100 * time.Millisecond,
+	})
+	g.writer.Transport = discardTransport{}
+	b.Cleanup(func() { g.writer.Close() })
+	return g
+}
+
+func BenchmarkGenerateRandomMetric(b *testing.B) {
+	g := newBenchGenerator(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		g.generateRandomMetric()
+	}
+}
+
+func BenchmarkSendMetric(b *testing.B) {
+	g := newBenchGenerator(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := g.sendMetric(ctx, g.generateRandomMetric()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSendMetricParallel(b *testing.B) {
+	g := newBenchGenerator(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := g.sendMetric(ctx, g.generateRandomMetric()); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkConsistentHashBalancer(b *testing.B) {
+	messages := balancerTestMessages(100_000)
+	partitions := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	balancer := NewConsistentHashBalancer(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		balancer.Balance(messages[i%len(messages)], partitions...)
+	}
+}
+
+// BenchmarkWorkerThroughput compares one WriteMessages call per metric, as the
+// generator did before MetricBatcher, against queueing through the batcher.
+// Each iteration is one metric; metrics/s includes the final flush.
+func BenchmarkWorkerThroughput(b *testing.B) {
+	b.Run("per_metric", func(b *testing.B) {
+		g := newBenchGenerator(b)
+		ctx := context.Background()
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if err := g.sendMetric(ctx, g.generateRandomMetric()); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.StopTimer()
+		b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "metrics/s")
+	})
+
+	for _, batchSize := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("batcher/size=%d", batchSize), func(b *testing.B) {
+			g := newBenchGeneratorWithBatchSize(b, batchSize)
+			ctx := context.Background()
+			batcher := NewMetricBatcher(g.writer, g.batcherConfig)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			go batcher.Start(ctx)
+			for i := 0; i < b.N; i++ {
+				if err := batcher.Add(ctx, g.generateRandomMetric()); err != nil {
+					b.Fatal(err)
+				}
+			}
+			batcher.Close()
+			b.StopTimer()
+
+			if sent := atomic.LoadUint64(&batcher.sentMetrics); sent != uint64(b.N) {
+				b.Fatalf("batcher sent %d metrics, want %d", sent, b.N)
+			}
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "metrics/s")
+		})
+	}
+}
@@ -2,25 +2,30 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/segmentio/kafka-go"
 
+	"github.com/asmit27rai/kubesight/internal/logging"
+	"github.com/asmit27rai/kubesight/internal/stream"
 	"github.com/asmit27rai/kubesight/pkg/metrics"
 )
 
 type MockDataGenerator struct {
 	kafkaBrokers   []string
 	writer         *kafka.Writer
+	batcherConfig  BatcherConfig
 	generationRate int
+	namespaceRates map[string]float64
 	clusterCount   int
 	namespaceCount int
 	podCount       int
@@ -30,10 +35,17 @@ type MockDataGenerator struct {
 	pods        []string
 	containers  []string
 	metricNames []string
+	podNodes    map[string]string
+
+	simulator *SimulatedCluster
 }
 
 func main() {
-	log.Println("Starting KubeSight Mock Data Generator...")
+	if err := logging.Setup(os.Stderr, os.Getenv("LOG_LEVEL")); err != nil {
+		slog.Warn("Falling back to info log level", "error", err)
+	}
+
+	slog.Info("Starting KubeSight Mock Data Generator")
 
 	config := parseConfig()
 
@@ -47,7 +59,7 @@ func main() {
 
 	go func() {
 		<-c
-		log.Println("Shutting down mock data generator...")
+		slog.Info("Shutting down mock data generator")
 		cancel()
 	}()
 
@@ -61,17 +73,44 @@ func main() {
 		generator.StartGenerating(ctx)
 	case "burst":
 		generator.GenerateBurst(ctx, 10000)
+	case "simulate":
+		flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+		timeScale := flags.String("time-scale", config.TimeScale, "Simulated time speed, e.g. 100x")
+		addr := flags.String("addr", config.SimulationAddr, "Address for the simulation clock endpoint")
+		flags.Parse(os.Args[2:])
+
+		scale, err := ParseTimeScale(*timeScale)
+		if err != nil {
+			slog.Error("Invalid simulation time scale", "error", err)
+			os.Exit(1)
+		}
+
+		generator.simulator = NewSimulatedCluster(scale)
+		go serveSimulationClock(ctx, *addr, generator.simulator)
+		generator.StartGenerating(ctx)
 	default:
-		log.Fatalf("Unknown command: %s. Use 'generate' or 'burst'", command)
+		slog.Error("Unknown command, use 'generate', 'burst' or 'simulate'", "command", command)
+		os.Exit(1)
 	}
 }
 
 type Config struct {
 	KafkaBrokers   []string
 	GenerationRate int
+	NamespaceRates map[string]float64
 	ClusterCount   int
 	NamespaceCount int
 	PodCount       int
+
+	BatchSize         int
+	BatchTimeout      time.Duration
+	MaxPendingMetrics int
+
+	TimeScale      string
+	SimulationAddr string
+
+	VirtualNodes       int
+	PartitionByCluster bool
 }
 
 func parseConfig() Config {
@@ -81,6 +120,15 @@ func parseConfig() Config {
 		ClusterCount:   3,
 		NamespaceCount: 5,
 		PodCount:       20,
+
+		BatchSize:         100,
+		BatchTimeout:      100 * time.Millisecond,
+		MaxPendingMetrics: 10000,
+
+		TimeScale:      "1x",
+		SimulationAddr: ":8081",
+
+		VirtualNodes: stream.DefaultVirtualNodes,
 	}
 
 	if brokers := os.Getenv("KAFKA_BROKERS"); brokers != "" {
@@ -93,6 +141,10 @@ func parseConfig() Config {
 		}
 	}
 
+	if rates := os.Getenv("NAMESPACE_RATES"); rates != "" {
+		config.NamespaceRates = parseNamespaceRates(rates)
+	}
+
 	if clusters := os.Getenv("CLUSTER_COUNT"); clusters != "" {
 		if c, err := strconv.Atoi(clusters); err == nil {
 			config.ClusterCount = c
@@ -111,24 +163,91 @@ func parseConfig() Config {
 		}
 	}
 
+	if batchSize := os.Getenv("BATCH_SIZE"); batchSize != "" {
+		if b, err := strconv.Atoi(batchSize); err == nil {
+			config.BatchSize = b
+		}
+	}
+
+	if batchTimeout := os.Getenv("BATCH_TIMEOUT_MS"); batchTimeout != "" {
+		if t, err := strconv.Atoi(batchTimeout); err == nil {
+			config.BatchTimeout = time.Duration(t) * time.Millisecond
+		}
+	}
+
+	if maxPending := os.Getenv("MAX_PENDING_METRICS"); maxPending != "" {
+		if m, err := strconv.Atoi(maxPending); err == nil {
+			config.MaxPendingMetrics = m
+		}
+	}
+
+	if timeScale := os.Getenv("SIMULATION_TIME_SCALE"); timeScale != "" {
+		config.TimeScale = timeScale
+	}
+
+	if addr := os.Getenv("SIMULATION_ADDR"); addr != "" {
+		config.SimulationAddr = addr
+	}
+
+	if virtualNodes := os.Getenv("HASH_VIRTUAL_NODES"); virtualNodes != "" {
+		if v, err := strconv.Atoi(virtualNodes); err == nil {
+			config.VirtualNodes = v
+		}
+	}
+
+	if partitionByCluster := os.Getenv("PARTITION_BY_CLUSTER"); partitionByCluster != "" {
+		if p, err := strconv.ParseBool(partitionByCluster); err == nil {
+			config.PartitionByCluster = p
+		}
+	}
+
 	return config
 }
 
+func parseNamespaceRates(value string) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, entry := range strings.Split(value, ",") {
+		namespace, multiplier, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || namespace == "" {
+			slog.Warn("Ignoring malformed namespace rate", "entry", entry)
+			continue
+		}
+		m, err := strconv.ParseFloat(multiplier, 64)
+		if err != nil || m < 0 {
+			slog.Warn("Ignoring invalid namespace rate multiplier", "namespace", namespace, "multiplier", multiplier)
+			continue
+		}
+		rates[namespace] = m
+	}
+	return rates
+}
+
 func NewMockDataGenerator(config Config) *MockDataGenerator {
+	var balancer kafka.Balancer = NewConsistentHashBalancer(config.VirtualNodes)
+	if config.PartitionByCluster {
+		balancer = &kafka.Hash{}
+	}
+
 	writer := &kafka.Writer{
 		Addr:         kafka.TCP(config.KafkaBrokers...),
 		Topic:        "k8s-metrics",
-		Balancer:     &kafka.LeastBytes{},
+		Balancer:     balancer,
 		RequiredAcks: kafka.RequireOne,
-		Async:        true,
 		BatchTimeout: 10 * time.Millisecond,
-		BatchSize:    100,
+		BatchSize:    config.BatchSize,
 	}
 
 	generator := &MockDataGenerator{
 		kafkaBrokers:   config.KafkaBrokers,
 		writer:         writer,
 		generationRate: config.GenerationRate,
+		namespaceRates: config.NamespaceRates,
+		batcherConfig: BatcherConfig{
+			BatchSize:          config.BatchSize,
+			BatchTimeout:       config.BatchTimeout,
+			MaxPendingMetrics:  config.MaxPendingMetrics,
+			PartitionByCluster: config.PartitionByCluster,
+		},
 		clusterCount:   config.ClusterCount,
 		namespaceCount: config.NamespaceCount,
 		podCount:       config.PodCount,
@@ -152,8 +271,12 @@ func (g *MockDataGenerator) initializeTemplates() {
 		g.namespaces = append(g.namespaces, fmt.Sprintf("namespace-%d", i+1))
 	}
 
+	nodeCount := g.podCount/5 + 1
+	g.podNodes = make(map[string]string, g.podCount)
 	for i := 0; i < g.podCount; i++ {
-		g.pods = append(g.pods, fmt.Sprintf("pod-%d", i+1))
+		pod := fmt.Sprintf("pod-%d", i+1)
+		g.pods = append(g.pods, pod)
+		g.podNodes[pod] = fmt.Sprintf("node-%d", i%nodeCount+1)
 	}
 
 	g.containers = []string{"main", "sidecar", "proxy", "init", "worker"}
@@ -176,16 +299,30 @@ func (g *MockDataGenerator) initializeTemplates() {
 		"network_packets_out",
 	}
 
-	log.Printf("Initialized templates: %d clusters, %d namespaces, %d pods, %d metrics",
-		len(g.clusters), len(g.namespaces), len(g.pods), len(g.metricNames))
+	slog.Info("Initialized templates",
+		"clusters", len(g.clusters),
+		"namespaces", len(g.namespaces),
+		"pods", len(g.pods),
+		"metrics", len(g.metricNames))
 }
 
 func (g *MockDataGenerator) StartGenerating(ctx context.Context) {
-	log.Printf("Starting continuous data generation at %d metrics/second", g.generationRate)
-
-	interval := time.Second / time.Duration(g.generationRate)
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	slog.Info("Starting continuous data generation",
+		"rate", g.generationRate,
+		"batch_size", g.batcherConfig.BatchSize)
+
+	batcher := NewMetricBatcher(g.writer, g.batcherConfig)
+	go batcher.Start(ctx)
+
+	ticks := make(chan string)
+	for _, namespace := range g.namespaces {
+		interval, enabled := g.namespaceInterval(namespace)
+		if !enabled {
+			slog.Info("Namespace generation disabled", "namespace", namespace)
+			continue
+		}
+		go g.tickNamespace(ctx, namespace, interval, ticks)
+	}
 
 	count := 0
 	start := time.Now()
@@ -193,40 +330,77 @@ func (g *MockDataGenerator) StartGenerating(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
-			log.Printf("Generated %d total metrics in %v", count, time.Since(start))
+			batcher.Close()
+			slog.Info("Generation stopped", "total", count, "elapsed", time.Since(start))
 			g.writer.Close()
 			return
 
-		case <-ticker.C:
-			metric := g.generateRandomMetric()
-			if err := g.sendMetric(ctx, metric); err != nil {
-				log.Printf("Error sending metric: %v", err)
+		case namespace := <-ticks:
+			metric := g.generateMetric(namespace)
+			if err := batcher.Add(ctx, metric); err != nil {
+				slog.Error("Error queueing metric", "error", err)
 			} else {
 				count++
 
 				if count%1000 == 0 {
 					elapsed := time.Since(start)
 					rate := float64(count) / elapsed.Seconds()
-					log.Printf("Generated %d metrics (%.1f/sec)", count, rate)
+					slog.Info("Generation progress", "total", count, "rate", rate)
 				}
 			}
 		}
 	}
 }
 
+func (g *MockDataGenerator) namespaceInterval(namespace string) (time.Duration, bool) {
+	multiplier, exists := g.namespaceRates[namespace]
+	if !exists {
+		multiplier = 1
+	}
+
+	rate := float64(g.generationRate) / float64(len(g.namespaces)) * multiplier
+	if rate <= 0 {
+		return 0, false
+	}
+
+	interval := time.Duration(float64(time.Second) / rate)
+	if interval <= 0 {
+		interval = time.Nanosecond
+	}
+	return interval, true
+}
+
+func (g *MockDataGenerator) tickNamespace(ctx context.Context, namespace string, interval time.Duration, ticks chan<- string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			select {
+			case ticks <- namespace:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
 func (g *MockDataGenerator) GenerateBurst(ctx context.Context, burstSize int) {
-	log.Printf("💥 Generating burst of %d metrics...", burstSize)
+	slog.Info("Generating burst", "size", burstSize)
 
 	start := time.Now()
 
 	for i := 0; i < burstSize; i++ {
 		metric := g.generateRandomMetric()
 		if err := g.sendMetric(ctx, metric); err != nil {
-			log.Printf("Error sending metric %d: %v", i, err)
+			slog.Error("Error sending metric", "index", i, "error", err)
 		}
 
 		if (i+1)%1000 == 0 {
-			log.Printf("📊 Burst progress: %d/%d metrics", i+1, burstSize)
+			slog.Debug("Burst progress", "sent", i+1, "total", burstSize)
 		}
 
 		if i%100 == 0 {
@@ -236,16 +410,19 @@ func (g *MockDataGenerator) GenerateBurst(ctx context.Context, burstSize int) {
 
 	elapsed := time.Since(start)
 	rate := float64(burstSize) / elapsed.Seconds()
-	log.Printf("Burst complete: %d metrics in %v (%.1f/sec)", burstSize, elapsed, rate)
+	slog.Info("Burst complete", "size", burstSize, "elapsed", elapsed, "rate", rate)
 
 	g.writer.Close()
 }
 
 func (g *MockDataGenerator) generateRandomMetric() *metrics.MetricPoint {
+	return g.generateMetric(g.namespaces[rand.Intn(len(g.namespaces))])
+}
+
+func (g *MockDataGenerator) generateMetric(namespace string) *metrics.MetricPoint {
 	now := time.Now()
 
 	cluster := g.clusters[rand.Intn(len(g.clusters))]
-	namespace := g.namespaces[rand.Intn(len(g.namespaces))]
 	pod := g.pods[rand.Intn(len(g.pods))]
 	container := g.containers[rand.Intn(len(g.containers))]
 	metricName := g.metricNames[rand.Intn(len(g.metricNames))]
@@ -303,6 +480,14 @@ func (g *MockDataGenerator) generateRandomMetric() *metrics.MetricPoint {
 		"version":   "v1.0.0",
 	}
 
+	if g.simulator != nil {
+		if simulated, ok := g.simulator.Value(pod, metricName); ok {
+			value = simulated
+		}
+		labels["source"] = "simulator"
+		labels["sim_time"] = g.simulator.Now().Format(time.RFC3339)
+	}
+
 	if rand.Float32() < 0.1 {
 		labels["anomaly"] = "possible"
 	}
@@ -317,6 +502,7 @@ func (g *MockDataGenerator) generateRandomMetric() *metrics.MetricPoint {
 		Namespace:     namespace,
 		PodName:       pod,
 		ContainerName: container,
+		NodeName:      g.podNodes[pod],
 		MetricName:    metricName,
 		Value:         value,
 		Unit:          unit,
@@ -325,22 +511,16 @@ func (g *MockDataGenerator) generateRandomMetric() *metrics.MetricPoint {
 }
 
 func (g *MockDataGenerator) sendMetric(ctx context.Context, metric *metrics.MetricPoint) error {
-	data, err := json.Marshal(metric)
+	message, err := encodeMetric(metric, g.batcherConfig.PartitionByCluster)
 	if err != nil {
-		return fmt.Errorf("failed to marshal metric: %v", err)
-	}
-
-	message := kafka.Message{
-		Key:   []byte(metric.GetKey()),
-		Value: data,
-		Time:  metric.Timestamp,
+		return err
 	}
 
 	return g.writer.WriteMessages(ctx, message)
 }
 
 func (g *MockDataGenerator) GenerateSpecificScenario(ctx context.Context, scenario string) {
-	log.Printf("Generating scenario: %s", scenario)
+	slog.Info("Generating scenario", "scenario", scenario)
 
 	var metrics []*metrics.MetricPoint
 
@@ -377,9 +557,9 @@ func (g *MockDataGenerator) GenerateSpecificScenario(ctx context.Context, scenar
 
 	for _, metric := range metrics {
 		if err := g.sendMetric(ctx, metric); err != nil {
-			log.Printf("Error sending scenario metric: %v", err)
+			slog.Error("Error sending scenario metric", "scenario", scenario, "error", err)
 		}
 	}
 
-	log.Printf("Scenario '%s' complete: sent %d metrics", scenario, len(metrics))
+	slog.Info("Scenario complete", "scenario", scenario, "sent", len(metrics))
 }
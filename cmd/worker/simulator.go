@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	simulationIdleCPUMean    = 0.05
+	simulationActiveCPUMean  = 0.60
+	simulationMemoryLimit    = 0.95
+	simulationNetworkLogMean = 12.0
+	simulationNetworkLogStd  = 0.8
+)
+
+type SimulatedCluster struct {
+	timeScale float64
+	realStart time.Time
+	simStart  time.Time
+	pods      map[string]*simulatedPod
+	rng       *rand.Rand
+	mutex     sync.Mutex
+}
+
+type simulatedPod struct {
+	memoryBase   float64
+	memoryGrowth float64
+	lastRestart  time.Time
+	restarts     int
+}
+
+type SimulationClock struct {
+	SimTime      time.Time `json:"sim_time"`
+	Day          int       `json:"day"`
+	Hour         float64   `json:"hour"`
+	TimeScale    float64   `json:"time_scale"`
+	DiurnalBias  float64   `json:"diurnal_bias"`
+	BusinessHour bool      `json:"business_hour"`
+}
+
+func NewSimulatedCluster(timeScale float64) *SimulatedCluster {
+	if timeScale <= 0 {
+		timeScale = 1
+	}
+
+	now := time.Now()
+	return &SimulatedCluster{
+		timeScale: timeScale,
+		realStart: now,
+		simStart:  time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+		pods:      make(map[string]*simulatedPod),
+		rng:       rand.New(rand.NewSource(now.UnixNano())),
+	}
+}
+
+func ParseTimeScale(value string) (float64, error) {
+	scale, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "x"), 64)
+	if err != nil || scale <= 0 {
+		return 0, fmt.Errorf("invalid time scale %q: expected a positive factor such as 100x", value)
+	}
+	return scale, nil
+}
+
+func (sc *SimulatedCluster) Now() time.Time {
+	elapsed := time.Since(sc.realStart)
+	return sc.simStart.Add(time.Duration(float64(elapsed) * sc.timeScale))
+}
+
+func (sc *SimulatedCluster) Clock() SimulationClock {
+	now := sc.Now()
+	hour := simulationHour(now)
+
+	return SimulationClock{
+		SimTime:      now,
+		Day:          int(now.Sub(sc.simStart)/(24*time.Hour)) + 1,
+		Hour:         hour,
+		TimeScale:    sc.timeScale,
+		DiurnalBias:  diurnalBias(hour),
+		BusinessHour: hour >= 9 && hour < 18,
+	}
+}
+
+func (sc *SimulatedCluster) Value(pod, metricName string) (float64, bool) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	now := sc.Now()
+	bias := diurnalBias(simulationHour(now))
+
+	switch metricName {
+	case "cpu_usage":
+		return sc.cpuUsage(bias), true
+	case "memory_usage":
+		return sc.memoryUsage(sc.pod(pod, now), now), true
+	case "pod_restarts":
+		return float64(sc.pod(pod, now).restarts), true
+	case "network_in", "network_out":
+		return sc.networkBytes(bias), true
+	default:
+		return 0, false
+	}
+}
+
+func (sc *SimulatedCluster) cpuUsage(bias float64) float64 {
+	activeProbability := math.Min(0.2+0.6*(bias-0.5), 0.9)
+
+	var value float64
+	if sc.rng.Float64() < activeProbability {
+		value = simulationActiveCPUMean + sc.rng.NormFloat64()*0.1
+	} else {
+		value = simulationIdleCPUMean + sc.rng.NormFloat64()*0.02
+	}
+	return math.Min(math.Max(value, 0), 1)
+}
+
+func (sc *SimulatedCluster) memoryUsage(state *simulatedPod, now time.Time) float64 {
+	value := state.memoryBase + state.memoryGrowth*now.Sub(state.lastRestart).Hours()
+	if value >= simulationMemoryLimit {
+		state.lastRestart = now
+		state.restarts++
+		value = state.memoryBase
+	}
+	return value
+}
+
+func (sc *SimulatedCluster) networkBytes(bias float64) float64 {
+	return math.Exp(simulationNetworkLogMean+sc.rng.NormFloat64()*simulationNetworkLogStd) * bias
+}
+
+func (sc *SimulatedCluster) pod(name string, now time.Time) *simulatedPod {
+	if state, exists := sc.pods[name]; exists {
+		return state
+	}
+
+	state := &simulatedPod{
+		memoryBase:   0.2 + sc.rng.Float64()*0.2,
+		memoryGrowth: 0.01 + sc.rng.Float64()*0.04,
+		lastRestart:  now,
+	}
+	sc.pods[name] = state
+	return state
+}
+
+func simulationHour(t time.Time) float64 {
+	return float64(t.Hour()) + float64(t.Minute())/60 + float64(t.Second())/3600
+}
+
+func diurnalBias(hour float64) float64 {
+	bias := 1 + 0.5*math.Sin(2*math.Pi*(hour-7.5)/24)
+	if hour >= 9 && hour < 18 {
+		bias += 0.25
+	}
+	return bias
+}
+
+func serveSimulationClock(ctx context.Context, addr string, cluster *SimulatedCluster) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/simulation/time", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(cluster.Clock()); err != nil {
+			slog.Error("Failed to encode simulation clock", "error", err)
+		}
+	}).Methods("GET")
+
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      router,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Simulation clock available", "url", fmt.Sprintf("http://%s/api/v1/simulation/time", addr))
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		slog.Error("Simulation clock server failed", "error", err)
+	}
+}
@@ -0,0 +1,213 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	NotifierSlack     = "slack"
+	NotifierPagerDuty = "pagerduty"
+	NotifierWebhook   = "webhook"
+
+	pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+	notifyTimeout      = 10 * time.Second
+	notifyMaxAttempts  = 3
+)
+
+var notifyRetryBackoff = 500 * time.Millisecond
+
+type AlertRule struct {
+	Name           string            `json:"name"`
+	MetricName     string            `json:"metric_name"`
+	Threshold      float64           `json:"threshold"`
+	Severity       string            `json:"severity"`
+	NotifierType   string            `json:"notifier_type"`
+	NotifierConfig map[string]string `json:"notifier_config,omitempty"`
+}
+
+type WebhookAlert struct {
+	RuleName   string            `json:"rule_name"`
+	MetricName string            `json:"metric_name"`
+	Value      float64           `json:"value"`
+	Threshold  float64           `json:"threshold"`
+	Severity   string            `json:"severity"`
+	Message    string            `json:"message"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	FiredAt    time.Time         `json:"fired_at"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, alert WebhookAlert) error
+}
+
+func NewNotifier(rule AlertRule) (Notifier, error) {
+	switch rule.NotifierType {
+	case NotifierSlack:
+		webhookURL := rule.NotifierConfig["webhook_url"]
+		if webhookURL == "" {
+			return nil, fmt.Errorf("alert rule %s: slack notifier requires webhook_url", rule.Name)
+		}
+		return &SlackNotifier{
+			WebhookURL: webhookURL,
+			Channel:    rule.NotifierConfig["channel"],
+			Username:   rule.NotifierConfig["username"],
+		}, nil
+	case NotifierPagerDuty:
+		routingKey := rule.NotifierConfig["routing_key"]
+		if routingKey == "" {
+			return nil, fmt.Errorf("alert rule %s: pagerduty notifier requires routing_key", rule.Name)
+		}
+		return &PagerDutyNotifier{
+			RoutingKey: routingKey,
+			EventsURL:  rule.NotifierConfig["events_url"],
+		}, nil
+	case NotifierWebhook:
+		webhookURL := rule.NotifierConfig["url"]
+		if webhookURL == "" {
+			return nil, fmt.Errorf("alert rule %s: webhook notifier requires url", rule.Name)
+		}
+		return &WebhookNotifier{URL: webhookURL}, nil
+	default:
+		return nil, fmt.Errorf("alert rule %s: unknown notifier type: %s", rule.Name, rule.NotifierType)
+	}
+}
+
+type SlackNotifier struct {
+	WebhookURL string
+	Channel    string
+	Username   string
+	HTTPClient *http.Client
+}
+
+func (sn *SlackNotifier) Notify(ctx context.Context, alert WebhookAlert) error {
+	payload := map[string]interface{}{
+		"text": fmt.Sprintf(":rotating_light: *[%s] %s*\n%s\n`%s` = %.4f (threshold %.4f) at %s",
+			alert.Severity, alert.RuleName, alert.Message, alert.MetricName,
+			alert.Value, alert.Threshold, alert.FiredAt.Format(time.RFC3339)),
+	}
+	if sn.Channel != "" {
+		payload["channel"] = sn.Channel
+	}
+	if sn.Username != "" {
+		payload["username"] = sn.Username
+	}
+
+	return postJSON(ctx, sn.HTTPClient, sn.WebhookURL, payload)
+}
+
+type PagerDutyNotifier struct {
+	RoutingKey string
+	EventsURL  string
+	HTTPClient *http.Client
+}
+
+func (pn *PagerDutyNotifier) Notify(ctx context.Context, alert WebhookAlert) error {
+	eventsURL := pn.EventsURL
+	if eventsURL == "" {
+		eventsURL = pagerDutyEventsURL
+	}
+
+	payload := map[string]interface{}{
+		"routing_key":  pn.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    alert.RuleName + "/" + alert.MetricName,
+		"payload": map[string]interface{}{
+			"summary":   fmt.Sprintf("%s: %s", alert.RuleName, alert.Message),
+			"source":    "kubesight",
+			"severity":  pagerDutySeverity(alert.Severity),
+			"timestamp": alert.FiredAt.Format(time.RFC3339),
+			"custom_details": map[string]interface{}{
+				"metric_name": alert.MetricName,
+				"value":       alert.Value,
+				"threshold":   alert.Threshold,
+				"labels":      alert.Labels,
+			},
+		},
+	}
+
+	return postJSON(ctx, pn.HTTPClient, eventsURL, payload)
+}
+
+type WebhookNotifier struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+func (wn *WebhookNotifier) Notify(ctx context.Context, alert WebhookAlert) error {
+	return postJSON(ctx, wn.HTTPClient, wn.URL, alert)
+}
+
+func pagerDutySeverity(severity string) string {
+	switch severity {
+	case "critical", "error", "warning", "info":
+		return severity
+	default:
+		return "error"
+	}
+}
+
+func AnomalySeverity(class string) string {
+	switch class {
+	case "shift":
+		return "critical"
+	case "drift":
+		return "error"
+	case "spike":
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %v", err)
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: notifyTimeout}
+	}
+
+	for attempt := 1; ; attempt++ {
+		retryable, err := sendJSON(ctx, client, url, data)
+		if err == nil || !retryable || attempt == notifyMaxAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%v (retry cancelled: %v)", err, ctx.Err())
+		case <-time.After(notifyRetryBackoff * time.Duration(attempt)):
+		}
+	}
+}
+
+// sendJSON makes a single delivery attempt. Transport errors, 429 and 5xx
+// responses are retryable; other rejections are not.
+func sendJSON(ctx context.Context, client *http.Client, url string, data []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return false, fmt.Errorf("failed to build notification request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("failed to deliver notification: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, fmt.Errorf("notification rejected with status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return false, nil
+}
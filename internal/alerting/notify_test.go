@@ -0,0 +1,196 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testAlert = WebhookAlert{
+	RuleName:   "high_cpu",
+	MetricName: "cpu_usage",
+	Value:      0.97,
+	Threshold:  0.9,
+	Severity:   "critical",
+	Message:    "CPU usage above 90%",
+	FiredAt:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func init() {
+	notifyRetryBackoff = time.Millisecond
+}
+
+// mockServer answers with the given status codes in order, repeating the
+// last one, and records each request body.
+func mockServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32, chan map[string]interface{}) {
+	t.Helper()
+
+	var calls atomic.Int32
+	bodies := make(chan map[string]interface{}, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", got)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid notification body: %v", err)
+		}
+		bodies <- body
+
+		status := statuses[len(statuses)-1]
+		if n <= len(statuses) {
+			status = statuses[n-1]
+		}
+		w.WriteHeader(status)
+		w.Write([]byte("mock response"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls, bodies
+}
+
+func TestNotifiersPostToMockServer(t *testing.T) {
+	server, _, bodies := mockServer(t, http.StatusOK)
+
+	tests := []struct {
+		name     string
+		rule     AlertRule
+		validate func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			name: "slack",
+			rule: AlertRule{Name: "high_cpu", NotifierType: NotifierSlack, NotifierConfig: map[string]string{
+				"webhook_url": server.URL, "channel": "#alerts", "username": "kubesight",
+			}},
+			validate: func(t *testing.T, body map[string]interface{}) {
+				text, _ := body["text"].(string)
+				if !strings.Contains(text, "[critical] high_cpu") || !strings.Contains(text, "cpu_usage") {
+					t.Errorf("text = %q, want severity, rule and metric", text)
+				}
+				if body["channel"] != "#alerts" || body["username"] != "kubesight" {
+					t.Errorf("channel/username = %v/%v", body["channel"], body["username"])
+				}
+			},
+		},
+		{
+			name: "pagerduty",
+			rule: AlertRule{Name: "high_cpu", NotifierType: NotifierPagerDuty, NotifierConfig: map[string]string{
+				"routing_key": "rk-123", "events_url": server.URL,
+			}},
+			validate: func(t *testing.T, body map[string]interface{}) {
+				if body["routing_key"] != "rk-123" || body["event_action"] != "trigger" {
+					t.Errorf("routing_key/event_action = %v/%v", body["routing_key"], body["event_action"])
+				}
+				if body["dedup_key"] != "high_cpu/cpu_usage" {
+					t.Errorf("dedup_key = %v, want high_cpu/cpu_usage", body["dedup_key"])
+				}
+				payload, _ := body["payload"].(map[string]interface{})
+				if payload["severity"] != "critical" {
+					t.Errorf("payload severity = %v, want critical", payload["severity"])
+				}
+			},
+		},
+		{
+			name: "webhook",
+			rule: AlertRule{Name: "high_cpu", NotifierType: NotifierWebhook, NotifierConfig: map[string]string{"url": server.URL}},
+			validate: func(t *testing.T, body map[string]interface{}) {
+				if body["rule_name"] != "high_cpu" || body["value"] != 0.97 {
+					t.Errorf("body = %v, want the alert as JSON", body)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier, err := NewNotifier(tt.rule)
+			if err != nil {
+				t.Fatalf("NewNotifier() error = %v", err)
+			}
+			if err := notifier.Notify(context.Background(), testAlert); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			tt.validate(t, <-bodies)
+		})
+	}
+}
+
+func TestNotifyRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantErr   bool
+		wantCalls int32
+	}{
+		{name: "success", statuses: []int{http.StatusNoContent}, wantCalls: 1},
+		{name: "recovers after server error", statuses: []int{http.StatusServiceUnavailable, http.StatusOK}, wantCalls: 2},
+		{name: "recovers after rate limit", statuses: []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusOK}, wantCalls: 3},
+		{name: "gives up after max attempts", statuses: []int{http.StatusBadGateway}, wantErr: true, wantCalls: notifyMaxAttempts},
+		{name: "client error is not retried", statuses: []int{http.StatusBadRequest}, wantErr: true, wantCalls: 1},
+		{name: "redirect is rejected", statuses: []int{http.StatusNotModified}, wantErr: true, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls, _ := mockServer(t, tt.statuses...)
+
+			err := (&WebhookNotifier{URL: server.URL}).Notify(context.Background(), testAlert)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "status") {
+				t.Errorf("error = %v, want the rejected status", err)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("server saw %d requests, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestNotifyRetryStopsOnCancel(t *testing.T) {
+	server, calls, _ := mockServer(t, http.StatusServiceUnavailable)
+
+	previous := notifyRetryBackoff
+	notifyRetryBackoff = time.Hour
+	defer func() { notifyRetryBackoff = previous }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := (&WebhookNotifier{URL: server.URL}).Notify(ctx, testAlert); err == nil {
+		t.Fatal("Notify() succeeded against a failing server")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server saw %d requests, want 1 before the context expired", got)
+	}
+}
+
+func TestNotifyUnreachableServer(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	if err := (&SlackNotifier{WebhookURL: url}).Notify(context.Background(), testAlert); err == nil {
+		t.Fatal("Notify() succeeded against a closed server")
+	}
+}
+
+func TestNewNotifierValidatesConfig(t *testing.T) {
+	for _, rule := range []AlertRule{
+		{Name: "r", NotifierType: NotifierSlack},
+		{Name: "r", NotifierType: NotifierPagerDuty},
+		{Name: "r", NotifierType: NotifierWebhook},
+		{Name: "r", NotifierType: "email"},
+	} {
+		if _, err := NewNotifier(rule); err == nil {
+			t.Errorf("NewNotifier(%+v) succeeded, want error", rule)
+		}
+	}
+}
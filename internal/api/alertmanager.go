@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/middleware"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	alertStatusResolved = "resolved"
+	alertMetricPrefix   = "k8s_alert_"
+)
+
+type AlertmanagerWebhook struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+func (h *Handler) IngestAlertmanager(w http.ResponseWriter, r *http.Request) {
+	if h.rejectIfDraining(w) {
+		return
+	}
+
+	var webhook AlertmanagerWebhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid Alertmanager payload", err)
+		return
+	}
+
+	firing, resolved, skipped := 0, 0, 0
+	for _, alert := range webhook.Alerts {
+		metric := alertToMetric(alert)
+		if metric == nil {
+			skipped++
+			continue
+		}
+
+		h.queryEngine.ProcessMetric(metric)
+		if metric.Value > 0 {
+			firing++
+		} else {
+			resolved++
+		}
+	}
+
+	middleware.LoggerFromContext(r.Context()).Info("Alertmanager alerts ingested",
+		"receiver", webhook.Receiver,
+		"firing", firing,
+		"resolved", resolved,
+		"skipped", skipped)
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"received": len(webhook.Alerts),
+		"firing":   firing,
+		"resolved": resolved,
+		"skipped":  skipped,
+	})
+}
+
+func alertToMetric(alert AlertmanagerAlert) *metrics.MetricPoint {
+	alertName := alert.Labels["alertname"]
+	if alertName == "" {
+		return nil
+	}
+
+	value := 1.0
+	timestamp := alert.StartsAt
+	if alert.Status == alertStatusResolved {
+		value = 0.0
+		if !alert.EndsAt.IsZero() {
+			timestamp = alert.EndsAt
+		}
+	}
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	labels := make(map[string]string, len(alert.Labels)+1)
+	for key, val := range alert.Labels {
+		switch key {
+		case "cluster", "namespace", "pod", "container", "node":
+			continue
+		}
+		labels[key] = val
+	}
+	labels["alert_status"] = alert.Status
+
+	return &metrics.MetricPoint{
+		Timestamp:     timestamp,
+		ClusterID:     alert.Labels["cluster"],
+		Namespace:     alert.Labels["namespace"],
+		PodName:       alert.Labels["pod"],
+		ContainerName: alert.Labels["container"],
+		NodeName:      alert.Labels["node"],
+		MetricName:    alertMetricPrefix + alertName,
+		Value:         value,
+		Unit:          "state",
+		Labels:        labels,
+	}
+}
@@ -0,0 +1,356 @@
+package api
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/protobuf"
+
+	maxProtoMessageBytes = 4 << 20
+)
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+type Codec interface {
+	ContentType() string
+	Decode(r io.Reader, v interface{}) error
+	Encode(w io.Writer, v interface{}) error
+}
+
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string {
+	return ContentTypeJSON
+}
+
+func (JSONCodec) Decode(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+func (JSONCodec) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+type ProtoCodec struct{}
+
+func (ProtoCodec) ContentType() string {
+	return ContentTypeProtobuf
+}
+
+func (ProtoCodec) Decode(r io.Reader, v interface{}) error {
+	data, err := io.ReadAll(io.LimitReader(r, maxProtoMessageBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxProtoMessageBytes {
+		return fmt.Errorf("protobuf message exceeds %d bytes", maxProtoMessageBytes)
+	}
+
+	switch msg := v.(type) {
+	case *metrics.QueryRequest:
+		return unmarshalQueryRequest(data, msg)
+	case *metrics.QueryResult:
+		return unmarshalQueryResult(data, msg)
+	default:
+		return fmt.Errorf("protobuf decoding not supported for %T", v)
+	}
+}
+
+func (ProtoCodec) Encode(w io.Writer, v interface{}) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch msg := v.(type) {
+	case *metrics.QueryRequest:
+		data = marshalQueryRequest(msg)
+	case *metrics.QueryResult:
+		data, err = marshalQueryResult(msg)
+	default:
+		return fmt.Errorf("protobuf encoding not supported for %T", v)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+func requestCodec(r *http.Request) Codec {
+	if mediaTypeIs(r.Header.Get("Content-Type"), ContentTypeProtobuf) {
+		return ProtoCodec{}
+	}
+	return JSONCodec{}
+}
+
+func responseCodec(r *http.Request) Codec {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaTypeIs(accepted, ContentTypeProtobuf) {
+			return ProtoCodec{}
+		}
+	}
+	return JSONCodec{}
+}
+
+func mediaTypeIs(header, want string) bool {
+	mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(header))
+	return err == nil && mediaType == want
+}
+
+func marshalQueryRequest(req *metrics.QueryRequest) []byte {
+	var buf []byte
+	buf = appendString(buf, 1, req.ID)
+	buf = appendString(buf, 2, req.Query)
+	buf = appendString(buf, 3, string(req.QueryType))
+
+	if !req.TimeRange.Start.IsZero() || !req.TimeRange.End.IsZero() {
+		var timeRange []byte
+		timeRange = appendTime(timeRange, 1, req.TimeRange.Start)
+		timeRange = appendTime(timeRange, 2, req.TimeRange.End)
+		buf = appendBytes(buf, 4, timeRange)
+	}
+
+	keys := make([]string, 0, len(req.Filters))
+	for key := range req.Filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var entry []byte
+		entry = appendString(entry, 1, key)
+		entry = appendString(entry, 2, req.Filters[key])
+		buf = appendBytes(buf, 5, entry)
+	}
+
+	buf = appendDouble(buf, 6, req.ErrorBound)
+	buf = appendDouble(buf, 7, req.Confidence)
+	buf = appendString(buf, 8, req.GroupByLabel)
+	return buf
+}
+
+func unmarshalQueryRequest(data []byte, req *metrics.QueryRequest) error {
+	return walkFields(data, func(field int, wireType int, value uint64, payload []byte) error {
+		switch field {
+		case 1:
+			req.ID = string(payload)
+		case 2:
+			req.Query = string(payload)
+		case 3:
+			req.QueryType = metrics.QueryType(payload)
+		case 4:
+			return walkFields(payload, func(field int, wireType int, value uint64, payload []byte) error {
+				switch field {
+				case 1:
+					req.TimeRange.Start = time.Unix(0, int64(value)).UTC()
+				case 2:
+					req.TimeRange.End = time.Unix(0, int64(value)).UTC()
+				}
+				return nil
+			})
+		case 5:
+			var key, val string
+			err := walkFields(payload, func(field int, wireType int, value uint64, payload []byte) error {
+				switch field {
+				case 1:
+					key = string(payload)
+				case 2:
+					val = string(payload)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if req.Filters == nil {
+				req.Filters = make(map[string]string)
+			}
+			req.Filters[key] = val
+		case 6:
+			req.ErrorBound = math.Float64frombits(value)
+		case 7:
+			req.Confidence = math.Float64frombits(value)
+		case 8:
+			req.GroupByLabel = string(payload)
+		}
+		return nil
+	})
+}
+
+func marshalQueryResult(result *metrics.QueryResult) ([]byte, error) {
+	var buf []byte
+	buf = appendString(buf, 1, result.ID)
+	buf = appendString(buf, 2, result.Query)
+
+	if result.Result != nil {
+		encoded, err := json.Marshal(result.Result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode query result: %v", err)
+		}
+		buf = appendBytes(buf, 3, encoded)
+	}
+
+	if result.Error != nil {
+		buf = appendFixed64(buf, 4, math.Float64bits(*result.Error))
+	}
+	if result.Confidence != nil {
+		buf = appendFixed64(buf, 5, math.Float64bits(*result.Confidence))
+	}
+
+	buf = appendVarint(buf, 6, uint64(result.SampleSize))
+	buf = appendVarint(buf, 7, uint64(result.ProcessingTime))
+	if result.IsApproximate {
+		buf = appendVarint(buf, 8, 1)
+	}
+	buf = appendTime(buf, 9, result.Timestamp)
+	buf = appendDouble(buf, 10, result.QualityScore)
+	return buf, nil
+}
+
+func unmarshalQueryResult(data []byte, result *metrics.QueryResult) error {
+	return walkFields(data, func(field int, wireType int, value uint64, payload []byte) error {
+		switch field {
+		case 1:
+			result.ID = string(payload)
+		case 2:
+			result.Query = string(payload)
+		case 3:
+			result.Result = json.RawMessage(append([]byte(nil), payload...))
+		case 4:
+			errorValue := math.Float64frombits(value)
+			result.Error = &errorValue
+		case 5:
+			confidence := math.Float64frombits(value)
+			result.Confidence = &confidence
+		case 6:
+			result.SampleSize = int(int64(value))
+		case 7:
+			result.ProcessingTime = time.Duration(int64(value))
+		case 8:
+			result.IsApproximate = value != 0
+		case 9:
+			result.Timestamp = time.Unix(0, int64(value)).UTC()
+		case 10:
+			result.QualityScore = math.Float64frombits(value)
+		}
+		return nil
+	})
+}
+
+func appendTag(buf []byte, field int, wireType int) []byte {
+	return binary.AppendUvarint(buf, uint64(field)<<3|uint64(wireType))
+}
+
+func appendVarint(buf []byte, field int, value uint64) []byte {
+	if value == 0 {
+		return buf
+	}
+	buf = appendTag(buf, field, wireVarint)
+	return binary.AppendUvarint(buf, value)
+}
+
+func appendFixed64(buf []byte, field int, value uint64) []byte {
+	buf = appendTag(buf, field, wireFixed64)
+	return binary.LittleEndian.AppendUint64(buf, value)
+}
+
+func appendDouble(buf []byte, field int, value float64) []byte {
+	if value == 0 {
+		return buf
+	}
+	return appendFixed64(buf, field, math.Float64bits(value))
+}
+
+func appendBytes(buf []byte, field int, value []byte) []byte {
+	buf = appendTag(buf, field, wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+func appendString(buf []byte, field int, value string) []byte {
+	if value == "" {
+		return buf
+	}
+	return appendBytes(buf, field, []byte(value))
+}
+
+func appendTime(buf []byte, field int, value time.Time) []byte {
+	if value.IsZero() {
+		return buf
+	}
+	return appendVarint(buf, field, uint64(value.UnixNano()))
+}
+
+func walkFields(data []byte, visit func(field int, wireType int, value uint64, payload []byte) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("invalid protobuf field tag")
+		}
+		data = data[n:]
+
+		field, wireType := int(tag>>3), int(tag&7)
+		if field == 0 {
+			return fmt.Errorf("invalid protobuf field number 0")
+		}
+
+		var (
+			value   uint64
+			payload []byte
+		)
+
+		switch wireType {
+		case wireVarint:
+			value, n = binary.Uvarint(data)
+			if n <= 0 {
+				return fmt.Errorf("invalid varint for field %d", field)
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("truncated fixed64 for field %d", field)
+			}
+			value = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return fmt.Errorf("invalid length for field %d", field)
+			}
+			payload = data[n : n+int(length)]
+			data = data[n+int(length):]
+		case wireFixed32:
+			if len(data) < 4 {
+				return fmt.Errorf("truncated fixed32 for field %d", field)
+			}
+			value = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		default:
+			return fmt.Errorf("unsupported wire type %d for field %d", wireType, field)
+		}
+
+		if err := visit(field, wireType, value, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
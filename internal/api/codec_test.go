@@ -0,0 +1,240 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+func testQueryRequest() *metrics.QueryRequest {
+	return &metrics.QueryRequest{
+		ID:        "q-1",
+		Query:     "PERCENTILE(99)",
+		QueryType: metrics.Percentile,
+		TimeRange: metrics.TimeRange{
+			Start: time.Date(2024, 1, 1, 0, 0, 0, 123, time.UTC),
+			End:   time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC),
+		},
+		Filters:      map[string]string{"namespace": "default", "cluster_id": "prod", "empty": ""},
+		ErrorBound:   0.01,
+		Confidence:   0.95,
+		GroupByLabel: "pod",
+	}
+}
+
+func testQueryResult() *metrics.QueryResult {
+	errorValue := 0.02
+	confidence := 0.0
+	return &metrics.QueryResult{
+		ID:    "q-1",
+		Query: "PERCENTILE(99)",
+		Result: &metrics.PercentileResult{
+			Percentile: 99,
+			Value:      0.87,
+			SampleSize: 1000,
+			LowerCI:    0.85,
+			UpperCI:    0.89,
+		},
+		Error:          &errorValue,
+		Confidence:     &confidence,
+		SampleSize:     1000,
+		ProcessingTime: 1500 * time.Microsecond,
+		IsApproximate:  true,
+		Timestamp:      time.Date(2024, 1, 1, 0, 0, 0, 42, time.UTC),
+		QualityScore:   0.75,
+	}
+}
+
+func TestProtoCodecQueryRequestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *metrics.QueryRequest
+	}{
+		{name: "full", request: testQueryRequest()},
+		{name: "empty", request: &metrics.QueryRequest{}},
+		{name: "start only", request: &metrics.QueryRequest{
+			QueryType: metrics.CountDistinct,
+			TimeRange: metrics.TimeRange{Start: time.Unix(0, 1).UTC()},
+		}},
+		{name: "before epoch", request: &metrics.QueryRequest{
+			TimeRange: metrics.TimeRange{Start: time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := (ProtoCodec{}).Encode(&buf, tt.request); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			var decoded metrics.QueryRequest
+			if err := (ProtoCodec{}).Decode(&buf, &decoded); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if len(tt.request.Filters) == 0 {
+				decoded.Filters = tt.request.Filters
+			}
+			if !reflect.DeepEqual(&decoded, tt.request) {
+				t.Errorf("round trip = %+v, want %+v", decoded, *tt.request)
+			}
+		})
+	}
+}
+
+func TestProtoCodecQueryResultRoundTrip(t *testing.T) {
+	original := testQueryResult()
+
+	var buf bytes.Buffer
+	if err := (ProtoCodec{}).Encode(&buf, original); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var decoded metrics.QueryResult
+	if err := (ProtoCodec{}).Decode(&buf, &decoded); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	wantJSON, _ := json.Marshal(original.Result)
+	if raw, ok := decoded.Result.(json.RawMessage); !ok || !bytes.Equal(raw, wantJSON) {
+		t.Errorf("Result = %s, want %s", decoded.Result, wantJSON)
+	}
+	if decoded.Error == nil || *decoded.Error != *original.Error {
+		t.Errorf("Error = %v, want %v", decoded.Error, *original.Error)
+	}
+	if decoded.Confidence == nil || *decoded.Confidence != 0 {
+		t.Errorf("Confidence = %v, want explicit zero to survive as an optional field", decoded.Confidence)
+	}
+
+	decoded.Result, decoded.Error, decoded.Confidence = nil, nil, nil
+	want := *original
+	want.Result, want.Error, want.Confidence = nil, nil, nil
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("round trip = %+v, want %+v", decoded, want)
+	}
+}
+
+func TestProtoCodecQueryResultOmitsUnsetOptionals(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (ProtoCodec{}).Encode(&buf, &metrics.QueryResult{ID: "q"}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var decoded metrics.QueryResult
+	if err := (ProtoCodec{}).Decode(&buf, &decoded); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.Error != nil || decoded.Confidence != nil || decoded.Result != nil {
+		t.Errorf("unset optional fields decoded as error=%v confidence=%v result=%v",
+			decoded.Error, decoded.Confidence, decoded.Result)
+	}
+}
+
+// The expected bytes follow the proto3 encoding of query.proto so clients
+// generated with protoc can read what the server writes.
+func TestProtoCodecWireFormat(t *testing.T) {
+	request := &metrics.QueryRequest{
+		ID:         "q1",
+		Filters:    map[string]string{"ns": "a"},
+		ErrorBound: 0.5,
+	}
+	want := []byte{
+		0x0a, 0x02, 'q', '1', // 1: id
+		0x2a, 0x07, 0x0a, 0x02, 'n', 's', 0x12, 0x01, 'a', // 5: filters map entry
+		0x31, 0, 0, 0, 0, 0, 0, 0xe0, 0x3f, // 6: error_bound double
+	}
+
+	var buf bytes.Buffer
+	if err := (ProtoCodec{}).Encode(&buf, request); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Encode() = % x, want % x", buf.Bytes(), want)
+	}
+}
+
+func TestProtoCodecSkipsUnknownFields(t *testing.T) {
+	data := []byte{
+		0x0a, 0x02, 'q', '1', // 1: id
+		0x78, 0x2a, // 15: unknown varint
+		0x85, 0x01, 1, 2, 3, 4, // 16: unknown fixed32
+		0x8a, 0x01, 0x01, 'x', // 17: unknown bytes
+		0x1a, 0x0a, 'p', 'e', 'r', 'c', 'e', 'n', 't', 'i', 'l', 'e', // 3: query_type
+	}
+
+	var decoded metrics.QueryRequest
+	if err := (ProtoCodec{}).Decode(bytes.NewReader(data), &decoded); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.ID != "q1" || decoded.QueryType != metrics.Percentile {
+		t.Errorf("Decode() = %+v, want id q1 and type percentile", decoded)
+	}
+}
+
+func TestProtoCodecRejectsMalformedInput(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "truncated length", data: []byte{0x0a, 0x05, 'q'}},
+		{name: "truncated fixed64", data: []byte{0x31, 0, 0}},
+		{name: "field zero", data: []byte{0x02, 0x00}},
+		{name: "group wire type", data: []byte{0x0b}},
+		{name: "truncated varint", data: []byte{0x08, 0xff}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded metrics.QueryRequest
+			if err := (ProtoCodec{}).Decode(bytes.NewReader(tt.data), &decoded); err == nil {
+				t.Errorf("Decode(% x) succeeded, want error", tt.data)
+			}
+		})
+	}
+}
+
+func benchmarkCodecRoundTrip(b *testing.B, codec Codec) {
+	messages := []struct {
+		name    string
+		message interface{}
+		decoded func() interface{}
+	}{
+		{name: "request", message: testQueryRequest(), decoded: func() interface{} { return &metrics.QueryRequest{} }},
+		{name: "result", message: testQueryResult(), decoded: func() interface{} { return &metrics.QueryResult{} }},
+	}
+
+	for _, m := range messages {
+		b.Run(m.name, func(b *testing.B) {
+			var buf bytes.Buffer
+			if err := codec.Encode(&buf, m.message); err != nil {
+				b.Fatal(err)
+			}
+			size := buf.Len()
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				if err := codec.Encode(&buf, m.message); err != nil {
+					b.Fatal(err)
+				}
+				if err := codec.Decode(&buf, m.decoded()); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(size), "bytes/msg")
+		})
+	}
+}
+
+func BenchmarkJSONRoundTrip(b *testing.B) {
+	benchmarkCodecRoundTrip(b, JSONCodec{})
+}
+
+func BenchmarkProtoRoundTrip(b *testing.B) {
+	benchmarkCodecRoundTrip(b, ProtoCodec{})
+}
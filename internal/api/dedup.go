@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/asmit27rai/kubesight/internal/middleware"
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	dedupExpectedItems     = 1000000
+	dedupFalsePositiveRate = 0.01
+)
+
+type ingestDeduplicator struct {
+	filter *probabilistic.BloomFilter
+	path   string
+	mutex  sync.Mutex
+}
+
+func newIngestDeduplicator(path string) (*ingestDeduplicator, error) {
+	dedup := &ingestDeduplicator{path: path}
+
+	if path != "" {
+		filter, err := probabilistic.LoadBloomFilterFromFile(path)
+		switch {
+		case err == nil:
+			dedup.filter = filter
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, fmt.Errorf("failed to load dedup filter from %s: %v", path, err)
+		}
+	}
+
+	if dedup.filter == nil {
+		dedup.filter = probabilistic.NewBloomFilterOptimal(dedupExpectedItems, dedupFalsePositiveRate)
+	}
+	return dedup, nil
+}
+
+func (d *ingestDeduplicator) filterNew(points []*metrics.MetricPoint) ([]*metrics.MetricPoint, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	fresh := make([]*metrics.MetricPoint, 0, len(points))
+	for _, point := range points {
+		fingerprint := []byte(point.GetKey() + strconv.FormatInt(point.Timestamp.UnixNano(), 10))
+		if d.filter.Contains(fingerprint) {
+			continue
+		}
+		d.filter.Add(fingerprint)
+		fresh = append(fresh, point)
+	}
+
+	if d.path != "" && len(fresh) > 0 {
+		if err := d.filter.SaveToFile(d.path); err != nil {
+			return fresh, err
+		}
+	}
+	return fresh, nil
+}
+
+func (h *Handler) SetDedupFilterFile(path string) error {
+	dedup, err := newIngestDeduplicator(path)
+	if err != nil {
+		return err
+	}
+	h.dedup = dedup
+	return nil
+}
+
+func (h *Handler) IngestBatchDedup(w http.ResponseWriter, r *http.Request) {
+	if h.rejectIfDraining(w) {
+		return
+	}
+
+	var points []*metrics.MetricPoint
+	if err := json.NewDecoder(r.Body).Decode(&points); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+
+	valid := make([]*metrics.MetricPoint, 0, len(points))
+	for _, point := range points {
+		if isImportable(point) {
+			valid = append(valid, point)
+		}
+	}
+	rejected := len(points) - len(valid)
+
+	fresh, err := h.dedup.filterNew(valid)
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Failed to persist dedup filter", "error", err)
+	}
+	for _, point := range fresh {
+		h.queryEngine.ProcessMetric(point)
+	}
+
+	middleware.LoggerFromContext(r.Context()).Info("Deduplicated batch ingested",
+		"received", len(points),
+		"accepted", len(fresh),
+		"deduplicated", len(valid)-len(fresh),
+		"rejected_invalid", rejected)
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"accepted":         len(fresh),
+		"deduplicated":     len(valid) - len(fresh),
+		"rejected_invalid": rejected,
+	})
+}
+
+func isImportable(point *metrics.MetricPoint) bool {
+	return point != nil &&
+		point.ClusterID != "" &&
+		point.Namespace != "" &&
+		point.PodName != "" &&
+		point.MetricName != "" &&
+		!point.Timestamp.IsZero() &&
+		!math.IsNaN(point.Value) &&
+		!math.IsInf(point.Value, 0)
+}
@@ -1,210 +1,1477 @@
 package api
 
 import (
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/asmit27rai/kubesight/internal/alerting"
 	"github.com/asmit27rai/kubesight/internal/engine"
+	"github.com/asmit27rai/kubesight/internal/middleware"
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+	"github.com/asmit27rai/kubesight/internal/sampling"
+	"github.com/asmit27rai/kubesight/internal/slo"
+	"github.com/asmit27rai/kubesight/internal/stream"
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
 	"github.com/asmit27rai/kubesight/pkg/metrics"
 )
 
 type Handler struct {
 	queryEngine *engine.QueryEngine
+	sloTracker  *slo.SLOTracker
+	processor   *stream.Processor
+	dedup       *ingestDeduplicator
+
+	adminToken string
+	draining   atomic.Bool
+}
+
+func NewHandler(queryEngine *engine.QueryEngine) *Handler {
+	dedup, _ := newIngestDeduplicator("")
+	return &Handler{
+		queryEngine: queryEngine,
+		dedup:       dedup,
+	}
+}
+
+func (h *Handler) SetSLOTracker(tracker *slo.SLOTracker) {
+	h.sloTracker = tracker
+}
+
+func (h *Handler) SetProcessor(processor *stream.Processor) {
+	h.processor = processor
+}
+
+func (h *Handler) SetAdminToken(token string) {
+	h.adminToken = token
+}
+
+func (h *Handler) StartDraining() {
+	h.draining.Store(true)
+}
+
+func (h *Handler) IsDraining() bool {
+	return h.draining.Load()
+}
+
+func (h *Handler) rejectIfDraining(w http.ResponseWriter) bool {
+	if !h.IsDraining() {
+		return false
+	}
+	h.writeError(w, http.StatusServiceUnavailable, "Server is draining, ingest is closed", nil)
+	return true
+}
+
+func RegisterRoutes(router *mux.Router, handler *Handler) {
+	router.HandleFunc("/query", handler.ExecuteQuery).Methods("GET", "POST")
+	router.HandleFunc("/query/batch", handler.ExecuteBatchQuery).Methods("POST")
+	router.HandleFunc("/query/diff", handler.ExecuteDiffQuery).Methods("POST")
+	router.HandleFunc("/query/export", handler.StreamExport).Methods("GET")
+	router.HandleFunc("/query/complexity", handler.EstimateQueryComplexity).Methods("GET")
+
+	router.HandleFunc("/stats", handler.GetStats).Methods("GET")
+	router.HandleFunc("/stats/engine", handler.GetEngineStats).Methods("GET")
+	router.HandleFunc("/stats/sampling", handler.GetSamplingStats).Methods("GET")
+	router.HandleFunc("/stats/partitions", handler.GetPartitionStats).Methods("GET")
+	router.HandleFunc("/stream/stats", handler.GetStreamStats).Methods("GET")
+
+	router.HandleFunc("/health", handler.HealthCheck).Methods("GET")
+	router.HandleFunc("/metrics", handler.GetMetrics).Methods("GET")
+
+	router.HandleFunc("/samples", handler.GetSamples).Methods("GET")
+	router.HandleFunc("/samples/{stratum}", handler.GetStratumSamples).Methods("GET")
+
+	router.HandleFunc("/stream/percentile", handler.StreamPercentile).Methods("GET")
+
+	router.HandleFunc("/structures/cms/{metric_name}/stats", handler.GetMetricCMSStats).Methods("GET")
+
+	router.HandleFunc("/structures/cms/export", handler.ExportCMS).Methods("GET")
+	router.HandleFunc("/structures/cms/merge", handler.MergeCMS).Methods("POST")
+	router.HandleFunc("/structures/quantile/merge", handler.MergeQuantileSketches).Methods("POST")
+	router.HandleFunc("/structures/quantile/query", handler.QueryQuantiles).Methods("POST")
+	router.HandleFunc("/structures/quantile/cdf", handler.QueryCDF).Methods("GET")
+	router.HandleFunc("/structures/hll/delta", handler.GetHLLDelta).Methods("GET")
+	router.HandleFunc("/structures/hll/delta", handler.ApplyHLLDelta).Methods("POST")
+	router.HandleFunc("/structures/hll/merkle", handler.GetHLLMerkleTree).Methods("GET")
+
+	router.HandleFunc("/slo", handler.ListSLOs).Methods("GET")
+	router.HandleFunc("/slo/{name}/budget", handler.GetSLOBudget).Methods("GET")
+
+	router.HandleFunc("/dashboard/overview", handler.GetDashboardOverview).Methods("GET")
+
+	router.HandleFunc("/analytics/heatmap", handler.GetHeatmap).Methods("GET")
+	router.HandleFunc("/analytics/capacity", handler.GetCapacityForecast).Methods("GET")
+	router.HandleFunc("/analytics/pod/{pod_name}/timeseries", handler.GetPodTimeSeries).Methods("GET")
+	router.HandleFunc("/analytics/pod/{pod_name}/containers", handler.GetPodContainers).Methods("GET")
+	router.HandleFunc("/analytics/pod/{pod_name}/pressure-score", handler.GetPodPressureScore).Methods("GET")
+	router.HandleFunc("/analytics/namespace/{namespace}/summary", handler.GetNamespaceSummary).Methods("GET")
+	router.HandleFunc("/analytics/namespace/{namespace}/pressure-ranking", handler.GetNamespacePressureRanking).Methods("GET")
+	router.HandleFunc("/analytics/cluster/{cluster_id}/summary", handler.GetClusterSummary).Methods("GET")
+	router.HandleFunc("/analytics/node/{node_name}/pods", handler.GetNodePods).Methods("GET")
+	router.HandleFunc("/analytics/gaps", handler.GetGaps).Methods("GET")
+	router.HandleFunc("/analytics/representativeness", handler.GetRepresentativeness).Methods("GET")
+	router.HandleFunc("/analytics/categories", handler.GetCategories).Methods("GET")
+	router.HandleFunc("/analytics/namespaces/cardinality", handler.GetNamespaceCardinality).Methods("GET")
+	router.HandleFunc("/analytics/join-cardinality", handler.GetJoinCardinality).Methods("GET")
+	router.HandleFunc("/analytics/similar", handler.GetSimilarPods).Methods("GET")
+	router.HandleFunc("/analytics/noisy-pods", handler.GetNoisyPods).Methods("GET")
+	router.HandleFunc("/analytics/anomaly/score", handler.ScoreAnomaly).Methods("POST")
+	router.HandleFunc("/analytics/sample-size", handler.GetSampleSize).Methods("GET")
+
+	router.HandleFunc("/admin/ws", handler.AdminWebSocket).Methods("GET")
+	router.HandleFunc("/admin/reset", handler.ResetEngine).Methods("POST")
+	router.HandleFunc("/admin/warm-bloom", handler.WarmBloomFilter).Methods("POST")
+	router.HandleFunc("/admin/compaction", handler.GetCompactionStats).Methods("GET")
+	router.HandleFunc("/admin/metric-filters", handler.GetMetricFilters).Methods("GET")
+	router.HandleFunc("/admin/metric-filters", handler.UpdateMetricFilters).Methods("PUT")
+	router.HandleFunc("/admin/namespace-labels", handler.GetNamespaceLabels).Methods("GET")
+	router.HandleFunc("/admin/namespace-labels", handler.UpdateNamespaceLabels).Methods("PUT")
+	router.HandleFunc("/admin/normalization-rules", handler.GetNormalizationRules).Methods("GET")
+	router.HandleFunc("/admin/normalization-rules", handler.AddNormalizationRule).Methods("POST")
+	router.HandleFunc("/admin/metric-aliases", handler.GetMetricAliases).Methods("GET")
+	router.HandleFunc("/admin/metric-aliases", handler.AddMetricAlias).Methods("POST")
+	router.HandleFunc("/admin/metric-aliases/{metric_name}", handler.DeleteMetricAlias).Methods("DELETE")
+	router.HandleFunc("/admin/metric-priorities", handler.GetMetricPriorities).Methods("GET")
+	router.HandleFunc("/admin/metric-priorities", handler.UpdateMetricPriorities).Methods("PUT")
+	router.HandleFunc("/admin/anomaly/thresholds", handler.GetAnomalyThresholds).Methods("GET")
+	router.HandleFunc("/admin/anomaly/thresholds/reset", handler.ResetAnomalyThresholds).Methods("POST")
+	router.HandleFunc("/admin/anomaly/thresholds/{metric_name}", handler.UpdateAnomalyThreshold).Methods("PUT")
+	router.HandleFunc("/admin/anomaly/thresholds/{metric_name}", handler.RevertAnomalyThreshold).Methods("DELETE")
+
+	router.HandleFunc("/ingest/alertmanager", handler.IngestAlertmanager).Methods("POST")
+	router.HandleFunc("/ingest/batch-dedup", handler.IngestBatchDedup).Methods("POST")
+
+	router.HandleFunc("/demo/generate", handler.GenerateTestData).Methods("POST")
+	router.HandleFunc("/demo/query", handler.DemoQuery).Methods("GET")
+}
+
+func (h *Handler) ExecuteQuery(w http.ResponseWriter, r *http.Request) {
+	var request *metrics.QueryRequest
+
+	if r.Method == "POST" {
+		request = &metrics.QueryRequest{}
+		if err := requestCodec(r).Decode(r.Body, request); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid query request", err)
+			return
+		}
+	} else {
+		request = h.parseQueryParams(r)
+		if request == nil {
+			h.writeError(w, http.StatusBadRequest, "Missing required query parameters", nil)
+			return
+		}
+	}
+
+	if request.ID == "" {
+		request.ID = fmt.Sprintf("query_%d", time.Now().UnixNano())
+	}
+
+	var (
+		result *metrics.QueryResult
+		err    error
+	)
+	if asOfStr := r.URL.Query().Get("as_of"); asOfStr != "" {
+		asOf, parseErr := time.Parse(time.RFC3339, asOfStr)
+		if parseErr != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid as_of timestamp", parseErr)
+			return
+		}
+		result, err = h.queryEngine.ExecuteQueryAtTime(request, asOf)
+	} else {
+		result, err = h.queryEngine.ExecuteQuery(request)
+	}
+	var tooComplex *kserrors.ErrQueryTooComplex
+	if errors.As(err, &tooComplex) {
+		h.writeJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+			"error":      "query_too_complex",
+			"complexity": tooComplex.Complexity,
+			"limit":      tooComplex.Limit,
+		})
+		return
+	}
+	if err != nil {
+		h.writeError(w, errorStatus(err, http.StatusInternalServerError), "Query execution failed", err)
+		return
+	}
+
+	h.writeEncoded(w, responseCodec(r), http.StatusOK, result)
+
+	middleware.LoggerFromContext(r.Context()).Info("Query executed",
+		"query_id", request.ID,
+		"type", request.QueryType,
+		"processing_time", result.ProcessingTime,
+		"samples", result.SampleSize)
+}
+
+func (h *Handler) EstimateQueryComplexity(w http.ResponseWriter, r *http.Request) {
+	request := h.parseQueryParams(r)
+	if request == nil {
+		h.writeError(w, http.StatusBadRequest, "Missing required query parameters", nil)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.queryEngine.EstimateQueryComplexity(request))
+}
+
+func (h *Handler) ExecuteDiffQuery(w http.ResponseWriter, r *http.Request) {
+	var request metrics.DiffRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+	if request.Base == nil || request.Compare == nil {
+		h.writeError(w, http.StatusBadRequest, "Both base and compare queries are required", nil)
+		return
+	}
+
+	now := time.Now().UnixNano()
+	if request.Base.ID == "" {
+		request.Base.ID = fmt.Sprintf("diff_base_%d", now)
+	}
+	if request.Compare.ID == "" {
+		request.Compare.ID = fmt.Sprintf("diff_compare_%d", now)
+	}
+
+	result, err := h.queryEngine.ExecuteDiff(&request)
+	if err != nil {
+		h.writeError(w, errorStatus(err, http.StatusInternalServerError), "Diff query failed", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) StreamExport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if format := query.Get("format"); format != "" && format != "jsonl" {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("Unsupported export format: %s", format), nil)
+		return
+	}
+
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid limit parameter", err)
+			return
+		}
+		limit = parsed
+	}
+
+	request := &metrics.QueryRequest{Filters: make(map[string]string)}
+	applyQueryFilters(request, query)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	encoder := json.NewEncoder(w)
+	logger := middleware.LoggerFromContext(r.Context())
+
+	var cursor engine.ExportCursor
+	exported := 0
+	for {
+		select {
+		case <-r.Context().Done():
+			logger.Info("Export cancelled by client", "exported", exported)
+			return
+		default:
+		}
+
+		pageSize := engine.DefaultExportPageSize
+		if limit > 0 && limit-exported < pageSize {
+			pageSize = limit - exported
+		}
+
+		page, next, done := h.queryEngine.ExportPage(request, cursor, pageSize)
+		for _, sample := range page {
+			if err := encoder.Encode(sample); err != nil {
+				logger.Error("Failed to write export line", "exported", exported, "error", err)
+				return
+			}
+			exported++
+		}
+		if err := controller.Flush(); err != nil {
+			logger.Error("Failed to flush export page", "exported", exported, "error", err)
+			return
+		}
+
+		if done || (limit > 0 && exported >= limit) {
+			break
+		}
+		cursor = next
+	}
+
+	logger.Info("Export completed", "exported", exported)
+}
+
+func (h *Handler) ExecuteBatchQuery(w http.ResponseWriter, r *http.Request) {
+	var requests []metrics.QueryRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+
+	results := make([]*metrics.QueryResult, len(requests))
+
+	for i, request := range requests {
+		if request.ID == "" {
+			request.ID = fmt.Sprintf("batch_query_%d_%d", time.Now().UnixNano(), i)
+		}
+
+		result, err := h.queryEngine.ExecuteQuery(&request)
+		if err != nil {
+			result = &metrics.QueryResult{
+				ID:            request.ID,
+				Query:         request.Query,
+				Result:        nil,
+				SampleSize:    0,
+				IsApproximate: false,
+				Timestamp:     time.Now(),
+			}
+		}
+		results[i] = result
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"results": results,
+		"count":   len(results),
+	})
+}
+
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats := h.queryEngine.GetStats()
+
+	systemStats := metrics.SystemStats{
+		Timestamp:       time.Now(),
+		TotalMetrics:    stats.TotalReceived,
+		SampledMetrics:  stats.TotalSampled,
+		SamplingRate:    samplingRate(stats),
+		ProcessingRate:  float64(stats.TotalReceived) / time.Since(stats.LastUpdateTime).Seconds(),
+		QueryLatencyP95: float64(stats.P95Latency.Nanoseconds()) / 1e6,
+		ErrorRate:       stats.ErrorRate,
+	}
+
+	h.writeJSON(w, http.StatusOK, systemStats)
+}
+
+func (h *Handler) GetEngineStats(w http.ResponseWriter, r *http.Request) {
+	stats := h.queryEngine.GetStats()
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) GetSamplingStats(w http.ResponseWriter, r *http.Request) {
+	engineStats := h.queryEngine.GetStats()
+
+	stats := map[string]interface{}{
+		"total_processed":  engineStats.TotalReceived,
+		"total_sampled":    engineStats.TotalSampled,
+		"sampling_rate":    samplingRate(engineStats),
+		"adaptive_enabled": true,
+		"reservoirs":       5,
+	}
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) GetCompactionStats(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.queryEngine.GetCompactionStats())
+}
+
+func (h *Handler) GetMetricFilters(w http.ResponseWriter, r *http.Request) {
+	if h.processor == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "Stream processor is not attached", nil)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.processor.GetMetricFilters())
+}
+
+func (h *Handler) UpdateMetricFilters(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+	if h.processor == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "Stream processor is not attached", nil)
+		return
+	}
+
+	var filters stream.MetricFilters
+	if err := json.NewDecoder(r.Body).Decode(&filters); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+
+	if err := h.processor.SetMetricFilters(filters); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid metric filters", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.processor.GetMetricFilters())
+}
+
+func (h *Handler) GetNamespaceLabels(w http.ResponseWriter, r *http.Request) {
+	if h.processor == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "Stream processor is not attached", nil)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.processor.GetNamespaceLabels())
+}
+
+func (h *Handler) UpdateNamespaceLabels(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+	if h.processor == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "Stream processor is not attached", nil)
+		return
+	}
+
+	var namespaceLabels map[string]map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&namespaceLabels); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+
+	h.processor.SetNamespaceLabels(namespaceLabels)
+	h.writeJSON(w, http.StatusOK, h.processor.GetNamespaceLabels())
+}
+
+func (h *Handler) GetMetricPriorities(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.queryEngine.GetMetricPriorities())
+}
+
+func (h *Handler) UpdateMetricPriorities(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	var priorities map[string]int
+	if err := json.NewDecoder(r.Body).Decode(&priorities); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+	if priorities == nil {
+		priorities = make(map[string]int)
+	}
+
+	if err := h.queryEngine.SetMetricPriorities(priorities); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid metric priorities", err)
+		return
+	}
+	if h.processor != nil {
+		if err := h.processor.SetMetricPriorities(priorities); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid metric priorities", err)
+			return
+		}
+	}
+
+	middleware.LoggerFromContext(r.Context()).Info("Metric priorities updated", "metrics", len(priorities))
+	h.writeJSON(w, http.StatusOK, h.queryEngine.GetMetricPriorities())
+}
+
+func (h *Handler) GetAnomalyThresholds(w http.ResponseWriter, r *http.Request) {
+	thresholds := h.queryEngine.GetAnomalyThresholds()
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"thresholds": thresholds,
+		"count":      len(thresholds),
+	})
+}
+
+func (h *Handler) ScoreAnomaly(w http.ResponseWriter, r *http.Request) {
+	var metric metrics.MetricPoint
+	if err := json.NewDecoder(r.Body).Decode(&metric); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+	if metric.MetricName == "" {
+		h.writeError(w, http.StatusBadRequest, "metric_name is required", nil)
+		return
+	}
+	if metric.Timestamp.IsZero() {
+		metric.Timestamp = time.Now()
+	}
+
+	score := h.queryEngine.ScoreAnomaly(&metric)
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"score":    score,
+		"severity": alerting.AnomalySeverity(score.Class),
+	})
+}
+
+func (h *Handler) UpdateAnomalyThreshold(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	metricName := mux.Vars(r)["metric_name"]
+
+	var update sampling.AnomalyThresholdUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+
+	previous, updated, err := h.queryEngine.UpdateAnomalyThreshold(metricName, update)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid anomaly threshold", err)
+		return
+	}
+	if h.processor != nil {
+		if err := h.processor.SetAnomalyThreshold(updated); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid anomaly threshold", err)
+			return
+		}
+	}
+
+	middleware.LoggerFromContext(r.Context()).Info("Anomaly threshold updated",
+		"metric", metricName, "old", previous, "new", updated)
+	h.writeJSON(w, http.StatusOK, updated)
+}
+
+func (h *Handler) RevertAnomalyThreshold(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	metricName := mux.Vars(r)["metric_name"]
+
+	previous, restored, err := h.queryEngine.RevertAnomalyThreshold(metricName)
+	if err != nil {
+		h.writeError(w, errorStatus(err, http.StatusInternalServerError), "Failed to revert anomaly threshold", err)
+		return
+	}
+	if h.processor != nil {
+		h.processor.RevertAnomalyThreshold(metricName)
+	}
+
+	middleware.LoggerFromContext(r.Context()).Info("Anomaly threshold reverted",
+		"metric", metricName, "old", previous, "new", restored)
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"metric_name": metricName,
+		"threshold":   restored,
+		"removed":     restored == nil,
+	})
+}
+
+func (h *Handler) ResetAnomalyThresholds(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	previous := h.queryEngine.GetAnomalyThresholds()
+	h.queryEngine.ResetAnomalyThresholds()
+	if h.processor != nil {
+		h.processor.ResetAnomalyThresholds()
+	}
+	thresholds := h.queryEngine.GetAnomalyThresholds()
+
+	middleware.LoggerFromContext(r.Context()).Info("Anomaly thresholds reset to defaults",
+		"old", previous, "new", thresholds)
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"thresholds": thresholds,
+		"count":      len(thresholds),
+	})
+}
+
+func (h *Handler) ResetEngine(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	var request struct {
+		Scope string `json:"scope"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+
+	previous, err := h.queryEngine.Reset(request.Scope)
+	if err != nil {
+		h.writeError(w, errorStatus(err, http.StatusInternalServerError), "Engine reset failed", err)
+		return
+	}
+	clearedAt := time.Now()
+
+	middleware.LoggerFromContext(r.Context()).Warn("Query engine state reset",
+		"scope", request.Scope,
+		"user", middleware.UserFromContext(r.Context()),
+		"remote_addr", r.RemoteAddr,
+		"previous_stats", previous)
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"reset_scope":    request.Scope,
+		"cleared_at":     clearedAt,
+		"previous_stats": previous,
+	})
+}
+
+func (h *Handler) WarmBloomFilter(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	result := h.queryEngine.WarmBloomFilter()
+	middleware.LoggerFromContext(r.Context()).Info("Bloom filter re-warmed",
+		"user", middleware.UserFromContext(r.Context()),
+		"keys_added", result.KeysAdded)
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) GetNormalizationRules(w http.ResponseWriter, r *http.Request) {
+	if h.processor == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "Stream processor is not attached", nil)
+		return
+	}
+
+	rules := h.processor.GetNormalizationRules()
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"rules": rules,
+		"count": len(rules),
+	})
+}
+
+func (h *Handler) AddNormalizationRule(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+	if h.processor == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "Stream processor is not attached", nil)
+		return
+	}
+
+	var rule stream.NormalizationRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+
+	if err := h.processor.AddNormalizationRule(rule); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid normalization rule", err)
+		return
+	}
+
+	rules := h.processor.GetNormalizationRules()
+	h.writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"rules": rules,
+		"count": len(rules),
+	})
+}
+
+func (h *Handler) GetMetricAliases(w http.ResponseWriter, r *http.Request) {
+	if h.processor == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "Stream processor is not attached", nil)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.processor.GetMetricAliases())
+}
+
+func (h *Handler) AddMetricAlias(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+	if h.processor == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "Stream processor is not attached", nil)
+		return
+	}
+
+	var request struct {
+		Alias  *stream.MetricAlias  `json:"alias"`
+		FanOut *stream.MetricFanOut `json:"fan_out"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+	if request.Alias == nil && request.FanOut == nil {
+		h.writeError(w, http.StatusBadRequest, "Request must contain alias or fan_out", nil)
+		return
+	}
+
+	if request.Alias != nil {
+		if err := h.processor.SetMetricAlias(*request.Alias); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid metric alias", err)
+			return
+		}
+	}
+	if request.FanOut != nil {
+		if err := h.processor.SetMetricFanOut(*request.FanOut); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid metric fan-out", err)
+			return
+		}
+	}
+
+	h.writeJSON(w, http.StatusCreated, h.processor.GetMetricAliases())
+}
+
+func (h *Handler) DeleteMetricAlias(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+	if h.processor == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "Stream processor is not attached", nil)
+		return
+	}
+
+	metricName := mux.Vars(r)["metric_name"]
+	if !h.processor.RemoveMetricAliases(metricName) {
+		h.writeError(w, http.StatusNotFound, "No metric alias or fan-out for "+metricName, nil)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.processor.GetMetricAliases())
+}
+
+func (h *Handler) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if h.adminToken == "" {
+		h.writeError(w, http.StatusForbidden, "Admin updates are disabled: no admin token configured", nil)
+		return false
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+		h.writeError(w, http.StatusUnauthorized, "Invalid admin token", nil)
+		return false
+	}
+	return true
+}
+
+func (h *Handler) GetPartitionStats(w http.ResponseWriter, r *http.Request) {
+	if h.processor == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "Stream processor is not attached", nil)
+		return
+	}
+
+	partitions := h.processor.GetPartitionStats()
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"partitions": partitions,
+		"count":      len(partitions),
+	})
+}
+
+func (h *Handler) GetStreamStats(w http.ResponseWriter, r *http.Request) {
+	if h.processor == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "Stream processor is not attached", nil)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.processor.GetStats())
+}
+
+func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	if h.IsDraining() {
+		h.writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+			"status":    "draining",
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	status := map[string]interface{}{
+		"status":    "healthy",
+		"timestamp": time.Now().Format(time.RFC3339),
+		"version":   "1.0.0",
+		"service":   "kubesight-query-engine",
+	}
+
+	h.writeJSON(w, http.StatusOK, status)
+}
+
+func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	stats := h.queryEngine.GetStats()
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "# HELP kubesight_queries_total Total number of queries processed\n")
+	fmt.Fprintf(w, "# TYPE kubesight_queries_total counter\n")
+	fmt.Fprintf(w, "kubesight_queries_total{type=\"total\"} %d\n", stats.TotalQueries)
+	fmt.Fprintf(w, "kubesight_queries_total{type=\"approximate\"} %d\n", stats.ApproxQueries)
+
+	fmt.Fprintf(w, "# HELP kubesight_query_duration_milliseconds Query processing time\n")
+	fmt.Fprintf(w, "# TYPE kubesight_query_duration_milliseconds histogram\n")
+	fmt.Fprintf(w, "kubesight_query_duration_milliseconds_sum %f\n", float64(stats.AvgLatency.Nanoseconds())/1e6)
+	fmt.Fprintf(w, "kubesight_query_duration_milliseconds_count %d\n", stats.TotalQueries)
+
+	fmt.Fprintf(w, "# HELP kubesight_query_latency_milliseconds Query latency summary\n")
+	fmt.Fprintf(w, "# TYPE kubesight_query_latency_milliseconds summary\n")
+	fmt.Fprintf(w, "kubesight_query_latency_milliseconds{quantile=\"0.95\"} %f\n", float64(stats.P95Latency.Nanoseconds())/1e6)
+	fmt.Fprintf(w, "kubesight_query_latency_milliseconds{quantile=\"0.99\"} %f\n", float64(stats.P99Latency.Nanoseconds())/1e6)
+	fmt.Fprintf(w, "kubesight_query_latency_milliseconds_avg %f\n", float64(stats.AvgLatency.Nanoseconds())/1e6)
+
+	fmt.Fprintf(w, "# HELP kubesight_metrics_received_total Total number of metrics received\n")
+	fmt.Fprintf(w, "# TYPE kubesight_metrics_received_total counter\n")
+	fmt.Fprintf(w, "kubesight_metrics_received_total %d\n", stats.TotalReceived)
+
+	fmt.Fprintf(w, "# HELP kubesight_samples_total Total number of metrics kept by the sampler\n")
+	fmt.Fprintf(w, "# TYPE kubesight_samples_total counter\n")
+	fmt.Fprintf(w, "kubesight_samples_total %d\n", stats.TotalSampled)
+}
+
+func samplingRate(stats engine.QueryEngineStats) float64 {
+	if stats.TotalReceived == 0 {
+		return 0
+	}
+	return float64(stats.TotalSampled) / float64(stats.TotalReceived)
+}
+
+func (h *Handler) GetSamples(w http.ResponseWriter, r *http.Request) {
+	samples := map[string]interface{}{
+		"total_samples": 1000,
+		"strata_count":  5,
+		"last_updated":  time.Now(),
+	}
+
+	h.writeJSON(w, http.StatusOK, samples)
+}
+
+func (h *Handler) GetStratumSamples(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	stratum := vars["stratum"]
+
+	if stratum == "" {
+		h.writeError(w, http.StatusBadRequest, "Missing stratum parameter", nil)
+		return
+	}
+
+	samples := map[string]interface{}{
+		"stratum":      stratum,
+		"sample_count": 100,
+		"samples":      []interface{}{},
+		"last_updated": time.Now(),
+	}
+
+	h.writeJSON(w, http.StatusOK, samples)
+}
+
+func (h *Handler) StreamPercentile(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	metricName := query.Get("metric")
+	if metricName == "" {
+		h.writeError(w, http.StatusBadRequest, "Missing metric parameter", nil)
+		return
+	}
+
+	pStr := query.Get("p")
+	if pStr == "" {
+		h.writeError(w, http.StatusBadRequest, "Missing p parameter", nil)
+		return
+	}
+	p, err := strconv.ParseFloat(pStr, 64)
+	if err != nil || p < 0 || p > 100 {
+		h.writeError(w, http.StatusBadRequest, "Invalid p parameter", err)
+		return
+	}
+
+	interval := 5 * time.Second
+	if intervalStr := query.Get("interval"); intervalStr != "" {
+		parsed, err := time.ParseDuration(intervalStr)
+		if err != nil || parsed <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid interval parameter", err)
+			return
+		}
+		interval = parsed
+	}
+
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Unable to clear write deadline for percentile stream", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: 5000\n\n")
+	if err := controller.Flush(); err != nil {
+		middleware.LoggerFromContext(r.Context()).Error("Streaming not supported", "error", err)
+		return
+	}
+
+	request := &metrics.QueryRequest{
+		Query:     fmt.Sprintf("PERCENTILE(%g) %s", p, metricName),
+		QueryType: metrics.Percentile,
+		Filters:   map[string]string{"metric_name": metricName},
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		request.ID = fmt.Sprintf("stream_percentile_%d", time.Now().UnixNano())
+
+		event := map[string]interface{}{
+			"percentile":  p,
+			"value":       nil,
+			"sample_size": 0,
+			"timestamp":   time.Now(),
+		}
+
+		if result, err := h.queryEngine.ExecuteQuery(request); err != nil {
+			middleware.LoggerFromContext(r.Context()).Error("Streaming percentile query failed", "error", err)
+		} else {
+			event["sample_size"] = result.SampleSize
+			switch value := result.Result.(type) {
+			case *metrics.PercentileResult:
+				event["value"] = value.Value
+			case *metrics.HistogramResult:
+				event["value"] = value.Value
+			}
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			middleware.LoggerFromContext(r.Context()).Error("Failed to encode percentile event", "error", err)
+			return
+		}
+
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if err := controller.Flush(); err != nil {
+			middleware.LoggerFromContext(r.Context()).Error("Failed to flush percentile event", "error", err)
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Handler) GetMetricCMSStats(w http.ResponseWriter, r *http.Request) {
+	metricName := mux.Vars(r)["metric_name"]
+
+	stats, exists := h.queryEngine.GetMetricCMSStats(metricName)
+	if !exists {
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("No frequency sketch for metric: %s", metricName), nil)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) GetHLLMerkleTree(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.queryEngine.HLLMerkleTree())
+}
+
+func (h *Handler) GetHLLDelta(w http.ResponseWriter, r *http.Request) {
+	var since []byte
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		decoded, err := base64.StdEncoding.DecodeString(sinceStr)
+		if err != nil {
+			decoded, err = base64.URLEncoding.DecodeString(sinceStr)
+		}
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid base64 snapshot", err)
+			return
+		}
+		since = decoded
+	}
+
+	delta, err := h.queryEngine.HLLDelta(since)
+	if err != nil {
+		h.writeError(w, errorStatus(err, http.StatusBadRequest), "HyperLogLog delta failed", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"changed": len(delta),
+		"delta":   delta,
+	})
+}
+
+func (h *Handler) ApplyHLLDelta(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	var request struct {
+		Delta []probabilistic.BucketEntry `json:"delta"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+
+	stats, err := h.queryEngine.ApplyHLLDelta(request.Delta)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "HyperLogLog delta apply failed", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) ExportCMS(w http.ResponseWriter, r *http.Request) {
+	data, err := h.queryEngine.ExportCMS()
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, "Count-min sketch export failed", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"encoding": "base64-gob",
+		"sketch":   base64.StdEncoding.EncodeToString(data),
+	})
+}
+
+func (h *Handler) MergeCMS(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	var request struct {
+		Sketch string `json:"sketch"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+
+	data, err := base64.StdEncoding.DecodeString(request.Sketch)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid base64 sketch", err)
+		return
+	}
+
+	stats, err := h.queryEngine.MergeCMS(data)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Count-min sketch merge failed", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) MergeQuantileSketches(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	var encoded []string
+	if err := json.NewDecoder(r.Body).Decode(&encoded); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+
+	payloads := make([][]byte, len(encoded))
+	for i, sketch := range encoded {
+		data, err := base64.StdEncoding.DecodeString(sketch)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid base64 sketch at index %d", i), err)
+			return
+		}
+		payloads[i] = data
+	}
+
+	result, err := h.queryEngine.MergeQuantileSketches(payloads)
+	if err != nil {
+		h.writeError(w, errorStatus(err, http.StatusBadRequest), "Quantile sketch merge failed", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) QueryQuantiles(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		MetricName string    `json:"metric_name"`
+		Quantiles  []float64 `json:"quantiles"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+		return
+	}
+
+	result, err := h.queryEngine.ExecuteQuantiles(request.MetricName, request.Quantiles)
+	if err != nil {
+		h.writeError(w, errorStatus(err, http.StatusInternalServerError), "Quantile query failed", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) QueryCDF(w http.ResponseWriter, r *http.Request) {
+	value, err := strconv.ParseFloat(r.URL.Query().Get("value"), 64)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid value parameter", err)
+		return
+	}
+
+	result, err := h.queryEngine.ExecuteCDF(r.URL.Query().Get("metric"), value)
+	if err != nil {
+		h.writeError(w, errorStatus(err, http.StatusInternalServerError), "CDF query failed", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) ListSLOs(w http.ResponseWriter, r *http.Request) {
+	if h.sloTracker == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "SLO tracking is not enabled", nil)
+		return
+	}
+
+	slos := h.sloTracker.List()
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"slos":  slos,
+		"count": len(slos),
+	})
 }
 
-func NewHandler(queryEngine *engine.QueryEngine) *Handler {
-	return &Handler{
-		queryEngine: queryEngine,
+func (h *Handler) GetSLOBudget(w http.ResponseWriter, r *http.Request) {
+	if h.sloTracker == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "SLO tracking is not enabled", nil)
+		return
+	}
+
+	budget, err := h.sloTracker.Budget(mux.Vars(r)["name"])
+	if err != nil {
+		h.writeError(w, http.StatusNotFound, "SLO not found", err)
+		return
 	}
+
+	h.writeJSON(w, http.StatusOK, budget)
 }
 
-func RegisterRoutes(router *mux.Router, handler *Handler) {
-	router.HandleFunc("/query", handler.ExecuteQuery).Methods("GET", "POST")
-	router.HandleFunc("/query/batch", handler.ExecuteBatchQuery).Methods("POST")
+func (h *Handler) GetDashboardOverview(w http.ResponseWriter, r *http.Request) {
+	maxAge := engine.DefaultDashboardMaxAge
+	if maxAgeStr := r.URL.Query().Get("max_age_seconds"); maxAgeStr != "" {
+		seconds, err := strconv.Atoi(maxAgeStr)
+		if err != nil || seconds < 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid max_age_seconds parameter", err)
+			return
+		}
+		maxAge = time.Duration(seconds) * time.Second
+	}
 
-	router.HandleFunc("/stats", handler.GetStats).Methods("GET")
-	router.HandleFunc("/stats/engine", handler.GetEngineStats).Methods("GET")
-	router.HandleFunc("/stats/sampling", handler.GetSamplingStats).Methods("GET")
+	h.writeJSON(w, http.StatusOK, h.queryEngine.DashboardOverview(maxAge))
+}
 
-	router.HandleFunc("/health", handler.HealthCheck).Methods("GET")
-	router.HandleFunc("/metrics", handler.GetMetrics).Methods("GET")
+func (h *Handler) GetHeatmap(w http.ResponseWriter, r *http.Request) {
+	metricName := r.URL.Query().Get("metric")
+	if metricName == "" {
+		h.writeError(w, http.StatusBadRequest, "Missing metric parameter", nil)
+		return
+	}
 
-	router.HandleFunc("/samples", handler.GetSamples).Methods("GET")
-	router.HandleFunc("/samples/{stratum}", handler.GetStratumSamples).Methods("GET")
+	h.writeJSON(w, http.StatusOK, h.queryEngine.Heatmap(metricName))
+}
 
-	router.HandleFunc("/demo/generate", handler.GenerateTestData).Methods("POST")
-	router.HandleFunc("/demo/query", handler.DemoQuery).Methods("GET")
+func (h *Handler) GetCapacityForecast(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	metricName := query.Get("metric")
+	if metricName == "" {
+		h.writeError(w, http.StatusBadRequest, "Missing metric parameter", nil)
+		return
+	}
+
+	threshold := 0.9
+	if thresholdStr := query.Get("target_threshold"); thresholdStr != "" {
+		parsed, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid target_threshold parameter", err)
+			return
+		}
+		threshold = parsed
+	}
+
+	h.writeJSON(w, http.StatusOK, h.queryEngine.CapacityForecast(metricName, threshold))
 }
 
-func (h *Handler) ExecuteQuery(w http.ResponseWriter, r *http.Request) {
-	var request *metrics.QueryRequest
+func (h *Handler) GetPodTimeSeries(w http.ResponseWriter, r *http.Request) {
+	podName := mux.Vars(r)["pod_name"]
+	query := r.URL.Query()
 
-	if r.Method == "POST" {
-		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-			h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+	buckets := engine.DefaultTimeSeriesBuckets
+	if bucketsStr := query.Get("buckets"); bucketsStr != "" {
+		parsed, err := strconv.Atoi(bucketsStr)
+		if err != nil || parsed <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid buckets parameter", err)
 			return
 		}
-	} else {
-		request = h.parseQueryParams(r)
-		if request == nil {
-			h.writeError(w, http.StatusBadRequest, "Missing required query parameters", nil)
+		if parsed > engine.MaxTimeSeriesBuckets {
+			h.writeError(w, http.StatusBadRequest,
+				fmt.Sprintf("buckets must not exceed %d", engine.MaxTimeSeriesBuckets), nil)
 			return
 		}
+		buckets = parsed
 	}
 
-	if request.ID == "" {
-		request.ID = fmt.Sprintf("query_%d", time.Now().UnixNano())
-	}
+	h.writeJSON(w, http.StatusOK, h.queryEngine.PodTimeSeries(podName, query.Get("metric"), buckets))
+}
 
-	result, err := h.queryEngine.ExecuteQuery(request)
-	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Query execution failed", err)
-		return
-	}
+func (h *Handler) GetPodContainers(w http.ResponseWriter, r *http.Request) {
+	podName := mux.Vars(r)["pod_name"]
+	containers := h.queryEngine.PodContainers(podName)
 
-	h.writeJSON(w, http.StatusOK, result)
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pod_name":   podName,
+		"containers": containers,
+		"count":      len(containers),
+	})
+}
 
-	log.Printf("Query executed: %s (type: %s, time: %v, samples: %d)",
-		request.ID, request.QueryType, result.ProcessingTime, result.SampleSize)
+func (h *Handler) GetPodPressureScore(w http.ResponseWriter, r *http.Request) {
+	podName := mux.Vars(r)["pod_name"]
+	h.writeJSON(w, http.StatusOK, h.queryEngine.PodPressureScore(podName))
 }
 
-func (h *Handler) ExecuteBatchQuery(w http.ResponseWriter, r *http.Request) {
-	var requests []metrics.QueryRequest
+func (h *Handler) GetNamespacePressureRanking(w http.ResponseWriter, r *http.Request) {
+	namespace := mux.Vars(r)["namespace"]
+	ranking := h.queryEngine.NamespacePressureRanking(namespace)
 
-	if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
-		h.writeError(w, http.StatusBadRequest, "Invalid JSON request", err)
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"namespace": namespace,
+		"pods":      ranking,
+		"count":     len(ranking),
+	})
+}
+
+func (h *Handler) GetNamespaceSummary(w http.ResponseWriter, r *http.Request) {
+	namespace := mux.Vars(r)["namespace"]
+
+	metricName := r.URL.Query().Get("metric")
+	if metricName == "" {
+		h.writeError(w, http.StatusBadRequest, "Missing metric parameter", nil)
 		return
 	}
 
-	results := make([]*metrics.QueryResult, len(requests))
+	h.writeJSON(w, http.StatusOK, h.queryEngine.NamespaceSummary(namespace, metricName))
+}
 
-	for i, request := range requests {
-		if request.ID == "" {
-			request.ID = fmt.Sprintf("batch_query_%d_%d", time.Now().UnixNano(), i)
-		}
+func (h *Handler) GetClusterSummary(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["cluster_id"]
+	h.writeJSON(w, http.StatusOK, h.queryEngine.ClusterSummary(clusterID))
+}
 
-		result, err := h.queryEngine.ExecuteQuery(&request)
-		if err != nil {
-			result = &metrics.QueryResult{
-				ID:            request.ID,
-				Query:         request.Query,
-				Result:        nil,
-				SampleSize:    0,
-				IsApproximate: false,
-				Timestamp:     time.Now(),
-			}
-		}
-		results[i] = result
-	}
+func (h *Handler) GetNodePods(w http.ResponseWriter, r *http.Request) {
+	nodeName := mux.Vars(r)["node_name"]
+	pods := h.queryEngine.NodePods(nodeName)
 
 	h.writeJSON(w, http.StatusOK, map[string]interface{}{
-		"results": results,
-		"count":   len(results),
+		"node_name": nodeName,
+		"pods":      pods,
+		"count":     len(pods),
 	})
 }
 
-func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
-	stats := h.queryEngine.GetStats()
+func (h *Handler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	categories := h.queryEngine.CategorySummaries()
 
-	systemStats := metrics.SystemStats{
-		Timestamp:       time.Now(),
-		TotalMetrics:    stats.TotalSamples,
-		SampledMetrics:  stats.TotalSamples,
-		SamplingRate:    0.05,
-		ProcessingRate:  float64(stats.TotalSamples) / time.Since(stats.LastUpdateTime).Seconds(),
-		QueryLatencyP95: float64(stats.AvgLatency.Nanoseconds()) / 1e6,
-		ErrorRate:       stats.ErrorRate,
-	}
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"categories": categories,
+		"count":      len(categories),
+	})
+}
 
-	h.writeJSON(w, http.StatusOK, systemStats)
+func (h *Handler) GetNamespaceCardinality(w http.ResponseWriter, r *http.Request) {
+	cardinalities := h.queryEngine.NamespaceCardinalities()
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"namespaces": cardinalities,
+		"count":      len(cardinalities),
+	})
 }
 
-func (h *Handler) GetEngineStats(w http.ResponseWriter, r *http.Request) {
-	stats := h.queryEngine.GetStats()
-	h.writeJSON(w, http.StatusOK, stats)
+func (h *Handler) GetJoinCardinality(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	left, err := engine.ParseJoinFilter(query.Get("left_filter"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid left_filter parameter", err)
+		return
+	}
+	right, err := engine.ParseJoinFilter(query.Get("right_filter"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid right_filter parameter", err)
+		return
+	}
+
+	result, err := h.queryEngine.JoinCardinality(left, right, query.Get("identity"))
+	if err != nil {
+		h.writeError(w, errorStatus(err, http.StatusInternalServerError), "Join cardinality estimation failed", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
 }
 
-func (h *Handler) GetSamplingStats(w http.ResponseWriter, r *http.Request) {
-	stats := map[string]interface{}{
-		"total_processed":  1000000,
-		"total_sampled":    50000,
-		"sampling_rate":    0.05,
-		"adaptive_enabled": true,
-		"reservoirs":       5,
+func (h *Handler) GetGaps(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	stratum := query.Get("stratum")
+	if stratum == "" {
+		h.writeError(w, http.StatusBadRequest, "Missing stratum parameter", nil)
+		return
 	}
 
-	h.writeJSON(w, http.StatusOK, stats)
+	interval := engine.DefaultGapInterval
+	if intervalStr := query.Get("interval"); intervalStr != "" {
+		parsed, err := time.ParseDuration(intervalStr)
+		if err != nil || parsed <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid interval parameter", err)
+			return
+		}
+		interval = parsed
+	}
+
+	gaps := h.queryEngine.DetectGaps(stratum, interval)
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"stratum":  stratum,
+		"interval": interval.String(),
+		"gaps":     gaps,
+		"count":    len(gaps),
+	})
 }
 
-func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	status := map[string]interface{}{
-		"status":    "healthy",
-		"timestamp": time.Now().Format(time.RFC3339),
-		"version":   "1.0.0",
-		"service":   "kubesight-query-engine",
+func (h *Handler) GetRepresentativeness(w http.ResponseWriter, r *http.Request) {
+	stratum := r.URL.Query().Get("stratum")
+	if stratum == "" {
+		h.writeError(w, http.StatusBadRequest, "Missing stratum parameter", nil)
+		return
 	}
 
-	h.writeJSON(w, http.StatusOK, status)
+	result, err := h.queryEngine.SampleRepresentativeness(stratum)
+	if err != nil {
+		h.writeError(w, errorStatus(err, http.StatusInternalServerError), "Failed to assess sample representativeness", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
 }
 
-func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
-	stats := h.queryEngine.GetStats()
+func (h *Handler) GetSampleSize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
 
-	w.Header().Set("Content-Type", "text/plain")
-	w.WriteHeader(http.StatusOK)
+	params := map[string]float64{"p": 99, "error": 0.01, "confidence": 0.95}
+	for name := range params {
+		valueStr := query.Get(name)
+		if valueStr == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid "+name+" parameter", err)
+			return
+		}
+		params[name] = parsed
+	}
 
-	fmt.Fprintf(w, "# HELP kubesight_queries_total Total number of queries processed\n")
-	fmt.Fprintf(w, "# TYPE kubesight_queries_total counter\n")
-	fmt.Fprintf(w, "kubesight_queries_total{type=\"total\"} %d\n", stats.TotalQueries)
-	fmt.Fprintf(w, "kubesight_queries_total{type=\"approximate\"} %d\n", stats.ApproxQueries)
+	estimateType := query.Get("type")
+	if estimateType == "" {
+		estimateType = engine.SampleSizePercentile
+	}
 
-	fmt.Fprintf(w, "# HELP kubesight_query_duration_milliseconds Query processing time\n")
-	fmt.Fprintf(w, "# TYPE kubesight_query_duration_milliseconds histogram\n")
-	fmt.Fprintf(w, "kubesight_query_duration_milliseconds_sum %f\n", float64(stats.AvgLatency.Nanoseconds())/1e6)
-	fmt.Fprintf(w, "kubesight_query_duration_milliseconds_count %d\n", stats.TotalQueries)
+	request := &metrics.QueryRequest{Filters: make(map[string]string)}
+	applyQueryFilters(request, query)
 
-	fmt.Fprintf(w, "# HELP kubesight_samples_total Total number of samples processed\n")
-	fmt.Fprintf(w, "# TYPE kubesight_samples_total counter\n")
-	fmt.Fprintf(w, "kubesight_samples_total %d\n", stats.TotalSamples)
+	result, err := h.queryEngine.SampleSize(request, estimateType, params["p"], params["error"], params["confidence"])
+	if err != nil {
+		h.writeError(w, errorStatus(err, http.StatusInternalServerError), "Failed to estimate sample size", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
 }
 
-func (h *Handler) GetSamples(w http.ResponseWriter, r *http.Request) {
-	samples := map[string]interface{}{
-		"total_samples": 1000,
-		"strata_count":  5,
-		"last_updated":  time.Now(),
+func (h *Handler) GetSimilarPods(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	metricName, podName := query.Get("metric"), query.Get("pod")
+	if metricName == "" || podName == "" {
+		h.writeError(w, http.StatusBadRequest, "Missing metric or pod parameter", nil)
+		return
 	}
 
-	h.writeJSON(w, http.StatusOK, samples)
+	k := engine.DefaultSimilarPods
+	if kStr := query.Get("k"); kStr != "" {
+		parsed, err := strconv.Atoi(kStr)
+		if err != nil || parsed <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid k parameter", err)
+			return
+		}
+		if parsed > engine.MaxSimilarPods {
+			h.writeError(w, http.StatusBadRequest,
+				fmt.Sprintf("k must not exceed %d", engine.MaxSimilarPods), nil)
+			return
+		}
+		k = parsed
+	}
+
+	similar := h.queryEngine.SimilarPods(metricName, podName, k)
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"metric":  metricName,
+		"pod":     podName,
+		"similar": similar,
+		"count":   len(similar),
+	})
 }
 
-func (h *Handler) GetStratumSamples(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	stratum := vars["stratum"]
+func (h *Handler) GetNoisyPods(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
 
-	if stratum == "" {
-		h.writeError(w, http.StatusBadRequest, "Missing stratum parameter", nil)
+	metricName := query.Get("metric")
+	if metricName == "" {
+		h.writeError(w, http.StatusBadRequest, "Missing metric parameter", nil)
 		return
 	}
 
-	samples := map[string]interface{}{
-		"stratum":      stratum,
-		"sample_count": 100,
-		"samples":      []interface{}{},
-		"last_updated": time.Now(),
+	k := engine.DefaultNoisyPods
+	if kStr := query.Get("k"); kStr != "" {
+		parsed, err := strconv.Atoi(kStr)
+		if err != nil || parsed <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid k parameter", err)
+			return
+		}
+		if parsed > engine.MaxNoisyPods {
+			h.writeError(w, http.StatusBadRequest,
+				fmt.Sprintf("k must not exceed %d", engine.MaxNoisyPods), nil)
+			return
+		}
+		k = parsed
 	}
 
-	h.writeJSON(w, http.StatusOK, samples)
+	pods := h.queryEngine.ExecuteNoisyPods(metricName, k)
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"metric": metricName,
+		"pods":   pods,
+		"count":  len(pods),
+	})
 }
 
 func (h *Handler) GenerateTestData(w http.ResponseWriter, r *http.Request) {
+	if h.rejectIfDraining(w) {
+		return
+	}
+
 	var config struct {
 		Count     int    `json:"count"`
 		ClusterID string `json:"cluster_id"`
@@ -273,7 +1540,7 @@ func (h *Handler) DemoQuery(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.queryEngine.ExecuteQuery(request)
 	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Demo query failed", err)
+		h.writeError(w, errorStatus(err, http.StatusInternalServerError), "Demo query failed", err)
 		return
 	}
 
@@ -293,7 +1560,25 @@ func (h *Handler) parseQueryParams(r *http.Request) *metrics.QueryRequest {
 		QueryType: metrics.QueryType(queryType),
 		Filters:   make(map[string]string),
 	}
+	applyQueryFilters(request, query)
+
+	if errorStr := query.Get("error_bound"); errorStr != "" {
+		if error, err := strconv.ParseFloat(errorStr, 64); err == nil {
+			request.ErrorBound = error
+		}
+	}
+	request.GroupByLabel = query.Get("group_by_label")
+
+	if confStr := query.Get("confidence"); confStr != "" {
+		if conf, err := strconv.ParseFloat(confStr, 64); err == nil {
+			request.Confidence = conf
+		}
+	}
+
+	return request
+}
 
+func applyQueryFilters(request *metrics.QueryRequest, query url.Values) {
 	if startStr := query.Get("start"); startStr != "" {
 		if start, err := time.Parse(time.RFC3339, startStr); err == nil {
 			request.TimeRange.Start = start
@@ -310,23 +1595,10 @@ func (h *Handler) parseQueryParams(r *http.Request) *metrics.QueryRequest {
 			request.Filters[key] = values[0]
 		}
 	}
-
-	if errorStr := query.Get("error_bound"); errorStr != "" {
-		if error, err := strconv.ParseFloat(errorStr, 64); err == nil {
-			request.ErrorBound = error
-		}
-	}
-	if confStr := query.Get("confidence"); confStr != "" {
-		if conf, err := strconv.ParseFloat(confStr, 64); err == nil {
-			request.Confidence = conf
-		}
-	}
-
-	return request
 }
 
 func isReservedParam(key string) bool {
-	reserved := []string{"type", "query", "start", "end", "error_bound", "confidence"}
+	reserved := []string{"type", "query", "start", "end", "error_bound", "confidence", "group_by_label", "as_of", "format", "limit", "p", "error"}
 	for _, r := range reserved {
 		if key == r {
 			return true
@@ -336,7 +1608,7 @@ func isReservedParam(key string) bool {
 }
 
 func (h *Handler) generateTestMetrics(count int, clusterID, namespace string) {
-	log.Printf("Generating %d test metrics for cluster: %s, namespace: %s", count, clusterID, namespace)
+	slog.Info("Generating test metrics", "count", count, "cluster_id", clusterID, "namespace", namespace)
 
 	metricNames := []string{"cpu_usage", "memory_usage", "disk_usage", "network_in", "network_out"}
 	pods := []string{"pod-1", "pod-2", "pod-3", "pod-4", "pod-5"}
@@ -357,11 +1629,11 @@ func (h *Handler) generateTestMetrics(count int, clusterID, namespace string) {
 		h.queryEngine.ProcessMetric(metric)
 
 		if i%1000 == 0 {
-			log.Printf("Generated %d/%d test metrics", i, count)
+			slog.Debug("Generated test metrics", "generated", i, "total", count)
 		}
 	}
 
-	log.Printf("Completed generating %d test metrics", count)
+	slog.Info("Completed generating test metrics", "count", count)
 }
 
 func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -369,8 +1641,38 @@ func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{})
 	w.WriteHeader(status)
 
 	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("Failed to encode JSON response: %v", err)
+		slog.Error("Failed to encode JSON response", "request_id", w.Header().Get("X-Request-ID"), "error", err)
+	}
+}
+
+func (h *Handler) writeEncoded(w http.ResponseWriter, codec Codec, status int, data interface{}) {
+	w.Header().Set("Content-Type", codec.ContentType())
+	w.WriteHeader(status)
+
+	if err := codec.Encode(w, data); err != nil {
+		slog.Error("Failed to encode response",
+			"request_id", w.Header().Get("X-Request-ID"),
+			"content_type", codec.ContentType(),
+			"error", err)
+	}
+}
+
+func errorStatus(err error, fallback int) int {
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch err.(type) {
+		case *kserrors.ErrInvalidQuery, *kserrors.ErrUnsupportedQueryType:
+			return http.StatusBadRequest
+		case *kserrors.ErrMetricNotFound:
+			return http.StatusNotFound
+		case *kserrors.ErrPrecisionMismatch:
+			return http.StatusConflict
+		case *kserrors.ErrSamplerFull:
+			return http.StatusServiceUnavailable
+		case *kserrors.ErrQueryTooComplex:
+			return http.StatusTooManyRequests
+		}
 	}
+	return fallback
 }
 
 func (h *Handler) writeError(w http.ResponseWriter, status int, message string, err error) {
@@ -382,7 +1684,11 @@ func (h *Handler) writeError(w http.ResponseWriter, status int, message string,
 
 	if err != nil {
 		errorResponse["details"] = err.Error()
-		log.Printf("API Error: %s - %v", message, err)
+		slog.Warn("API error",
+			"request_id", w.Header().Get("X-Request-ID"),
+			"status", status,
+			"message", message,
+			"error", err)
 	}
 
 	h.writeJSON(w, status, errorResponse)
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/asmit27rai/kubesight/internal/engine"
+	"github.com/asmit27rai/kubesight/internal/middleware"
+	"github.com/asmit27rai/kubesight/internal/sampling"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const testAdminToken = "test-admin-token"
+
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+
+	qe := engine.NewQueryEngine(engine.QueryEngineConfig{
+		HLLPrecision: 12,
+		CMSWidth:     2048,
+		CMSDepth:     4,
+		BloomSize:    1 << 16,
+		BloomHashes:  4,
+		SamplingConfig: sampling.SamplingConfig{
+			BaseRate:         1.0,
+			AnomalyRate:      1.0,
+			WindowSize:       time.Hour,
+			ReservoirSize:    10000,
+			MetricPriorities: map[string]int{},
+		},
+	})
+	engine.SeedEngine(qe, engine.HighCPUFixture())
+
+	handler := NewHandler(qe)
+	handler.SetAdminToken(testAdminToken)
+	return handler
+}
+
+// newTestServer mounts the handler behind the same router and middleware
+// stack the server binary uses.
+func newTestServer(t *testing.T, handler *Handler) *httptest.Server {
+	t.Helper()
+
+	router := mux.NewRouter()
+	apiRouter := router.PathPrefix("/api/v1").Subrouter()
+	apiRouter.Use(middleware.RequestLogger)
+	apiRouter.Use(middleware.AuditLogger(&bytes.Buffer{}))
+	RegisterRoutes(apiRouter, handler)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStreamPercentileBehindMiddleware(t *testing.T) {
+	server := newTestServer(t, newTestHandler(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		server.URL+"/api/v1/stream/percentile?metric=cpu_usage&p=95&interval=50ms", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET stream/percentile: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", got)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line, found := strings.CutPrefix(scanner.Text(), "data: ")
+		if !found {
+			continue
+		}
+
+		var event struct {
+			Percentile float64  `json:"percentile"`
+			Value      *float64 `json:"value"`
+			SampleSize int      `json:"sample_size"`
+		}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("invalid event %q: %v", line, err)
+		}
+		if event.Percentile != 95 || event.Value == nil || event.SampleSize == 0 {
+			t.Errorf("event = %+v, want a p95 value over the seeded samples", event)
+		}
+		return
+	}
+	t.Fatalf("stream ended without an event: %v", scanner.Err())
+}
+
+func TestStructureMergeRoutesRequireAdminToken(t *testing.T) {
+	server := newTestServer(t, newTestHandler(t))
+
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{name: "cms merge", path: "/api/v1/structures/cms/merge", body: `{"sketch":""}`},
+		{name: "quantile merge", path: "/api/v1/structures/quantile/merge", body: `[]`},
+		{name: "hll delta", path: "/api/v1/structures/hll/delta", body: `{"delta":[{"i":0,"v":30}]}`},
+	}
+
+	for _, tt := range tests {
+		for _, token := range []string{"", "wrong-token"} {
+			t.Run(tt.name+"/token="+token, func(t *testing.T) {
+				req, err := http.NewRequest(http.MethodPost, server.URL+tt.path, strings.NewReader(tt.body))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if token != "" {
+					req.Header.Set("Authorization", "Bearer "+token)
+				}
+
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatalf("POST %s: %v", tt.path, err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusUnauthorized {
+					t.Errorf("status = %d, want 401", resp.StatusCode)
+				}
+			})
+		}
+	}
+}
+
+func TestMergeCMSWithAdminToken(t *testing.T) {
+	server := newTestServer(t, newTestHandler(t))
+
+	resp, err := http.Get(server.URL + "/api/v1/structures/cms/export")
+	if err != nil {
+		t.Fatalf("GET cms/export: %v", err)
+	}
+	var exported struct {
+		Sketch string `json:"sketch"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&exported)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]string{"sketch": exported.Sketch})
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/structures/cms/merge", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST cms/merge: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestExecuteQueryRejectsMalformedArguments(t *testing.T) {
+	server := newTestServer(t, newTestHandler(t))
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "percentile not a number", query: "type=percentile&query=PERCENTILE(abc)"},
+		{name: "percentile out of range", query: "type=percentile&query=PERCENTILE(101)"},
+		{name: "negative k", query: "type=top_k&query=TOP_K(-1)"},
+		{name: "k not an integer", query: "type=top_k&query=TOP_K(1.5)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + "/api/v1/query?" + strings.NewReplacer("(", "%28", ")", "%29").Replace(tt.query))
+			if err != nil {
+				t.Fatalf("GET query: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestGetPodTimeSeries(t *testing.T) {
+	server := newTestServer(t, newTestHandler(t))
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+		wantPoints int
+	}{
+		{name: "seeded pod", path: "/analytics/pod/api-1/timeseries?metric=cpu_usage&buckets=5", wantStatus: http.StatusOK, wantPoints: 5},
+		{name: "unknown pod", path: "/analytics/pod/missing/timeseries", wantStatus: http.StatusOK, wantBody: "[]\n"},
+		{name: "buckets not a number", path: "/analytics/pod/api-1/timeseries?buckets=abc", wantStatus: http.StatusBadRequest},
+		{name: "buckets over the limit", path: "/analytics/pod/api-1/timeseries?buckets=1001", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + "/api/v1" + tt.path)
+			if err != nil {
+				t.Fatalf("GET %s: %v", tt.path, err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantBody != "" && string(body) != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+			var points []metrics.TimeSeriesPoint
+			if err := json.Unmarshal(body, &points); err != nil {
+				t.Fatalf("invalid body %q: %v", body, err)
+			}
+			if len(points) != tt.wantPoints {
+				t.Errorf("got %d points, want %d", len(points), tt.wantPoints)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func FuzzParseQueryParams(f *testing.F) {
+	for _, rawQuery := range []string{
+		"",
+		"type=percentile&query=PERCENTILE(95)",
+		"type=top_k&query=TOP_K(5)&namespace=default",
+		"type=count_distinct&error_bound=0.01&confidence=0.95&group_by_label=pod",
+		"type=membership&start=2024-01-01T00:00:00Z&end=not-a-time",
+		"type=&query=x",
+		"type=percentile&error_bound=NaN&confidence=abc",
+		"%zz=1&type=%",
+	} {
+		f.Add(rawQuery)
+	}
+	h := &Handler{}
+
+	f.Fuzz(func(t *testing.T, rawQuery string) {
+		r := httptest.NewRequest("GET", "/api/v1/query", nil)
+		r.URL.RawQuery = rawQuery
+		values := r.URL.Query()
+
+		request := h.parseQueryParams(r)
+		if values.Get("type") == "" {
+			if request != nil {
+				t.Errorf("parseQueryParams(%q) = %+v without a type, want nil", rawQuery, request)
+			}
+			return
+		}
+		if request == nil {
+			t.Fatalf("parseQueryParams(%q) = nil with type %q", rawQuery, values.Get("type"))
+		}
+
+		if string(request.QueryType) != values.Get("type") {
+			t.Errorf("QueryType = %q, want %q", request.QueryType, values.Get("type"))
+		}
+		if request.Query != values.Get("query") {
+			t.Errorf("Query = %q, want %q", request.Query, values.Get("query"))
+		}
+		for key, value := range request.Filters {
+			if isReservedParam(key) {
+				t.Errorf("reserved parameter %q leaked into filters", key)
+			}
+			if want := firstValue(values, key); value != want {
+				t.Errorf("filter %q = %q, want %q", key, value, want)
+			}
+		}
+		if !request.TimeRange.Start.IsZero() && values.Get("start") == "" {
+			t.Errorf("TimeRange.Start = %v without a start parameter", request.TimeRange.Start)
+		}
+		if !request.TimeRange.End.IsZero() && values.Get("end") == "" {
+			t.Errorf("TimeRange.End = %v without an end parameter", request.TimeRange.End)
+		}
+	})
+}
+
+func firstValue(values url.Values, key string) string {
+	if v := values[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
@@ -0,0 +1,349 @@
+package api
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/engine"
+	"github.com/asmit27rai/kubesight/internal/middleware"
+)
+
+const (
+	wsAcceptGUID      = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	wsMaxPayloadBytes = 1 << 20
+
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+type AdminCommand struct {
+	Cmd        string  `json:"cmd"`
+	AdminToken string  `json:"admin_token,omitempty"`
+	Rate       float64 `json:"rate,omitempty"`
+}
+
+type AdminResponse struct {
+	Cmd    string      `json:"cmd,omitempty"`
+	OK     bool        `json:"ok"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+type CommandHandler interface {
+	ClearSamples(cmd AdminCommand) (interface{}, error)
+	SetSamplingRate(cmd AdminCommand) (interface{}, error)
+	GetStats(cmd AdminCommand) (interface{}, error)
+	ForceSnapshot(cmd AdminCommand) (interface{}, error)
+}
+
+type engineCommandHandler struct {
+	queryEngine *engine.QueryEngine
+}
+
+func (ch *engineCommandHandler) ClearSamples(cmd AdminCommand) (interface{}, error) {
+	return map[string]interface{}{"cleared": ch.queryEngine.ClearSamples()}, nil
+}
+
+func (ch *engineCommandHandler) SetSamplingRate(cmd AdminCommand) (interface{}, error) {
+	if err := ch.queryEngine.SetSamplingRate(cmd.Rate); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"rate": cmd.Rate}, nil
+}
+
+func (ch *engineCommandHandler) GetStats(cmd AdminCommand) (interface{}, error) {
+	return ch.queryEngine.GetStats(), nil
+}
+
+func (ch *engineCommandHandler) ForceSnapshot(cmd AdminCommand) (interface{}, error) {
+	return ch.queryEngine.Snapshot(), nil
+}
+
+func dispatchAdminCommand(handler CommandHandler, cmd AdminCommand) AdminResponse {
+	var commands = map[string]func(AdminCommand) (interface{}, error){
+		"clear_samples":     handler.ClearSamples,
+		"set_sampling_rate": handler.SetSamplingRate,
+		"get_stats":         handler.GetStats,
+		"force_snapshot":    handler.ForceSnapshot,
+	}
+
+	run, exists := commands[cmd.Cmd]
+	if !exists {
+		return AdminResponse{Cmd: cmd.Cmd, OK: false, Error: fmt.Sprintf("unknown command: %s", cmd.Cmd)}
+	}
+
+	result, err := run(cmd)
+	if err != nil {
+		return AdminResponse{Cmd: cmd.Cmd, OK: false, Error: err.Error()}
+	}
+	return AdminResponse{Cmd: cmd.Cmd, OK: true, Result: result}
+}
+
+func (h *Handler) AdminWebSocket(w http.ResponseWriter, r *http.Request) {
+	if h.adminToken == "" {
+		h.writeError(w, http.StatusForbidden, "Admin channel is disabled: no admin token configured", nil)
+		return
+	}
+
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "WebSocket upgrade failed", err)
+		return
+	}
+	defer conn.Close()
+
+	var commandHandler CommandHandler = &engineCommandHandler{queryEngine: h.queryEngine}
+
+	first, err := conn.ReadMessage()
+	if err != nil {
+		return
+	}
+
+	var auth AdminCommand
+	if err := json.Unmarshal(first, &auth); err != nil ||
+		subtle.ConstantTimeCompare([]byte(auth.AdminToken), []byte(h.adminToken)) != 1 {
+		conn.WriteJSON(AdminResponse{OK: false, Error: "authentication failed"})
+		return
+	}
+	conn.WriteJSON(AdminResponse{Cmd: "auth", OK: true})
+
+	events, unsubscribe := h.queryEngine.SubscribeProcessed()
+	defer unsubscribe()
+
+	go func() {
+		for count := range events {
+			if err := conn.WriteJSON(map[string]interface{}{
+				"event": "metric_processed",
+				"count": count,
+			}); err != nil {
+				return
+			}
+		}
+	}()
+
+	changes, unsubscribeChanges := h.queryEngine.SubscribeChanges()
+	defer unsubscribeChanges()
+
+	go func() {
+		for change := range changes {
+			if err := conn.WriteJSON(map[string]interface{}{
+				"event":  change.Type,
+				"change": change,
+			}); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		message, err := conn.ReadMessage()
+		if err != nil {
+			if err != io.EOF {
+				middleware.LoggerFromContext(r.Context()).Info("Admin WebSocket closed", "error", err)
+			}
+			return
+		}
+
+		var cmd AdminCommand
+		if err := json.Unmarshal(message, &cmd); err != nil {
+			conn.WriteJSON(AdminResponse{OK: false, Error: "invalid command JSON"})
+			continue
+		}
+
+		response := dispatchAdminCommand(commandHandler, cmd)
+		middleware.LoggerFromContext(r.Context()).Info("Admin command executed", "cmd", cmd.Cmd, "ok", response.OK)
+
+		if err := conn.WriteJSON(response); err != nil {
+			return
+		}
+	}
+}
+
+type wsConn struct {
+	conn       net.Conn
+	reader     *bufio.Reader
+	writeMutex sync.Mutex
+}
+
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		return nil, fmt.Errorf("missing websocket upgrade headers")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, fmt.Errorf("unsupported websocket version")
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, fmt.Errorf("missing Sec-WebSocket-Key")
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+
+	hash := sha1.Sum([]byte(key + wsAcceptGUID))
+	accept := base64.StdEncoding.EncodeToString(hash[:])
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + accept + "\r\n\r\n"
+
+	if _, err := rw.WriteString(response); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, reader: rw.Reader}, nil
+}
+
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	var message []byte
+
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			c.writeFrame(wsOpClose, nil)
+			return nil, io.EOF
+		case wsOpText, wsOpBinary, wsOpContinuation:
+			message = append(message, payload...)
+			if len(message) > wsMaxPayloadBytes {
+				return nil, fmt.Errorf("websocket message exceeds %d bytes", wsMaxPayloadBytes)
+			}
+			if fin {
+				return message, nil
+			}
+		default:
+			return nil, fmt.Errorf("unsupported websocket opcode: %d", opcode)
+		}
+	}
+}
+
+func (c *wsConn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(wsOpText, data)
+}
+
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *wsConn) readFrame() (bool, byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended)
+	}
+
+	if !masked {
+		return false, 0, nil, fmt.Errorf("client frames must be masked")
+	}
+	if length > wsMaxPayloadBytes {
+		return false, 0, nil, fmt.Errorf("websocket frame exceeds %d bytes", wsMaxPayloadBytes)
+	}
+
+	mask := make([]byte, 4)
+	if _, err := io.ReadFull(c.reader, mask); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	frame := []byte{0x80 | opcode}
+	length := len(payload)
+
+	switch {
+	case length < 126:
+		frame = append(frame, byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, 126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(length))
+	default:
+		frame = append(frame, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(length))
+	}
+
+	frame = append(frame, payload...)
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+func headerContains(header http.Header, name, value string) bool {
+	for _, v := range header.Values(name) {
+		for _, part := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"gopkg.in/yaml.v2"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -11,16 +13,73 @@ type Config struct {
 	Kafka    KafkaConfig    `yaml:"kafka"`
 	Sampling SamplingConfig `yaml:"sampling"`
 	Storage  StorageConfig  `yaml:"storage"`
+	SLOs     []SLOConfig    `yaml:"slos"`
 }
 
 type ServerConfig struct {
 	Host string `yaml:"host" env:"SERVER_HOST" default:"0.0.0.0"`
 	Port int    `yaml:"port" env:"SERVER_PORT" default:"8080"`
+
+	AdminToken string `yaml:"admin_token" env:"ADMIN_TOKEN"`
+
+	AuditLogFile string `yaml:"audit_log_file" env:"AUDIT_LOG_FILE"`
+
+	DedupFilterFile string `yaml:"dedup_filter_file" env:"DEDUP_FILTER_FILE"`
+
+	LogLevel string `yaml:"log_level" env:"LOG_LEVEL" default:"info"`
+
+	ShutdownDrainTimeout time.Duration `yaml:"shutdown_drain_timeout" default:"30s"`
 }
 
 type KafkaConfig struct {
 	Brokers []string `yaml:"brokers" env:"KAFKA_BROKERS" default:"localhost:9092"`
 	Topics  Topics   `yaml:"topics"`
+
+	PartitionAssignment  map[string][]int `yaml:"partition_assignment"`
+	PartitionStartOffset string           `yaml:"partition_start_offset" default:"latest"`
+	PartitionByCluster   bool             `yaml:"partition_by_cluster" env:"PARTITION_BY_CLUSTER"`
+
+	AllowNegativeValues []string `yaml:"allow_negative_values"`
+
+	MetricAllowlist []string `yaml:"metric_allowlist"`
+	MetricBlocklist []string `yaml:"metric_blocklist"`
+
+	NamespaceLabelsFile string `yaml:"namespace_labels_file" env:"NAMESPACE_LABELS_FILE"`
+
+	NormalizationRules []NormalizationRuleConfig `yaml:"normalization_rules"`
+
+	CategoryRules map[string]string `yaml:"category_rules"`
+
+	MetricAliases []MetricAliasConfig  `yaml:"metric_aliases"`
+	MetricFanOuts []MetricFanOutConfig `yaml:"metric_fan_outs"`
+
+	PreAggregateMetrics []string `yaml:"pre_aggregate_metrics"`
+
+	ConsumerGroups []ConsumerGroupConfig `yaml:"consumer_groups"`
+}
+
+type ConsumerGroupConfig struct {
+	GroupID       string  `yaml:"group_id"`
+	Topic         string  `yaml:"topic"`
+	SamplingRate  float64 `yaml:"sampling_rate"`
+	ReservoirSize int     `yaml:"reservoir_size"`
+}
+
+type NormalizationRuleConfig struct {
+	MetricName  string  `yaml:"metric_name"`
+	Unit        string  `yaml:"unit"`
+	TargetUnit  string  `yaml:"target_unit"`
+	ScaleFactor float64 `yaml:"scale_factor"`
+}
+
+type MetricAliasConfig struct {
+	From []string `yaml:"from"`
+	To   string   `yaml:"to"`
+}
+
+type MetricFanOutConfig struct {
+	From string   `yaml:"from"`
+	To   []string `yaml:"to"`
 }
 
 type Topics struct {
@@ -35,6 +94,10 @@ type SamplingConfig struct {
 	ReservoirSize   int     `yaml:"reservoir_size" default:"10000"`
 	WindowSizeMin   int     `yaml:"window_size_min" default:"60"`
 	AdaptiveEnabled bool    `yaml:"adaptive_enabled" default:"true"`
+
+	ResolutionTierMinutes []int `yaml:"resolution_tier_minutes"`
+
+	MetricPriorities map[string]int `yaml:"metric_priorities"`
 }
 
 type StorageConfig struct {
@@ -43,6 +106,40 @@ type StorageConfig struct {
 	CMSDepth     int `yaml:"cms_depth" default:"5"`
 	BloomSize    int `yaml:"bloom_size" default:"1000000"`
 	BloomHashes  int `yaml:"bloom_hashes" default:"5"`
+
+	HistogramType  string `yaml:"histogram_type" default:"exact"`
+	HistogramScale int    `yaml:"histogram_scale" default:"8"`
+
+	QuantileAlgorithm string `yaml:"quantile_algorithm" default:"kll"`
+
+	PressureWeights map[string]float64 `yaml:"pressure_weights"`
+
+	CardinalityAlerts               []CardinalityAlertConfig `yaml:"cardinality_alerts"`
+	CardinalityAlertThrottleMinutes int                      `yaml:"cardinality_alert_throttle_minutes" default:"5"`
+
+	HLLAutoPrecision bool `yaml:"hll_auto_precision" default:"false"`
+
+	KeyDimensions []string `yaml:"key_dimensions"`
+
+	MaxQueryComplexity int `yaml:"max_query_complexity" default:"100000"`
+
+	SnapshotPath            string `yaml:"snapshot_path" env:"SNAPSHOT_PATH"`
+	SnapshotIntervalSeconds int    `yaml:"snapshot_interval_seconds" env:"SNAPSHOT_INTERVAL_SECONDS" default:"300"`
+}
+
+type CardinalityAlertConfig struct {
+	MetricName      string `yaml:"metric_name"`
+	MaxExpectedKeys int    `yaml:"max_expected_keys"`
+	WebhookURL      string `yaml:"webhook_url"`
+}
+
+type SLOConfig struct {
+	Name          string  `yaml:"name"`
+	MetricName    string  `yaml:"metric_name"`
+	Percentile    float64 `yaml:"percentile" default:"95"`
+	GoodThreshold float64 `yaml:"good_threshold"`
+	Target        float64 `yaml:"target"`
+	WindowMinutes int     `yaml:"window_minutes" default:"43200"`
 }
 
 func LoadConfig(configPath string) (*Config, error) {
@@ -50,7 +147,14 @@ func LoadConfig(configPath string) (*Config, error) {
 
 	config.Server.Host = getEnvOrDefault("SERVER_HOST", "0.0.0.0")
 	config.Server.Port = 8080
+	config.Server.AdminToken = os.Getenv("ADMIN_TOKEN")
+	config.Server.AuditLogFile = os.Getenv("AUDIT_LOG_FILE")
+	config.Server.DedupFilterFile = os.Getenv("DEDUP_FILTER_FILE")
+	config.Server.LogLevel = getEnvOrDefault("LOG_LEVEL", "info")
+	config.Server.ShutdownDrainTimeout = 30 * time.Second
 	config.Kafka.Brokers = []string{getEnvOrDefault("KAFKA_BROKERS", "localhost:9092")}
+	config.Kafka.NamespaceLabelsFile = os.Getenv("NAMESPACE_LABELS_FILE")
+	config.Kafka.PartitionByCluster = getEnvOrDefault("PARTITION_BY_CLUSTER", "false") == "true"
 	config.Kafka.Topics.Metrics = "k8s-metrics"
 	config.Kafka.Topics.Logs = "k8s-logs"
 	config.Kafka.Topics.Events = "k8s-events"
@@ -64,6 +168,19 @@ func LoadConfig(configPath string) (*Config, error) {
 	config.Storage.CMSDepth = 5
 	config.Storage.BloomSize = 1000000
 	config.Storage.BloomHashes = 5
+	config.Storage.HistogramType = "exact"
+	config.Storage.HistogramScale = 8
+	config.Storage.QuantileAlgorithm = "kll"
+	config.Storage.MaxQueryComplexity = 100000
+	config.Storage.SnapshotPath = os.Getenv("SNAPSHOT_PATH")
+	config.Storage.SnapshotIntervalSeconds = 300
+	if interval := os.Getenv("SNAPSHOT_INTERVAL_SECONDS"); interval != "" {
+		seconds, err := strconv.Atoi(interval)
+		if err != nil || seconds < 0 {
+			return nil, fmt.Errorf("invalid SNAPSHOT_INTERVAL_SECONDS %q: must be a non-negative integer", interval)
+		}
+		config.Storage.SnapshotIntervalSeconds = seconds
+	}
 
 	if configPath != "" {
 		data, err := os.ReadFile(configPath)
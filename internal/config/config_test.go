@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigSnapshotFromEnv(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantPath     string
+		wantInterval int
+		wantErr      bool
+	}{
+		{name: "defaults", wantInterval: 300},
+		{
+			name:         "path and interval",
+			env:          map[string]string{"SNAPSHOT_PATH": "/var/lib/kubesight/engine.snapshot", "SNAPSHOT_INTERVAL_SECONDS": "60"},
+			wantPath:     "/var/lib/kubesight/engine.snapshot",
+			wantInterval: 60,
+		},
+		{
+			name:         "zero interval disables periodic snapshots",
+			env:          map[string]string{"SNAPSHOT_PATH": "/tmp/engine.snapshot", "SNAPSHOT_INTERVAL_SECONDS": "0"},
+			wantPath:     "/tmp/engine.snapshot",
+			wantInterval: 0,
+		},
+		{name: "interval not a number", env: map[string]string{"SNAPSHOT_INTERVAL_SECONDS": "5m"}, wantErr: true},
+		{name: "negative interval", env: map[string]string{"SNAPSHOT_INTERVAL_SECONDS": "-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SNAPSHOT_PATH", "")
+			t.Setenv("SNAPSHOT_INTERVAL_SECONDS", "")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := LoadConfig("")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.Storage.SnapshotPath != tt.wantPath {
+				t.Errorf("SnapshotPath = %q, want %q", cfg.Storage.SnapshotPath, tt.wantPath)
+			}
+			if cfg.Storage.SnapshotIntervalSeconds != tt.wantInterval {
+				t.Errorf("SnapshotIntervalSeconds = %d, want %d", cfg.Storage.SnapshotIntervalSeconds, tt.wantInterval)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileOverridesSnapshotEnv(t *testing.T) {
+	t.Setenv("SNAPSHOT_PATH", "/from/env")
+	t.Setenv("SNAPSHOT_INTERVAL_SECONDS", "60")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("storage:\n  snapshot_path: /from/file\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Storage.SnapshotPath != "/from/file" {
+		t.Errorf("SnapshotPath = %q, want the config file value", cfg.Storage.SnapshotPath)
+	}
+	if cfg.Storage.SnapshotIntervalSeconds != 60 {
+		t.Errorf("SnapshotIntervalSeconds = %d, want the env value kept when the file omits it", cfg.Storage.SnapshotIntervalSeconds)
+	}
+}
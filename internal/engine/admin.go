@@ -0,0 +1,242 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+	"github.com/asmit27rai/kubesight/internal/sampling"
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const metricEventInterval = 100
+
+const (
+	ResetScopeAll     = "all"
+	ResetScopeHLL     = "hll"
+	ResetScopeCMS     = "cms"
+	ResetScopeBloom   = "bloom"
+	ResetScopeSamples = "samples"
+	ResetScopeStats   = "stats"
+)
+
+func ValidResetScope(scope string) bool {
+	switch scope {
+	case ResetScopeAll, ResetScopeHLL, ResetScopeCMS, ResetScopeBloom, ResetScopeSamples, ResetScopeStats:
+		return true
+	}
+	return false
+}
+
+type EngineSnapshot struct {
+	Timestamp   time.Time                `json:"timestamp"`
+	Stats       QueryEngineStats         `json:"stats"`
+	HLL         probabilistic.HLLStats   `json:"hll"`
+	CMS         probabilistic.CMSStats   `json:"cms"`
+	Bloom       probabilistic.BloomStats `json:"bloom"`
+	SampleKeys  int                      `json:"sample_keys"`
+	SampleCount int                      `json:"sample_count"`
+}
+
+func (qe *QueryEngine) ClearSamples() int {
+	qe.mutex.Lock()
+	defer qe.mutex.Unlock()
+
+	cleared := len(qe.getAllSamples())
+	qe.clearSamples()
+	return cleared
+}
+
+// clearSamples drops every retained sample, including the sampler
+// reservoirs and the downsampled tiers, so no query can still see them.
+// The caller must hold qe.mutex.
+func (qe *QueryEngine) clearSamples() {
+	qe.samples = make(map[string][]*metrics.MetricPoint)
+	qe.index = NewMetricIndex()
+	qe.sampleKeys = nil
+	qe.sampler.ClearReservoirs()
+	qe.resolutions.Clear()
+}
+
+func (qe *QueryEngine) Reset(scope string) (QueryEngineStats, error) {
+	if !ValidResetScope(scope) {
+		return QueryEngineStats{}, &kserrors.ErrInvalidQuery{Reason: fmt.Sprintf("unknown reset scope: %s", scope)}
+	}
+
+	qe.mutex.Lock()
+	defer qe.mutex.Unlock()
+
+	previous := qe.stats
+	all := scope == ResetScopeAll
+
+	if all || scope == ResetScopeHLL {
+		qe.hll.Clear()
+		qe.restoredSnapshot = false
+		qe.labelHLLs = make(map[string]*probabilistic.HyperLogLog)
+		qe.categoryHLLs = make(map[string]*probabilistic.HyperLogLog)
+		qe.namespacedHLL = make(map[string]*probabilistic.HyperLogLog)
+		qe.namespaceOrder = nil
+	}
+	if all || scope == ResetScopeCMS {
+		qe.cms.Clear()
+		qe.slidingCMS.Clear()
+		qe.metricCMS = make(map[string]*probabilistic.CountMinSketch)
+		qe.categoryCMS = make(map[string]*probabilistic.CountMinSketch)
+	}
+	if all || scope == ResetScopeBloom {
+		qe.bloom.Clear()
+	}
+	if all || scope == ResetScopeSamples {
+		qe.clearSamples()
+	}
+	if all || scope == ResetScopeStats {
+		qe.stats = QueryEngineStats{LastUpdateTime: time.Now()}
+		qe.latencies = latencyHistogram{}
+		qe.startedAt = time.Now()
+	}
+
+	return previous, nil
+}
+
+func (qe *QueryEngine) SetSamplingRate(rate float64) error {
+	if rate <= 0 || rate > 1 {
+		return fmt.Errorf("sampling rate must be in (0, 1]: %f", rate)
+	}
+
+	qe.sampler.SetBaseRate(rate)
+	return nil
+}
+
+func (qe *QueryEngine) GetMetricPriorities() map[string]int {
+	return qe.sampler.GetMetricPriorities()
+}
+
+func (qe *QueryEngine) SetMetricPriorities(priorities map[string]int) error {
+	return qe.sampler.SetMetricPriorities(priorities)
+}
+
+func (qe *QueryEngine) GetAnomalyThresholds() []sampling.AnomalyThreshold {
+	return qe.sampler.AnomalyDetector().Thresholds()
+}
+
+func (qe *QueryEngine) UpdateAnomalyThreshold(metricName string, update sampling.AnomalyThresholdUpdate) (sampling.AnomalyThreshold, sampling.AnomalyThreshold, error) {
+	detector := qe.sampler.AnomalyDetector()
+
+	previous, exists := detector.Threshold(metricName)
+	if !exists {
+		if update.UpperBound == nil {
+			return previous, previous, fmt.Errorf("upper_bound is required for new anomaly threshold %s", metricName)
+		}
+		previous = sampling.AnomalyThreshold{MetricName: metricName}
+	}
+
+	updated := update.Apply(previous)
+	if err := detector.SetThreshold(updated); err != nil {
+		return previous, previous, err
+	}
+	return previous, updated, nil
+}
+
+func (qe *QueryEngine) RevertAnomalyThreshold(metricName string) (sampling.AnomalyThreshold, *sampling.AnomalyThreshold, error) {
+	detector := qe.sampler.AnomalyDetector()
+
+	previous, exists := detector.Threshold(metricName)
+	restored, hasDefault := detector.RevertThreshold(metricName)
+	if !exists && !hasDefault {
+		return previous, nil, &kserrors.ErrMetricNotFound{MetricName: metricName}
+	}
+	if !hasDefault {
+		return previous, nil, nil
+	}
+	return previous, &restored, nil
+}
+
+func (qe *QueryEngine) ResetAnomalyThresholds() {
+	qe.sampler.AnomalyDetector().ResetThresholds()
+}
+
+func (qe *QueryEngine) Snapshot() *EngineSnapshot {
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	snapshot := &EngineSnapshot{
+		Timestamp:  time.Now(),
+		Stats:      qe.stats,
+		HLL:        qe.hll.GetStats(),
+		CMS:        qe.cms.GetStats(),
+		Bloom:      qe.bloom.GetStats(),
+		SampleKeys: len(qe.samples),
+	}
+	for _, samples := range qe.samples {
+		snapshot.SampleCount += len(samples)
+	}
+
+	return snapshot
+}
+
+func (qe *QueryEngine) StartCompactor(ctx context.Context, interval time.Duration) {
+	qe.sampler.StartCompactor(ctx, interval)
+}
+
+func (qe *QueryEngine) GetCompactionStats() sampling.CompactionStats {
+	return qe.sampler.GetCompactionStats()
+}
+
+func (qe *QueryEngine) Drain(timeout time.Duration) error {
+	qe.drainMutex.Lock()
+	qe.draining = true
+	qe.drainMutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		qe.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out after %v waiting for in-flight metrics", timeout)
+	}
+}
+
+func (qe *QueryEngine) SubscribeProcessed() (<-chan uint64, func()) {
+	qe.listenerMutex.Lock()
+	defer qe.listenerMutex.Unlock()
+
+	ch := make(chan uint64, 16)
+	id := qe.nextListenerID
+	qe.nextListenerID++
+	qe.listeners[id] = ch
+
+	unsubscribe := func() {
+		qe.listenerMutex.Lock()
+		defer qe.listenerMutex.Unlock()
+
+		if _, exists := qe.listeners[id]; exists {
+			delete(qe.listeners, id)
+			close(ch)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+func (qe *QueryEngine) notifyProcessed(count uint64) {
+	if count%metricEventInterval != 0 {
+		return
+	}
+
+	qe.listenerMutex.Lock()
+	defer qe.listenerMutex.Unlock()
+
+	for _, ch := range qe.listeners {
+		select {
+		case ch <- count:
+		default:
+		}
+	}
+}
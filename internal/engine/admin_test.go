@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+func TestClearSamplesRemovesAllSampleData(t *testing.T) {
+	recentCPU := NewFixtureBuilder().
+		WithCluster("prod").
+		WithPods("api-1", "api-2").
+		WithMetric("cpu_usage").
+		WithValues(rampValues(20, 0.5, 0.9)).
+		WithTimestamps(time.Now().Add(-10*time.Minute), 30*time.Second).
+		Build()
+
+	tests := []struct {
+		name  string
+		clear func(qe *QueryEngine)
+	}{
+		{name: "ClearSamples", clear: func(qe *QueryEngine) { qe.ClearSamples() }},
+		{name: "Reset samples", clear: func(qe *QueryEngine) {
+			if _, err := qe.Reset(ResetScopeSamples); err != nil {
+				t.Fatalf("Reset() error = %v", err)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qe := seededTestEngine(t, recentCPU)
+			if len(qe.RecentSamples(time.Hour)) == 0 || len(qe.sampler.GetAllSamples()) == 0 {
+				t.Fatal("seeding left no recent or reservoir samples to clear")
+			}
+
+			tt.clear(qe)
+
+			for _, request := range []*metrics.QueryRequest{
+				{QueryType: metrics.Percentile, Query: "PERCENTILE(95)", Filters: map[string]string{"metric_name": "cpu_usage"}},
+				{QueryType: metrics.Average, Query: "AVG(cpu_usage)", Filters: map[string]string{"metric_name": "cpu_usage"}},
+			} {
+				result, err := qe.ExecuteQuery(request)
+				if err != nil {
+					t.Fatalf("ExecuteQuery(%s) error = %v", request.Query, err)
+				}
+				if result.SampleSize != 0 {
+					t.Errorf("%s sample size = %d after clearing, want 0", request.Query, result.SampleSize)
+				}
+			}
+
+			if got := len(qe.RecentSamples(time.Hour)); got != 0 {
+				t.Errorf("RecentSamples() = %d samples after clearing, want 0", got)
+			}
+			if got := len(qe.trendSamples("cpu_usage")); got != 0 {
+				t.Errorf("trendSamples() = %d samples after clearing, want 0", got)
+			}
+			if got := len(qe.sampler.GetAllSamples()); got != 0 {
+				t.Errorf("sampler still holds %d reservoirs after clearing", got)
+			}
+			if _, err := qe.SampleRepresentativeness("prod/"); err == nil {
+				t.Error("SampleRepresentativeness() found samples after clearing")
+			}
+		})
+	}
+}
@@ -0,0 +1,593 @@
+package engine
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/sampling"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	DefaultHeatmapMaxSize = 50
+	MaxNamespaceHLLs      = 100
+)
+
+func (qe *QueryEngine) Heatmap(metricName string) *metrics.HeatmapResult {
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	maxSize := qe.heatmapMaxSize
+
+	type cell struct {
+		sum   float64
+		count int
+	}
+
+	cells := make(map[string]map[string]*cell)
+	podCounts := make(map[string]int)
+	namespaceCounts := make(map[string]int)
+
+	for _, sample := range qe.getAllSamples() {
+		if sample.MetricName != metricName {
+			continue
+		}
+
+		row, exists := cells[sample.Namespace]
+		if !exists {
+			row = make(map[string]*cell)
+			cells[sample.Namespace] = row
+		}
+		if _, exists := row[sample.PodName]; !exists {
+			row[sample.PodName] = &cell{}
+		}
+		row[sample.PodName].sum += sample.Value
+		row[sample.PodName].count++
+
+		namespaceCounts[sample.Namespace]++
+		podCounts[sample.PodName]++
+	}
+
+	namespaces := topKeysByCount(namespaceCounts, maxSize)
+	pods := topKeysByCount(podCounts, maxSize)
+
+	values := make([][]float64, len(namespaces))
+	for i, namespace := range namespaces {
+		values[i] = make([]float64, len(pods))
+		for j, pod := range pods {
+			if c, exists := cells[namespace][pod]; exists && c.count > 0 {
+				values[i][j] = c.sum / float64(c.count)
+			} else {
+				values[i][j] = math.NaN()
+			}
+		}
+	}
+
+	return &metrics.HeatmapResult{
+		MetricName: metricName,
+		Namespaces: namespaces,
+		Pods:       pods,
+		Values:     values,
+	}
+}
+
+const (
+	DefaultTimeSeriesBuckets = 60
+	MaxTimeSeriesBuckets     = 1000
+)
+
+func (qe *QueryEngine) PodTimeSeries(podName, metricName string, buckets int) []metrics.TimeSeriesPoint {
+	if buckets <= 0 {
+		buckets = DefaultTimeSeriesBuckets
+	}
+	if buckets > MaxTimeSeriesBuckets {
+		buckets = MaxTimeSeriesBuckets
+	}
+
+	filters := map[string]string{"pod_name": podName}
+	if metricName != "" {
+		filters["metric_name"] = metricName
+	}
+
+	qe.mutex.RLock()
+	samples := qe.getFilteredSamples(&metrics.QueryRequest{Filters: filters})
+	qe.mutex.RUnlock()
+
+	points := make([]metrics.TimeSeriesPoint, 0, buckets)
+	if len(samples) == 0 {
+		return points
+	}
+
+	start, end := samples[0].Timestamp, samples[0].Timestamp
+	for _, sample := range samples {
+		if sample.Timestamp.Before(start) {
+			start = sample.Timestamp
+		}
+		if sample.Timestamp.After(end) {
+			end = sample.Timestamp
+		}
+	}
+
+	width := end.Sub(start) / time.Duration(buckets)
+	if width <= 0 {
+		width = 1
+	}
+
+	sums := make([]float64, buckets)
+	counts := make([]int, buckets)
+	for _, sample := range samples {
+		idx := int(sample.Timestamp.Sub(start) / width)
+		if idx >= buckets {
+			idx = buckets - 1
+		}
+		sums[idx] += sample.Value
+		counts[idx]++
+	}
+
+	for i := range sums {
+		if counts[i] == 0 {
+			continue
+		}
+		points = append(points, metrics.TimeSeriesPoint{
+			Timestamp: start.Add(time.Duration(i) * width),
+			Value:     sums[i] / float64(counts[i]),
+		})
+	}
+
+	return points
+}
+
+func (qe *QueryEngine) NamespaceSummary(namespace, metricName string) *metrics.NamespaceSummary {
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	return qe.executeNamespaceSummary(namespace, metricName)
+}
+
+func (qe *QueryEngine) executeNamespaceSummary(namespace, metricName string) *metrics.NamespaceSummary {
+	summary := &metrics.NamespaceSummary{
+		Namespace:  namespace,
+		MetricName: metricName,
+	}
+
+	filters := map[string]string{"namespace": namespace}
+	if metricName != "" {
+		filters["metric_name"] = metricName
+	}
+
+	samples := qe.getFilteredSamples(&metrics.QueryRequest{Filters: filters})
+	if len(samples) == 0 {
+		return summary
+	}
+
+	type podAggregate struct {
+		sum   float64
+		count int
+	}
+
+	pods := make(map[string]*podAggregate)
+	values := make([]float64, len(samples))
+	summary.Max = math.Inf(-1)
+
+	for i, sample := range samples {
+		agg, exists := pods[sample.PodName]
+		if !exists {
+			agg = &podAggregate{}
+			pods[sample.PodName] = agg
+		}
+		agg.sum += sample.Value
+		agg.count++
+
+		values[i] = sample.Value
+		summary.Max = math.Max(summary.Max, sample.Value)
+	}
+
+	summary.PodCount = len(pods)
+	summary.TopPodValue = math.Inf(-1)
+
+	meanSum := 0.0
+	for pod, agg := range pods {
+		mean := agg.sum / float64(agg.count)
+		meanSum += mean
+
+		if mean > summary.TopPodValue || (mean == summary.TopPodValue && pod < summary.TopPod) {
+			summary.TopPod = pod
+			summary.TopPodValue = mean
+		}
+	}
+	summary.Mean = meanSum / float64(len(pods))
+
+	summary.P95 = percentileOf(values, 95)
+
+	return summary
+}
+
+func (qe *QueryEngine) NodePods(nodeName string) []metrics.NodePod {
+	qe.mutex.RLock()
+	samples := qe.getFilteredSamples(&metrics.QueryRequest{
+		Filters: map[string]string{"node_name": nodeName},
+	})
+	qe.mutex.RUnlock()
+
+	type lastValue struct {
+		value     float64
+		timestamp time.Time
+	}
+
+	pods := make(map[string]*metrics.NodePod)
+	latest := make(map[string]map[string]lastValue)
+
+	for _, sample := range samples {
+		key := sample.ClusterID + "/" + sample.Namespace + "/" + sample.PodName
+		pod, exists := pods[key]
+		if !exists {
+			pod = &metrics.NodePod{
+				ClusterID:   sample.ClusterID,
+				Namespace:   sample.Namespace,
+				PodName:     sample.PodName,
+				LastMetrics: make(map[string]float64),
+			}
+			pods[key] = pod
+			latest[key] = make(map[string]lastValue)
+		}
+
+		if sample.Timestamp.After(pod.LastSeen) {
+			pod.LastSeen = sample.Timestamp
+		}
+		if last, exists := latest[key][sample.MetricName]; !exists || sample.Timestamp.After(last.timestamp) {
+			latest[key][sample.MetricName] = lastValue{value: sample.Value, timestamp: sample.Timestamp}
+			pod.LastMetrics[sample.MetricName] = sample.Value
+		}
+	}
+
+	result := make([]metrics.NodePod, 0, len(pods))
+	for _, pod := range pods {
+		result = append(result, *pod)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].PodName < result[j].PodName
+	})
+
+	return result
+}
+
+func (qe *QueryEngine) PodContainers(podName string) []metrics.ContainerSummary {
+	qe.mutex.RLock()
+	samples := qe.getFilteredSamples(&metrics.QueryRequest{
+		Filters: map[string]string{"pod_name": podName},
+	})
+	qe.mutex.RUnlock()
+
+	type latestValue struct {
+		value     float64
+		timestamp time.Time
+	}
+
+	containers := make(map[string]*metrics.ContainerSummary)
+	sums := make(map[string]map[string]float64)
+	latest := make(map[string]map[string]latestValue)
+
+	for _, sample := range samples {
+		if sample.ContainerName == "" {
+			continue
+		}
+
+		key := sample.ClusterID + "/" + sample.Namespace + "/" + sample.ContainerName
+		container, exists := containers[key]
+		if !exists {
+			container = &metrics.ContainerSummary{
+				ClusterID:     sample.ClusterID,
+				Namespace:     sample.Namespace,
+				PodName:       sample.PodName,
+				ContainerName: sample.ContainerName,
+				Metrics:       make(map[string]metrics.ContainerMetricSummary),
+			}
+			containers[key] = container
+			sums[key] = make(map[string]float64)
+			latest[key] = make(map[string]latestValue)
+		}
+
+		if sample.Timestamp.After(container.LastSeen) {
+			container.LastSeen = sample.Timestamp
+		}
+
+		summary, seen := container.Metrics[sample.MetricName]
+		if !seen {
+			summary.Min = sample.Value
+			summary.Max = sample.Value
+		}
+		summary.Count++
+		summary.Min = math.Min(summary.Min, sample.Value)
+		summary.Max = math.Max(summary.Max, sample.Value)
+
+		sums[key][sample.MetricName] += sample.Value
+		summary.Mean = sums[key][sample.MetricName] / float64(summary.Count)
+
+		if last, exists := latest[key][sample.MetricName]; !exists || !sample.Timestamp.Before(last.timestamp) {
+			latest[key][sample.MetricName] = latestValue{value: sample.Value, timestamp: sample.Timestamp}
+			summary.Latest = sample.Value
+		}
+
+		container.Metrics[sample.MetricName] = summary
+	}
+
+	result := make([]metrics.ContainerSummary, 0, len(containers))
+	for _, container := range containers {
+		result = append(result, *container)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ClusterID != result[j].ClusterID {
+			return result[i].ClusterID < result[j].ClusterID
+		}
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].ContainerName < result[j].ContainerName
+	})
+
+	return result
+}
+
+const clusterSummaryTTL = 30 * time.Second
+
+type cachedClusterSummary struct {
+	summary   metrics.ClusterSummary
+	expiresAt time.Time
+}
+
+func (qe *QueryEngine) ClusterSummary(clusterID string) *metrics.ClusterSummary {
+	if cached, exists := qe.clusterSummaries.Load(clusterID); exists {
+		entry := cached.(*cachedClusterSummary)
+		if time.Now().Before(entry.expiresAt) {
+			summary := entry.summary
+			return &summary
+		}
+	}
+
+	qe.mutex.RLock()
+	summary := qe.executeClusterSummary(clusterID)
+	qe.mutex.RUnlock()
+
+	qe.clusterSummaries.Store(clusterID, &cachedClusterSummary{
+		summary:   *summary,
+		expiresAt: summary.LastUpdated.Add(clusterSummaryTTL),
+	})
+
+	return summary
+}
+
+func (qe *QueryEngine) executeClusterSummary(clusterID string) *metrics.ClusterSummary {
+	now := time.Now()
+	summary := &metrics.ClusterSummary{
+		ClusterID:   clusterID,
+		LastUpdated: now,
+	}
+
+	if hll, exists := qe.labelHLLs["cluster_id:"+clusterID]; exists {
+		summary.TotalPods = hll.Count()
+	}
+
+	samples := qe.getFilteredSamples(&metrics.QueryRequest{
+		Filters: map[string]string{"cluster_id": clusterID},
+	})
+
+	namespaces := make(map[string]bool)
+	latestRestarts := make(map[string]*metrics.MetricPoint)
+	var cpuValues, memoryValues []float64
+	hourAgo := now.Add(-time.Hour)
+
+	for _, sample := range samples {
+		namespaces[sample.Namespace] = true
+
+		switch sample.MetricName {
+		case "cpu_usage":
+			cpuValues = append(cpuValues, sample.Value)
+		case "memory_usage":
+			memoryValues = append(memoryValues, sample.Value)
+		case "pod_restarts":
+			key := sample.Namespace + "/" + sample.PodName
+			if latest, exists := latestRestarts[key]; !exists || sample.Timestamp.After(latest.Timestamp) {
+				latestRestarts[key] = sample
+			}
+		}
+
+		if sample.Timestamp.After(hourAgo) && (sample.IsAnomaly() || sample.Labels["anomaly"] == "true") {
+			summary.AnomalyCountLastHour++
+		}
+	}
+
+	summary.TotalNamespaces = len(namespaces)
+	summary.CPUUsageP95 = percentileOf(cpuValues, 95)
+	summary.MemoryUsageP95 = percentileOf(memoryValues, 95)
+	for _, sample := range latestRestarts {
+		summary.RestartsTotal += sample.Value
+	}
+
+	return summary
+}
+
+func percentileOf(values []float64, percentile float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	idx := int(math.Ceil(percentile/100*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+const (
+	capacityTopContributors = 5
+	maxForecastHours        = 24 * 365 * 10
+)
+
+func (qe *QueryEngine) CapacityForecast(metricName string, threshold float64) *metrics.CapacityForecast {
+	trend := qe.sampler.LinearTrend(metricName)
+
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	type series struct {
+		namespace  string
+		podName    string
+		timestamps []time.Time
+		values     []float64
+	}
+
+	seen := make(map[string]bool)
+	grouped := make(map[string]*series)
+	for _, sample := range append(qe.getAllSamples(), qe.trendSamples(metricName)...) {
+		if sample.MetricName != metricName {
+			continue
+		}
+		identity := sampleIdentity(sample)
+		if seen[identity] {
+			continue
+		}
+		seen[identity] = true
+
+		key := sample.Namespace + "/" + sample.PodName
+		s, exists := grouped[key]
+		if !exists {
+			s = &series{namespace: sample.Namespace, podName: sample.PodName}
+			grouped[key] = s
+		}
+		s.timestamps = append(s.timestamps, sample.Timestamp)
+		s.values = append(s.values, sample.Value)
+	}
+
+	now := time.Now()
+	contributors := make([]metrics.CapacityContributor, 0, len(grouped))
+	for _, s := range grouped {
+		podTrend := sampling.FitLinearTrend(s.timestamps, s.values)
+		contributors = append(contributors, metrics.CapacityContributor{
+			Namespace:         s.namespace,
+			PodName:           s.podName,
+			CurrentValue:      podTrend.CurrentValue,
+			RatePerHour:       podTrend.RatePerHour,
+			EstimatedBreachAt: estimateBreach(podTrend, threshold, now),
+		})
+	}
+
+	sort.Slice(contributors, func(i, j int) bool {
+		return contributors[i].RatePerHour > contributors[j].RatePerHour
+	})
+	if len(contributors) > capacityTopContributors {
+		contributors = contributors[:capacityTopContributors]
+	}
+
+	return &metrics.CapacityForecast{
+		MetricName:        metricName,
+		TargetThreshold:   threshold,
+		CurrentValue:      trend.CurrentValue,
+		RatePerHour:       trend.RatePerHour,
+		EstimatedBreachAt: estimateBreach(trend, threshold, now),
+		Confidence:        trendConfidence(trend.RSquared),
+		RSquared:          trend.RSquared,
+		TopContributors:   contributors,
+	}
+}
+
+func estimateBreach(trend sampling.Trend, threshold float64, now time.Time) *time.Time {
+	if trend.Points == 0 {
+		return nil
+	}
+	if trend.CurrentValue >= threshold {
+		return &now
+	}
+	if trend.RatePerHour <= 0 {
+		return nil
+	}
+
+	hours := (threshold - trend.CurrentValue) / trend.RatePerHour
+	if hours > maxForecastHours {
+		return nil
+	}
+	breach := now.Add(time.Duration(hours * float64(time.Hour)))
+	return &breach
+}
+
+func trendConfidence(rSquared float64) string {
+	switch {
+	case rSquared > 0.8:
+		return "high"
+	case rSquared > 0.5:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+func topKeysByCount(counts map[string]int, limit int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (qe *QueryEngine) NamespaceCardinalities() map[string]uint64 {
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	cardinalities := make(map[string]uint64, len(qe.namespacedHLL))
+	for namespace, hll := range qe.namespacedHLL {
+		cardinalities[namespace] = hll.Count()
+	}
+	return cardinalities
+}
+
+func (qe *QueryEngine) CategorySummaries() []metrics.CategorySummary {
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	sampleCounts := make(map[string]int)
+	for _, samples := range qe.samples {
+		for _, sample := range samples {
+			if sample.Category != "" {
+				sampleCounts[sample.Category]++
+			}
+		}
+	}
+
+	summaries := make([]metrics.CategorySummary, 0, len(qe.categoryHLLs))
+	for category, hll := range qe.categoryHLLs {
+		summary := metrics.CategorySummary{
+			Category:       category,
+			DistinctSeries: hll.Count(),
+			SampleCount:    sampleCounts[category],
+		}
+		if cms, exists := qe.categoryCMS[category]; exists {
+			summary.TotalPoints = cms.GetStats().TotalCount
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Category < summaries[j].Category
+	})
+
+	return summaries
+}
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+// timeSeriesFixture seeds api-1 with cpu_usage values 1, 3, ..., 11 at 10s
+// intervals, plus noise on another pod and another metric that the pod and
+// metric filters must exclude. Points are ingested newest first so bucketing
+// can't rely on arrival order.
+func timeSeriesFixture() []*metrics.MetricPoint {
+	points := NewFixtureBuilder().
+		WithPod("api-1").
+		WithMetric("cpu_usage").
+		WithValues([]float64{1, 3, 5, 7, 9, 11}).
+		WithTimestamps(FixtureEpoch, 10*time.Second).
+		Build()
+	slices.Reverse(points)
+
+	points = append(points, NewFixtureBuilder().
+		WithPod("api-2").
+		WithMetric("cpu_usage").
+		WithValues([]float64{100, 100, 100}).
+		WithTimestamps(FixtureEpoch, 10*time.Second).
+		Build()...)
+	return append(points, NewFixtureBuilder().
+		WithPod("api-1").
+		WithMetric("memory_usage").
+		WithValues([]float64{500, 500}).
+		WithTimestamps(FixtureEpoch.Add(-time.Hour), time.Hour).
+		Build()...)
+}
+
+func TestPodTimeSeries(t *testing.T) {
+	qe := seededTestEngine(t, timeSeriesFixture())
+
+	at := func(seconds int) time.Time { return FixtureEpoch.Add(time.Duration(seconds) * time.Second) }
+
+	tests := []struct {
+		name    string
+		pod     string
+		metric  string
+		buckets int
+		want    []metrics.TimeSeriesPoint
+	}{
+		{
+			// 50s span in 10s buckets; the last sample lands on the end
+			// boundary and folds into the final bucket.
+			name:    "one sample per bucket",
+			pod:     "api-1",
+			metric:  "cpu_usage",
+			buckets: 5,
+			want: []metrics.TimeSeriesPoint{
+				{Timestamp: at(0), Value: 1},
+				{Timestamp: at(10), Value: 3},
+				{Timestamp: at(20), Value: 5},
+				{Timestamp: at(30), Value: 7},
+				{Timestamp: at(40), Value: 10},
+			},
+		},
+		{
+			name:    "mean per bucket",
+			pod:     "api-1",
+			metric:  "cpu_usage",
+			buckets: 2,
+			want: []metrics.TimeSeriesPoint{
+				{Timestamp: at(0), Value: 3},
+				{Timestamp: at(25), Value: 9},
+			},
+		},
+		{
+			name:    "empty buckets are skipped",
+			pod:     "api-1",
+			metric:  "cpu_usage",
+			buckets: 10,
+			want: []metrics.TimeSeriesPoint{
+				{Timestamp: at(0), Value: 1},
+				{Timestamp: at(10), Value: 3},
+				{Timestamp: at(20), Value: 5},
+				{Timestamp: at(30), Value: 7},
+				{Timestamp: at(40), Value: 9},
+				{Timestamp: at(45), Value: 11},
+			},
+		},
+		{
+			name:    "single bucket",
+			pod:     "api-2",
+			metric:  "cpu_usage",
+			buckets: 1,
+			want:    []metrics.TimeSeriesPoint{{Timestamp: at(0), Value: 100}},
+		},
+		{
+			name:    "unknown pod",
+			pod:     "missing",
+			metric:  "cpu_usage",
+			buckets: 5,
+			want:    []metrics.TimeSeriesPoint{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := qe.PodTimeSeries(tt.pod, tt.metric, tt.buckets)
+			if got == nil {
+				t.Fatal("PodTimeSeries() = nil, want a non-nil slice")
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("PodTimeSeries() returned %d points, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				if !got[i].Timestamp.Equal(tt.want[i].Timestamp) || got[i].Value != tt.want[i].Value {
+					t.Errorf("point %d = {%s %v}, want {%s %v}", i,
+						got[i].Timestamp.Format(time.TimeOnly), got[i].Value,
+						tt.want[i].Timestamp.Format(time.TimeOnly), tt.want[i].Value)
+				}
+			}
+		})
+	}
+}
+
+func TestPodTimeSeriesBucketLimits(t *testing.T) {
+	points := NewFixtureBuilder().
+		WithPod("api-1").
+		WithValues(make([]float64, 2*MaxTimeSeriesBuckets)).
+		WithTimestamps(FixtureEpoch, time.Second).
+		Build()
+	qe := seededTestEngine(t, points)
+
+	tests := []struct {
+		name    string
+		buckets int
+		want    int
+	}{
+		{name: "zero uses the default", buckets: 0, want: DefaultTimeSeriesBuckets},
+		{name: "negative uses the default", buckets: -1, want: DefaultTimeSeriesBuckets},
+		{name: "clamped to the maximum", buckets: 5 * MaxTimeSeriesBuckets, want: MaxTimeSeriesBuckets},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := qe.PodTimeSeries("api-1", "", tt.buckets)
+			if len(got) != tt.want {
+				t.Errorf("PodTimeSeries() returned %d points, want %d", len(got), tt.want)
+			}
+			for i := 1; i < len(got); i++ {
+				if !got[i].Timestamp.After(got[i-1].Timestamp) {
+					t.Fatalf("point %d at %s is not after point %d at %s", i, got[i].Timestamp, i-1, got[i-1].Timestamp)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/alerting"
+	"github.com/asmit27rai/kubesight/internal/sampling"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+func (qe *QueryEngine) ScoreAnomaly(metric *metrics.MetricPoint) sampling.AnomalyScore {
+	return qe.sampler.ScoreAnomaly(metric)
+}
+
+func (qe *QueryEngine) AnomalyAlert(rule alerting.AlertRule, metric *metrics.MetricPoint) (alerting.WebhookAlert, bool) {
+	score := qe.ScoreAnomaly(metric)
+	if !score.Anomalous {
+		return alerting.WebhookAlert{}, false
+	}
+
+	severity := rule.Severity
+	if score.Class != string(sampling.AnomalyNone) {
+		severity = alerting.AnomalySeverity(score.Class)
+	}
+
+	return alerting.WebhookAlert{
+		RuleName:   rule.Name,
+		MetricName: metric.MetricName,
+		Value:      metric.Value,
+		Threshold:  rule.Threshold,
+		Severity:   severity,
+		Message: fmt.Sprintf("%s %s anomaly: value %.2f, z-score %.2f",
+			metric.MetricName, score.Class, metric.Value, score.ZScore),
+		Labels:  metric.Labels,
+		FiredAt: time.Now(),
+	}, true
+}
@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	benchSampleCount = 100_000
+	benchNamespaces  = 10
+	benchPodsPerNS   = 20
+)
+
+// newIndexBenchEngine seeds benchSampleCount samples spread evenly over
+// benchNamespaces namespaces, staying under the per-key sample cap so none
+// are evicted.
+func newIndexBenchEngine(b *testing.B) *QueryEngine {
+	b.Helper()
+
+	qe := newTestEngine(b, 1.0)
+	perKey := benchSampleCount / (benchNamespaces * benchPodsPerNS)
+	for i := 0; i < perKey; i++ {
+		for ns := 0; ns < benchNamespaces; ns++ {
+			for pod := 0; pod < benchPodsPerNS; pod++ {
+				qe.ProcessMetric(&metrics.MetricPoint{
+					Timestamp:     FixtureEpoch.Add(time.Duration(i) * time.Second),
+					ClusterID:     "prod",
+					Namespace:     fmt.Sprintf("ns-%d", ns),
+					PodName:       fmt.Sprintf("pod-%d", pod),
+					ContainerName: "main",
+					MetricName:    "cpu_usage",
+					Value:         float64(i),
+				})
+			}
+		}
+	}
+
+	if got := qe.index.Len(); got != benchSampleCount {
+		b.Fatalf("seeded %d samples, want %d", got, benchSampleCount)
+	}
+	return qe
+}
+
+func BenchmarkGetFilteredSamplesNamespace(b *testing.B) {
+	qe := newIndexBenchEngine(b)
+	request := &metrics.QueryRequest{Filters: map[string]string{"namespace": "ns-3"}}
+	want := benchSampleCount / benchNamespaces
+
+	b.Run("indexed", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if got := len(qe.getFilteredSamples(request)); got != want {
+				b.Fatalf("matched %d samples, want %d", got, want)
+			}
+		}
+	})
+
+	b.Run("linear_scan", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			var matched []*metrics.MetricPoint
+			for _, sample := range qe.getAllSamples() {
+				if qe.matchesFilters(sample, request) {
+					matched = append(matched, sample)
+				}
+			}
+			if len(matched) != want {
+				b.Fatalf("matched %d samples, want %d", len(matched), want)
+			}
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"log/slog"
+	"time"
+)
+
+type BloomWarmResult struct {
+	KeysAdded int           `json:"keys_added"`
+	Duration  time.Duration `json:"duration"`
+}
+
+func (qe *QueryEngine) WarmBloomFilter() BloomWarmResult {
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	return qe.warmBloomFilter()
+}
+
+func (qe *QueryEngine) warmBloomFilter() BloomWarmResult {
+	start := time.Now()
+
+	added := 0
+	for key, samples := range qe.samples {
+		if len(samples) == 0 {
+			continue
+		}
+		qe.bloom.Add([]byte(key))
+		added++
+
+		latest := samples[len(samples)-1]
+		if latest.ContainerName != "" {
+			qe.bloom.Add([]byte(qe.getPodMetricKey(latest)))
+		}
+	}
+
+	result := BloomWarmResult{KeysAdded: added, Duration: time.Since(start)}
+	slog.Info("Bloom filter warmed from sample history",
+		"keys_added", result.KeysAdded,
+		"duration", result.Duration)
+	return result
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	DefaultBootstrapSamples    = 200
+	MaxBootstrapSamples        = 10000
+	DefaultBootstrapConfidence = 0.95
+
+	bootstrapSeed = 1
+)
+
+func Bootstrap(samples []float64, percentile float64, B int, confidence float64) (lo, hi float64) {
+	if len(samples) == 0 || B <= 0 {
+		return 0, 0
+	}
+
+	rng := rand.New(rand.NewSource(bootstrapSeed))
+	resample := make([]float64, len(samples))
+	estimates := make([]float64, B)
+	for b := 0; b < B; b++ {
+		for i := range resample {
+			resample[i] = samples[rng.Intn(len(samples))]
+		}
+		sort.Float64s(resample)
+		estimates[b] = interpolatedPercentile(resample, percentile)
+	}
+	sort.Float64s(estimates)
+
+	tail := (1 - confidence) / 2 * 100
+	return interpolatedPercentile(estimates, tail), interpolatedPercentile(estimates, 100-tail)
+}
+
+func interpolatedPercentile(sorted []float64, percentile float64) float64 {
+	index := (percentile / 100.0) * float64(len(sorted)-1)
+	lowerIndex := int(math.Floor(index))
+	upperIndex := int(math.Ceil(index))
+
+	if lowerIndex == upperIndex {
+		return sorted[lowerIndex]
+	}
+	weight := index - float64(lowerIndex)
+	return sorted[lowerIndex]*(1-weight) + sorted[upperIndex]*weight
+}
+
+func bootstrapParams(request *metrics.QueryRequest) (int, float64, error) {
+	samples := DefaultBootstrapSamples
+	if value := request.Filters["bootstrap_samples"]; value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > MaxBootstrapSamples {
+			return 0, 0, &kserrors.ErrInvalidQuery{
+				Query:  request.Query,
+				Reason: fmt.Sprintf("bootstrap_samples must be between 1 and %d: %q", MaxBootstrapSamples, value),
+			}
+		}
+		samples = parsed
+	}
+
+	confidence := DefaultBootstrapConfidence
+	if request.Confidence > 0 && request.Confidence < 1 {
+		confidence = request.Confidence
+	}
+	return samples, confidence, nil
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/alerting"
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+)
+
+const DefaultCardinalityAlertThrottle = 5 * time.Minute
+
+type CardinalityAlert struct {
+	MetricName      string `json:"metric_name"`
+	MaxExpectedKeys int    `json:"max_expected_keys"`
+	WebhookURL      string `json:"webhook_url"`
+}
+
+func (qe *QueryEngine) checkCardinality(metricName string, cms *probabilistic.CountMinSketch) {
+	alert, exists := qe.cardinalityAlerts[metricName]
+	if !exists {
+		return
+	}
+
+	now := time.Now()
+	if last, fired := qe.lastCardinalityAlert[metricName]; fired && now.Sub(last) < qe.cardinalityThrottle {
+		return
+	}
+
+	stats := cms.GetStats()
+	if stats.Depth == 0 {
+		return
+	}
+	keys := int(stats.NonZeroCells / stats.Depth)
+	if keys <= alert.MaxExpectedKeys {
+		return
+	}
+
+	qe.lastCardinalityAlert[metricName] = now
+	go qe.notifyCardinality(alert, keys, now)
+}
+
+func (qe *QueryEngine) notifyCardinality(alert CardinalityAlert, keys int, firedAt time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notifier := &alerting.WebhookNotifier{URL: alert.WebhookURL}
+	err := notifier.Notify(ctx, alerting.WebhookAlert{
+		RuleName:   "cardinality_explosion",
+		MetricName: alert.MetricName,
+		Value:      float64(keys),
+		Threshold:  float64(alert.MaxExpectedKeys),
+		Severity:   "warning",
+		Message: fmt.Sprintf("%s has an estimated %d distinct keys, expected at most %d",
+			alert.MetricName, keys, alert.MaxExpectedKeys),
+		FiredAt: firedAt,
+	})
+	if err != nil {
+		slog.Warn("Failed to send cardinality alert", "metric", alert.MetricName, "error", err)
+	}
+}
@@ -0,0 +1,146 @@
+//go:build integration
+
+// Drives cardinality alerts end to end: metrics go through ProcessMetric and
+// the alert is delivered over HTTP to a local webhook receiver.
+//
+//	go test -tags integration ./internal/engine
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/alerting"
+	"github.com/asmit27rai/kubesight/internal/sampling"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const cardinalityAlertWait = 2 * time.Second
+
+func cardinalityWebhook(t *testing.T) (string, <-chan alerting.WebhookAlert) {
+	t.Helper()
+
+	alerts := make(chan alerting.WebhookAlert, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert alerting.WebhookAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("invalid alert body: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		alerts <- alert
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, alerts
+}
+
+func newCardinalityEngine(t *testing.T, throttle time.Duration, alerts ...CardinalityAlert) *QueryEngine {
+	t.Helper()
+
+	return NewQueryEngine(QueryEngineConfig{
+		HLLPrecision: 12,
+		CMSWidth:     2048,
+		CMSDepth:     4,
+		BloomSize:    1 << 16,
+		BloomHashes:  4,
+		SamplingConfig: sampling.SamplingConfig{
+			BaseRate:         1.0,
+			AnomalyRate:      1.0,
+			WindowSize:       time.Hour,
+			ReservoirSize:    10000,
+			MetricPriorities: map[string]int{},
+		},
+		CardinalityAlerts:        alerts,
+		CardinalityAlertThrottle: throttle,
+	})
+}
+
+// ingestDistinctKeys sends one point for each of n pods, so the metric gains
+// n distinct series keys.
+func ingestDistinctKeys(qe *QueryEngine, metricName string, offset, n int) {
+	for i := offset; i < offset+n; i++ {
+		qe.ProcessMetric(&metrics.MetricPoint{
+			Timestamp:     FixtureEpoch.Add(time.Duration(i) * time.Second),
+			ClusterID:     "prod",
+			Namespace:     "default",
+			PodName:       fmt.Sprintf("request-%d", i),
+			ContainerName: "main",
+			MetricName:    metricName,
+			Value:         1,
+		})
+	}
+}
+
+func expectNoAlert(t *testing.T, alerts <-chan alerting.WebhookAlert, wait time.Duration) {
+	t.Helper()
+
+	select {
+	case alert := <-alerts:
+		t.Fatalf("unexpected alert: %+v", alert)
+	case <-time.After(wait):
+	}
+}
+
+func receiveAlert(t *testing.T, alerts <-chan alerting.WebhookAlert) alerting.WebhookAlert {
+	t.Helper()
+
+	select {
+	case alert := <-alerts:
+		return alert
+	case <-time.After(cardinalityAlertWait):
+		t.Fatalf("no cardinality alert within %s", cardinalityAlertWait)
+	}
+	return alerting.WebhookAlert{}
+}
+
+func TestCardinalityAlertFiresWebhook(t *testing.T) {
+	url, alerts := cardinalityWebhook(t)
+	qe := newCardinalityEngine(t, time.Hour,
+		CardinalityAlert{MetricName: "http_requests", MaxExpectedKeys: 50, WebhookURL: url})
+
+	ingestDistinctKeys(qe, "http_requests", 0, 40)
+	expectNoAlert(t, alerts, 200*time.Millisecond)
+
+	ingestDistinctKeys(qe, "http_requests", 40, 60)
+	alert := receiveAlert(t, alerts)
+	if alert.RuleName != "cardinality_explosion" || alert.MetricName != "http_requests" {
+		t.Errorf("alert = %+v, want a cardinality_explosion alert for http_requests", alert)
+	}
+	if alert.Threshold != 50 || alert.Value <= 50 || alert.Value > 100 {
+		t.Errorf("alert value/threshold = %v/%v, want an estimate in (50, 100] over 50", alert.Value, alert.Threshold)
+	}
+
+	// Throttled: further growth inside the window does not alert again.
+	ingestDistinctKeys(qe, "http_requests", 100, 100)
+	expectNoAlert(t, alerts, 200*time.Millisecond)
+}
+
+func TestCardinalityAlertIgnoresOtherMetrics(t *testing.T) {
+	url, alerts := cardinalityWebhook(t)
+	qe := newCardinalityEngine(t, time.Hour,
+		CardinalityAlert{MetricName: "http_requests", MaxExpectedKeys: 10, WebhookURL: url})
+
+	ingestDistinctKeys(qe, "cpu_usage", 0, 100)
+	expectNoAlert(t, alerts, 200*time.Millisecond)
+}
+
+func TestCardinalityAlertRefiresAfterThrottle(t *testing.T) {
+	url, alerts := cardinalityWebhook(t)
+	throttle := 100 * time.Millisecond
+	qe := newCardinalityEngine(t, throttle,
+		CardinalityAlert{MetricName: "http_requests", MaxExpectedKeys: 10, WebhookURL: url})
+
+	ingestDistinctKeys(qe, "http_requests", 0, 20)
+	receiveAlert(t, alerts)
+
+	time.Sleep(2 * throttle)
+	ingestDistinctKeys(qe, "http_requests", 20, 1)
+	if alert := receiveAlert(t, alerts); alert.MetricName != "http_requests" {
+		t.Errorf("alert = %+v, want a second alert for http_requests", alert)
+	}
+}
@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	DefaultMaxQueryComplexity = 100000
+
+	defaultOperationCost = 1
+)
+
+var operationCost = map[metrics.QueryType]int{
+	metrics.CountDistinct:         1,
+	metrics.FrequencyCount:        1,
+	metrics.Membership:            1,
+	metrics.TopK:                  2,
+	metrics.Sum:                   2,
+	metrics.Average:               2,
+	metrics.ApproxJoin:            5,
+	metrics.Percentile:            10,
+	metrics.HistogramIntersection: 10,
+}
+
+type QueryComplexity struct {
+	QueryType        metrics.QueryType `json:"query_type"`
+	EstimatedSamples int               `json:"estimated_samples"`
+	OperationCost    int               `json:"operation_cost"`
+	Complexity       int               `json:"complexity"`
+	Limit            int               `json:"limit"`
+	Allowed          bool              `json:"allowed"`
+}
+
+type QueryComplexityEstimator struct {
+	engine *QueryEngine
+	limit  int
+}
+
+func NewQueryComplexityEstimator(engine *QueryEngine, limit int) *QueryComplexityEstimator {
+	if limit <= 0 {
+		limit = DefaultMaxQueryComplexity
+	}
+	return &QueryComplexityEstimator{engine: engine, limit: limit}
+}
+
+func (qce *QueryComplexityEstimator) Estimate(request *metrics.QueryRequest) QueryComplexity {
+	qe := qce.engine
+
+	estimated := qe.index.Len()
+	if prefix, ok := qe.stratumPrefix(request.Filters); ok {
+		estimated = len(qe.samplesByPrefix(prefix))
+	} else if count, ok := qe.index.Count(request.Filters); ok {
+		estimated = count
+	}
+
+	cost, exists := operationCost[request.QueryType]
+	if !exists {
+		cost = defaultOperationCost
+	}
+
+	complexity := estimated * cost
+	return QueryComplexity{
+		QueryType:        request.QueryType,
+		EstimatedSamples: estimated,
+		OperationCost:    cost,
+		Complexity:       complexity,
+		Limit:            qce.limit,
+		Allowed:          complexity <= qce.limit,
+	}
+}
+
+func (qe *QueryEngine) EstimateQueryComplexity(request *metrics.QueryRequest) QueryComplexity {
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	return qe.complexity.Estimate(request)
+}
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	DefaultDashboardMaxAge = 10 * time.Second
+
+	dashboardTopNamespaces   = 5
+	dashboardRecentAnomalies = 10
+	dashboardWindow          = time.Hour
+	dashboardBucket          = time.Minute
+)
+
+var dashboardSeriesMetrics = []string{"cpu_usage", "memory_usage"}
+
+func (qe *QueryEngine) DashboardOverview(maxAge time.Duration) *metrics.DashboardOverview {
+	qe.dashboardMutex.Lock()
+	defer qe.dashboardMutex.Unlock()
+
+	if cached := qe.dashboardCache; cached != nil && time.Since(cached.GeneratedAt) <= maxAge {
+		return cached
+	}
+
+	now := time.Now()
+	overview := &metrics.DashboardOverview{
+		GeneratedAt: now,
+		TimeSeries:  make(map[string][]metrics.TimeSeriesPoint, len(dashboardSeriesMetrics)),
+	}
+
+	var wg sync.WaitGroup
+	var seriesMutex sync.Mutex
+	run := func(query func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			query()
+		}()
+	}
+
+	run(func() { overview.TopNamespaces = qe.topNamespacesByPods(dashboardTopNamespaces) })
+	run(func() { overview.ClusterHealth = qe.clusterHealth(now) })
+	run(func() { overview.RecentAnomalies = qe.recentAnomalies(dashboardRecentAnomalies) })
+	run(func() { overview.Throughput = qe.throughput(now) })
+	for _, metricName := range dashboardSeriesMetrics {
+		metricName := metricName
+		run(func() {
+			series := qe.metricTimeSeries(metricName, now.Add(-dashboardWindow), dashboardBucket)
+			seriesMutex.Lock()
+			overview.TimeSeries[metricName] = series
+			seriesMutex.Unlock()
+		})
+	}
+	wg.Wait()
+
+	qe.dashboardCache = overview
+	return overview
+}
+
+func (qe *QueryEngine) topNamespacesByPods(limit int) []metrics.NamespacePodCount {
+	cardinalities := qe.NamespaceCardinalities()
+
+	counts := make([]metrics.NamespacePodCount, 0, len(cardinalities))
+	for namespace, pods := range cardinalities {
+		counts = append(counts, metrics.NamespacePodCount{Namespace: namespace, PodCount: pods})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].PodCount != counts[j].PodCount {
+			return counts[i].PodCount > counts[j].PodCount
+		}
+		return counts[i].Namespace < counts[j].Namespace
+	})
+
+	if len(counts) > limit {
+		counts = counts[:limit]
+	}
+	return counts
+}
+
+func (qe *QueryEngine) clusterHealth(now time.Time) []metrics.ClusterHealth {
+	qe.mutex.RLock()
+	samples := qe.getFilteredSamples(&metrics.QueryRequest{
+		TimeRange: metrics.TimeRange{Start: now.Add(-PressureWindow)},
+	})
+	qe.mutex.RUnlock()
+
+	byCluster := make(map[string][]*metrics.MetricPoint)
+	for _, sample := range samples {
+		byCluster[sample.ClusterID] = append(byCluster[sample.ClusterID], sample)
+	}
+
+	health := make([]metrics.ClusterHealth, 0, len(byCluster))
+	for clusterID, clusterSamples := range byCluster {
+		score := qe.pressureScore(clusterSamples)
+		health = append(health, metrics.ClusterHealth{
+			ClusterID: clusterID,
+			Score:     score.Score,
+			Breakdown: score.Breakdown,
+			Severity:  score.Severity,
+		})
+	}
+	sort.Slice(health, func(i, j int) bool {
+		return health[i].ClusterID < health[j].ClusterID
+	})
+
+	return health
+}
+
+func (qe *QueryEngine) recentAnomalies(limit int) []*metrics.MetricPoint {
+	qe.mutex.RLock()
+	var anomalies []*metrics.MetricPoint
+	for _, samples := range qe.samples {
+		for _, sample := range samples {
+			if sample.IsAnomaly() || sample.Labels["anomaly"] == "true" {
+				anomalies = append(anomalies, sample)
+			}
+		}
+	}
+	qe.mutex.RUnlock()
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		return anomalies[i].Timestamp.After(anomalies[j].Timestamp)
+	})
+	if len(anomalies) > limit {
+		anomalies = anomalies[:limit]
+	}
+
+	recent := make([]*metrics.MetricPoint, len(anomalies))
+	for i, sample := range anomalies {
+		copied := *sample
+		recent[i] = &copied
+	}
+	return recent
+}
+
+func (qe *QueryEngine) throughput(now time.Time) metrics.DashboardThroughput {
+	qe.mutex.RLock()
+	stats := qe.stats
+	elapsed := now.Sub(qe.startedAt).Seconds()
+	qe.mutex.RUnlock()
+
+	throughput := metrics.DashboardThroughput{
+		TotalReceived: stats.TotalReceived,
+		TotalSampled:  stats.TotalSampled,
+		TotalQueries:  stats.TotalQueries,
+		AvgLatency:    stats.AvgLatency,
+	}
+	if elapsed > 0 {
+		throughput.ReceivedPerSecond = float64(stats.TotalReceived) / elapsed
+		throughput.SampledPerSecond = float64(stats.TotalSampled) / elapsed
+	}
+	return throughput
+}
+
+func (qe *QueryEngine) metricTimeSeries(metricName string, start time.Time, width time.Duration) []metrics.TimeSeriesPoint {
+	qe.mutex.RLock()
+	samples := qe.getFilteredSamples(&metrics.QueryRequest{
+		TimeRange: metrics.TimeRange{Start: start},
+		Filters:   map[string]string{"metric_name": metricName},
+	})
+	qe.mutex.RUnlock()
+
+	start = start.Truncate(width)
+	buckets := int(dashboardWindow/width) + 1
+	sums := make([]float64, buckets)
+	counts := make([]int, buckets)
+	for _, sample := range samples {
+		idx := int(sample.Timestamp.Sub(start) / width)
+		if idx < 0 || idx >= buckets {
+			continue
+		}
+		sums[idx] += sample.Value
+		counts[idx]++
+	}
+
+	points := make([]metrics.TimeSeriesPoint, 0, buckets)
+	for i := range sums {
+		if counts[i] == 0 {
+			continue
+		}
+		points = append(points, metrics.TimeSeriesPoint{
+			Timestamp: start.Add(time.Duration(i) * width),
+			Value:     sums[i] / float64(counts[i]),
+		})
+	}
+	return points
+}
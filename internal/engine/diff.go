@@ -0,0 +1,194 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const klSmoothing = 1e-9
+
+func (qe *QueryEngine) ExecuteDiff(request *metrics.DiffRequest) (*metrics.DiffResult, error) {
+	if request.Base == nil || request.Compare == nil {
+		return nil, &kserrors.ErrInvalidQuery{Reason: "diff requires both base and compare queries"}
+	}
+
+	base, err := qe.ExecuteQuery(request.Base)
+	if err != nil {
+		return nil, fmt.Errorf("base query failed: %w", err)
+	}
+	compare, err := qe.ExecuteQuery(request.Compare)
+	if err != nil {
+		return nil, fmt.Errorf("compare query failed: %w", err)
+	}
+
+	diff, err := diffResults(base.Result, compare.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metrics.DiffResult{
+		Base:    base,
+		Compare: compare,
+		Diff:    diff,
+	}, nil
+}
+
+func diffResults(base, compare interface{}) (interface{}, error) {
+	switch baseValue := base.(type) {
+	case *metrics.TopKResult:
+		if compareValue, ok := compare.(*metrics.TopKResult); ok {
+			return diffTopK(baseValue, compareValue), nil
+		}
+	case *metrics.HistogramResult:
+		if compareValue, ok := compare.(*metrics.HistogramResult); ok {
+			return &metrics.HistogramDiff{
+				KLDivergence: klDivergence(baseValue.Buckets, compareValue.Buckets),
+			}, nil
+		}
+	}
+
+	baseScalar, baseOK := scalarValue(base)
+	compareScalar, compareOK := scalarValue(compare)
+	if !baseOK || !compareOK {
+		return nil, &kserrors.ErrInvalidQuery{
+			Reason: fmt.Sprintf("cannot diff results of type %T and %T", base, compare),
+		}
+	}
+	return diffScalar(baseScalar, compareScalar), nil
+}
+
+func scalarValue(result interface{}) (float64, bool) {
+	switch value := result.(type) {
+	case float64:
+		return value, true
+	case uint64:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case *metrics.ApproximateCountResult:
+		return float64(value.Count), true
+	case *metrics.PercentileResult:
+		return value.Value, true
+	default:
+		return 0, false
+	}
+}
+
+func diffScalar(base, compare float64) *metrics.ScalarDiff {
+	diff := &metrics.ScalarDiff{
+		BaseResult:    base,
+		CompareResult: compare,
+		AbsoluteDiff:  compare - base,
+	}
+	if base != 0 {
+		relative := (compare - base) / math.Abs(base) * 100
+		diff.RelativeDiffPercent = &relative
+	}
+	return diff
+}
+
+func diffTopK(base, compare *metrics.TopKResult) *metrics.TopKDiff {
+	baseKeys := make(map[string]bool, len(base.Items))
+	for _, item := range base.Items {
+		baseKeys[item.Key] = true
+	}
+	compareKeys := make(map[string]bool, len(compare.Items))
+	for _, item := range compare.Items {
+		compareKeys[item.Key] = true
+	}
+
+	diff := &metrics.TopKDiff{
+		Common:  make([]string, 0),
+		Added:   make([]string, 0),
+		Removed: make([]string, 0),
+	}
+	for key := range baseKeys {
+		if compareKeys[key] {
+			diff.Common = append(diff.Common, key)
+		} else {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+	for key := range compareKeys {
+		if !baseKeys[key] {
+			diff.Added = append(diff.Added, key)
+		}
+	}
+	sort.Strings(diff.Common)
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+
+	union := len(diff.Common) + len(diff.Added) + len(diff.Removed)
+	if union == 0 {
+		diff.JaccardOverlap = 1
+	} else {
+		diff.JaccardOverlap = float64(len(diff.Common)) / float64(union)
+	}
+	return diff
+}
+
+func klDivergence(base, compare []metrics.HistogramBucket) float64 {
+	q, p := alignHistograms(base, compare)
+	if p == nil {
+		return 0
+	}
+
+	smoothing := 1 + klSmoothing*float64(len(p))
+	divergence := 0.0
+	for i := range p {
+		pi := (p[i] + klSmoothing) / smoothing
+		qi := (q[i] + klSmoothing) / smoothing
+		divergence += pi * math.Log(pi/qi)
+	}
+	return divergence
+}
+
+func alignHistograms(a, b []metrics.HistogramBucket) ([]float64, []float64) {
+	type bucketBounds struct {
+		lower, upper float64
+	}
+
+	aCounts := make(map[bucketBounds]float64, len(a))
+	bCounts := make(map[bucketBounds]float64, len(b))
+	var aTotal, bTotal float64
+
+	for _, bucket := range a {
+		aCounts[bucketBounds{bucket.Lower, bucket.Upper}] += float64(bucket.Count)
+		aTotal += float64(bucket.Count)
+	}
+	for _, bucket := range b {
+		bCounts[bucketBounds{bucket.Lower, bucket.Upper}] += float64(bucket.Count)
+		bTotal += float64(bucket.Count)
+	}
+	if aTotal == 0 || bTotal == 0 {
+		return nil, nil
+	}
+
+	bounds := make([]bucketBounds, 0, len(aCounts)+len(bCounts))
+	for bound := range aCounts {
+		bounds = append(bounds, bound)
+	}
+	for bound := range bCounts {
+		if _, exists := aCounts[bound]; !exists {
+			bounds = append(bounds, bound)
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool {
+		if bounds[i].lower != bounds[j].lower {
+			return bounds[i].lower < bounds[j].lower
+		}
+		return bounds[i].upper < bounds[j].upper
+	})
+
+	aProbs := make([]float64, len(bounds))
+	bProbs := make([]float64, len(bounds))
+	for i, bound := range bounds {
+		aProbs[i] = aCounts[bound] / aTotal
+		bProbs[i] = bCounts[bound] / bTotal
+	}
+	return aProbs, bProbs
+}
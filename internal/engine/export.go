@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const DefaultExportPageSize = 100
+
+type ExportCursor struct {
+	Key    string
+	Offset int
+}
+
+func (qe *QueryEngine) ExportPage(request *metrics.QueryRequest, cursor ExportCursor, pageSize int) ([]*metrics.MetricPoint, ExportCursor, bool) {
+	if pageSize <= 0 {
+		pageSize = DefaultExportPageSize
+	}
+
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	page := make([]*metrics.MetricPoint, 0, pageSize)
+
+	start := sort.SearchStrings(qe.sampleKeys, cursor.Key)
+	for _, key := range qe.sampleKeys[start:] {
+		offset := 0
+		if key == cursor.Key {
+			offset = cursor.Offset
+		}
+
+		samples := qe.samples[key]
+		for i := offset; i < len(samples); i++ {
+			if !qe.matchesFilters(samples[i], request) {
+				continue
+			}
+			page = append(page, samples[i])
+			if len(page) == pageSize {
+				return page, ExportCursor{Key: key, Offset: i + 1}, false
+			}
+		}
+	}
+
+	return page, ExportCursor{}, true
+}
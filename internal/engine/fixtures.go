@@ -0,0 +1,186 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+var FixtureEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type FixtureBuilder struct {
+	clusterID     string
+	namespace     string
+	pods          []string
+	containerName string
+	nodeName      string
+	metricName    string
+	category      string
+	unit          string
+	labels        map[string]string
+	values        []float64
+	start         time.Time
+	interval      time.Duration
+}
+
+func NewFixtureBuilder() *FixtureBuilder {
+	return &FixtureBuilder{
+		clusterID:     "test-cluster",
+		namespace:     "default",
+		pods:          []string{"pod-1"},
+		containerName: "container-1",
+		metricName:    "cpu_usage",
+		unit:          "percent",
+		labels:        make(map[string]string),
+		start:         FixtureEpoch,
+		interval:      time.Minute,
+	}
+}
+
+func (fb *FixtureBuilder) WithCluster(clusterID string) *FixtureBuilder {
+	fb.clusterID = clusterID
+	return fb
+}
+
+func (fb *FixtureBuilder) WithNamespace(namespace string) *FixtureBuilder {
+	fb.namespace = namespace
+	return fb
+}
+
+func (fb *FixtureBuilder) WithPod(podName string) *FixtureBuilder {
+	fb.pods = []string{podName}
+	return fb
+}
+
+func (fb *FixtureBuilder) WithPods(podNames ...string) *FixtureBuilder {
+	if len(podNames) > 0 {
+		fb.pods = append([]string{}, podNames...)
+	}
+	return fb
+}
+
+func (fb *FixtureBuilder) WithContainer(containerName string) *FixtureBuilder {
+	fb.containerName = containerName
+	return fb
+}
+
+func (fb *FixtureBuilder) WithNode(nodeName string) *FixtureBuilder {
+	fb.nodeName = nodeName
+	return fb
+}
+
+func (fb *FixtureBuilder) WithMetric(metricName string) *FixtureBuilder {
+	fb.metricName = metricName
+	return fb
+}
+
+func (fb *FixtureBuilder) WithCategory(category string) *FixtureBuilder {
+	fb.category = category
+	return fb
+}
+
+func (fb *FixtureBuilder) WithUnit(unit string) *FixtureBuilder {
+	fb.unit = unit
+	return fb
+}
+
+func (fb *FixtureBuilder) WithLabel(key, value string) *FixtureBuilder {
+	fb.labels[key] = value
+	return fb
+}
+
+func (fb *FixtureBuilder) WithValues(values []float64) *FixtureBuilder {
+	fb.values = append([]float64{}, values...)
+	return fb
+}
+
+func (fb *FixtureBuilder) WithTimestamps(start time.Time, interval time.Duration) *FixtureBuilder {
+	fb.start = start
+	fb.interval = interval
+	return fb
+}
+
+func (fb *FixtureBuilder) Build() []*metrics.MetricPoint {
+	points := make([]*metrics.MetricPoint, 0, len(fb.values))
+
+	for i, value := range fb.values {
+		labels := make(map[string]string, len(fb.labels))
+		for key, val := range fb.labels {
+			labels[key] = val
+		}
+
+		points = append(points, &metrics.MetricPoint{
+			Timestamp:     fb.start.Add(time.Duration(i) * fb.interval),
+			ClusterID:     fb.clusterID,
+			Namespace:     fb.namespace,
+			PodName:       fb.pods[i%len(fb.pods)],
+			ContainerName: fb.containerName,
+			NodeName:      fb.nodeName,
+			MetricName:    fb.metricName,
+			Category:      fb.category,
+			Value:         value,
+			Unit:          fb.unit,
+			Labels:        labels,
+		})
+	}
+
+	return points
+}
+
+func SeedEngine(qe *QueryEngine, fixtures []*metrics.MetricPoint) {
+	for _, metric := range fixtures {
+		qe.ProcessMetric(metric)
+	}
+}
+
+func HighCPUFixture() []*metrics.MetricPoint {
+	return NewFixtureBuilder().
+		WithCluster("prod").
+		WithNamespace("default").
+		WithPods("api-1", "api-2", "api-3").
+		WithMetric("cpu_usage").
+		WithValues(rampValues(30, 0.85, 0.99)).
+		Build()
+}
+
+func NormalTrafficFixture() []*metrics.MetricPoint {
+	var fixtures []*metrics.MetricPoint
+	for _, metricName := range []string{"network_in", "network_out"} {
+		fixtures = append(fixtures, NewFixtureBuilder().
+			WithCluster("prod").
+			WithNamespace("default").
+			WithPods("web-1", "web-2").
+			WithMetric(metricName).
+			WithUnit("bytes").
+			WithValues(rampValues(30, 1000, 2000)).
+			Build()...)
+	}
+	return fixtures
+}
+
+func MultiClusterFixture() []*metrics.MetricPoint {
+	var fixtures []*metrics.MetricPoint
+	for i, clusterID := range []string{"prod", "staging", "dev"} {
+		base := 0.2 + 0.2*float64(i)
+		fixtures = append(fixtures, NewFixtureBuilder().
+			WithCluster(clusterID).
+			WithNamespace("default").
+			WithPods("app-1", "app-2").
+			WithMetric("cpu_usage").
+			WithValues(rampValues(10, base, base+0.1)).
+			Build()...)
+	}
+	return fixtures
+}
+
+func rampValues(count int, from, to float64) []float64 {
+	values := make([]float64, count)
+	if count == 1 {
+		values[0] = from
+		return values
+	}
+	for i := range values {
+		values[i] = from + (to-from)*float64(i)/float64(count-1)
+	}
+	return values
+}
@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+func TestFixtureBuilderIsDeterministic(t *testing.T) {
+	build := func() []*metrics.MetricPoint {
+		return NewFixtureBuilder().
+			WithCluster("prod").
+			WithNamespace("payments").
+			WithPods("api-1", "api-2").
+			WithMetric("memory_usage").
+			WithLabel("tier", "backend").
+			WithValues([]float64{0.1, 0.5, 0.9}).
+			WithTimestamps(FixtureEpoch, 30*time.Second).
+			Build()
+	}
+
+	first, second := build(), build()
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("FixtureBuilder produced different points for the same configuration")
+	}
+
+	wantPods := []string{"api-1", "api-2", "api-1"}
+	for i, point := range first {
+		if point.PodName != wantPods[i] {
+			t.Errorf("point %d pod = %s, want %s", i, point.PodName, wantPods[i])
+		}
+		if want := FixtureEpoch.Add(time.Duration(i) * 30 * time.Second); !point.Timestamp.Equal(want) {
+			t.Errorf("point %d timestamp = %s, want %s", i, point.Timestamp, want)
+		}
+		if point.Labels["tier"] != "backend" {
+			t.Errorf("point %d is missing the tier label", i)
+		}
+	}
+
+	first[0].Labels["tier"] = "mutated"
+	if first[1].Labels["tier"] != "backend" {
+		t.Fatal("Build() shares label maps between points")
+	}
+}
+
+func TestPrebuiltFixtures(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  []*metrics.MetricPoint
+		groupBy  string
+		expected map[string]uint64
+	}{
+		{"high cpu", HighCPUFixture(), "metric_name", map[string]uint64{"cpu_usage": 3}},
+		{"normal traffic", NormalTrafficFixture(), "metric_name", map[string]uint64{"network_in": 2, "network_out": 2}},
+		{"multi cluster", MultiClusterFixture(), "cluster_id", map[string]uint64{"prod": 2, "staging": 2, "dev": 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qe := seededTestEngine(t, tt.fixture)
+
+			result, err := qe.ExecuteQuery(&metrics.QueryRequest{
+				QueryType:    metrics.CountDistinct,
+				GroupByLabel: tt.groupBy,
+			})
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			if groups := result.Result.(map[string]uint64); !reflect.DeepEqual(groups, tt.expected) {
+				t.Fatalf("pods per %s = %v, want %v", tt.groupBy, groups, tt.expected)
+			}
+			if got := qe.GetStats().TotalSampled; got != uint64(len(tt.fixture)) {
+				t.Fatalf("TotalSampled = %d, want every one of the %d fixture points", got, len(tt.fixture))
+			}
+		})
+	}
+}
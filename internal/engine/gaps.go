@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"sort"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	DefaultGapInterval  = 30 * time.Second
+	GapAnomalyThreshold = 5 * time.Minute
+
+	ChangeEventGapAnomaly = "gap_anomaly"
+)
+
+type ChangeEvent struct {
+	Type      string       `json:"type"`
+	Stratum   string       `json:"stratum"`
+	Gap       *metrics.Gap `json:"gap,omitempty"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+func (qe *QueryEngine) DetectGaps(stratum string, expectedInterval time.Duration) []metrics.Gap {
+	qe.mutex.RLock()
+	timestamps := make([]time.Time, 0, len(qe.samples[stratum]))
+	for _, sample := range qe.samples[stratum] {
+		timestamps = append(timestamps, sample.Timestamp)
+	}
+	qe.mutex.RUnlock()
+
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i].Before(timestamps[j])
+	})
+
+	threshold := expectedInterval * 3 / 2
+	gaps := make([]metrics.Gap, 0)
+	for i := 1; i < len(timestamps); i++ {
+		if delta := timestamps[i].Sub(timestamps[i-1]); delta > threshold {
+			gaps = append(gaps, metrics.Gap{
+				Start:    timestamps[i-1],
+				End:      timestamps[i],
+				Duration: delta,
+			})
+		}
+	}
+
+	return gaps
+}
+
+func (qe *QueryEngine) SubscribeChanges() (<-chan ChangeEvent, func()) {
+	qe.listenerMutex.Lock()
+	defer qe.listenerMutex.Unlock()
+
+	ch := make(chan ChangeEvent, 16)
+	id := qe.nextListenerID
+	qe.nextListenerID++
+	qe.changeListeners[id] = ch
+
+	unsubscribe := func() {
+		qe.listenerMutex.Lock()
+		defer qe.listenerMutex.Unlock()
+
+		if _, exists := qe.changeListeners[id]; exists {
+			delete(qe.changeListeners, id)
+			close(ch)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+func (qe *QueryEngine) detectIngestGap(stratum string, previous, current *metrics.MetricPoint) {
+	delta := current.Timestamp.Sub(previous.Timestamp)
+	if delta <= GapAnomalyThreshold {
+		return
+	}
+
+	qe.notifyChange(ChangeEvent{
+		Type:    ChangeEventGapAnomaly,
+		Stratum: stratum,
+		Gap: &metrics.Gap{
+			Start:    previous.Timestamp,
+			End:      current.Timestamp,
+			Duration: delta,
+		},
+		Timestamp: time.Now(),
+	})
+}
+
+func (qe *QueryEngine) notifyChange(event ChangeEvent) {
+	qe.listenerMutex.Lock()
+	defer qe.listenerMutex.Unlock()
+
+	for _, ch := range qe.changeListeners {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+func (qe *QueryEngine) cloneHLL() *probabilistic.HyperLogLog {
+	return qe.hll.Clone()
+}
+
+func historicalRequest(request *metrics.QueryRequest, asOf time.Time) *metrics.QueryRequest {
+	historical := *request
+	if historical.TimeRange.End.IsZero() || historical.TimeRange.End.After(asOf) {
+		historical.TimeRange.End = asOf
+	}
+	return &historical
+}
+
+func (qe *QueryEngine) dispatchHistoricalQuery(request *metrics.QueryRequest, asOf time.Time) (*metrics.QueryResult, error) {
+	switch request.QueryType {
+	case metrics.CountDistinct:
+		if request.GroupByLabel != "" {
+			return qe.executeGroupedCountDistinctAt(request)
+		}
+		return qe.executeCountDistinctAt(request, asOf)
+	case metrics.TopK:
+		return qe.executeSpaceSavingTopK(request)
+	case metrics.Membership, metrics.FrequencyCount:
+		return qe.executeSampleFrequencyAt(request)
+	default:
+		return qe.dispatchQuery(request, &QueryPlan{Backend: BackendSamples})
+	}
+}
+
+func (qe *QueryEngine) executeCountDistinctAt(request *metrics.QueryRequest, asOf time.Time) (*metrics.QueryResult, error) {
+	hll := qe.cloneHLL()
+	replayed := 0
+
+	if qe.hasSamplesAfter(asOf) {
+		hll.Clear()
+		for _, sample := range qe.getFilteredSamples(request) {
+			hll.Add([]byte(qe.getMetricKey(sample)))
+			replayed++
+		}
+	} else {
+		replayed = qe.index.Len()
+	}
+
+	estimatedError := hll.EstimateError()
+
+	return &metrics.QueryResult{
+		ID:    request.ID,
+		Query: request.Query,
+		Result: &metrics.ApproximateCountResult{
+			Count:          hll.Count(),
+			EstimatedError: estimatedError,
+		},
+		Error:         &estimatedError,
+		SampleSize:    replayed,
+		IsApproximate: true,
+	}, nil
+}
+
+func (qe *QueryEngine) executeGroupedCountDistinctAt(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+	groupHLLs := make(map[string]*probabilistic.HyperLogLog)
+	samples := qe.getFilteredSamples(request)
+
+	for _, sample := range samples {
+		value := labelValue(sample, request.GroupByLabel)
+		if value == "" || sample.PodName == "" {
+			continue
+		}
+
+		hll, exists := groupHLLs[value]
+		if !exists {
+			hll = probabilistic.NewHyperLogLog(qe.labelPrecision)
+			groupHLLs[value] = hll
+		}
+		hll.Add([]byte(sample.PodName))
+	}
+
+	groups := make(map[string]uint64, len(groupHLLs))
+	var estimatedError float64
+	for value, hll := range groupHLLs {
+		groups[value] = hll.Count()
+		estimatedError = hll.EstimateError()
+	}
+
+	return &metrics.QueryResult{
+		ID:            request.ID,
+		Query:         request.Query,
+		Result:        groups,
+		Error:         &estimatedError,
+		SampleSize:    len(samples),
+		IsApproximate: true,
+	}, nil
+}
+
+func (qe *QueryEngine) executeSampleFrequencyAt(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+	var item string
+	if request.QueryType == metrics.Membership {
+		item = qe.extractMembershipItem(request.Query)
+	} else {
+		item = qe.extractFrequencyItem(request.Query)
+	}
+	if item == "" {
+		return nil, &kserrors.ErrInvalidQuery{
+			Query:  request.Query,
+			Reason: fmt.Sprintf("no item specified for %s", request.QueryType),
+		}
+	}
+
+	samples := qe.getFilteredSamples(request)
+	count := 0
+	for _, sample := range samples {
+		if qe.getMetricKey(sample) == item {
+			count++
+		}
+	}
+
+	var result interface{}
+	if request.QueryType == metrics.Membership {
+		result = &metrics.MembershipResult{Member: count > 0}
+	} else {
+		result = map[string]interface{}{
+			"item":  item,
+			"count": count,
+		}
+	}
+
+	return &metrics.QueryResult{
+		ID:            request.ID,
+		Query:         request.Query,
+		Result:        result,
+		SampleSize:    len(samples),
+		IsApproximate: false,
+	}, nil
+}
+
+func (qe *QueryEngine) hasSamplesAfter(asOf time.Time) bool {
+	for _, samples := range qe.samples {
+		if len(samples) > 0 && samples[len(samples)-1].Timestamp.After(asOf) {
+			return true
+		}
+	}
+	return false
+}
+
+func labelValue(metric *metrics.MetricPoint, label string) string {
+	switch label {
+	case "cluster_id":
+		return metric.ClusterID
+	case "namespace":
+		return metric.Namespace
+	case "container_name":
+		return metric.ContainerName
+	case "metric_name":
+		return metric.MetricName
+	default:
+		return metric.Labels[label]
+	}
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+func TestExecuteQueryAtTimeReplaysHistory(t *testing.T) {
+	qe := seededTestEngine(t, HighCPUFixture())
+	t0 := FixtureEpoch.Add(29 * time.Minute)
+
+	countRequest := &metrics.QueryRequest{QueryType: metrics.CountDistinct, Query: "COUNT_DISTINCT(pod_name)"}
+	avgRequest := &metrics.QueryRequest{QueryType: metrics.Average, Filters: map[string]string{"metric_name": "cpu_usage"}}
+
+	before, err := qe.ExecuteQueryAtTime(countRequest, t0)
+	if err != nil {
+		t.Fatalf("ExecuteQueryAtTime() error = %v", err)
+	}
+	avgBefore, err := qe.ExecuteQueryAtTime(avgRequest, t0)
+	if err != nil {
+		t.Fatalf("ExecuteQueryAtTime() error = %v", err)
+	}
+
+	SeedEngine(qe, NewFixtureBuilder().
+		WithCluster("prod").
+		WithPods("api-4", "api-5").
+		WithValues([]float64{0.1, 0.1, 0.1, 0.1, 0.1}).
+		WithTimestamps(t0.Add(time.Minute), time.Minute).
+		Build())
+
+	atT0, err := qe.ExecuteQueryAtTime(countRequest, t0)
+	if err != nil {
+		t.Fatalf("ExecuteQueryAtTime() error = %v", err)
+	}
+	later, err := qe.ExecuteQueryAtTime(countRequest, t0.Add(5*time.Minute))
+	if err != nil {
+		t.Fatalf("ExecuteQueryAtTime() error = %v", err)
+	}
+
+	countAt := func(result *metrics.QueryResult) uint64 {
+		return result.Result.(*metrics.ApproximateCountResult).Count
+	}
+	if countAt(atT0) != countAt(before) {
+		t.Fatalf("count at t0 changed after later ingestion: %d, was %d", countAt(atT0), countAt(before))
+	}
+	if countAt(atT0) != 3 || countAt(later) != 5 {
+		t.Fatalf("count at t0 = %d, at t0+5m = %d, want 3 and 5", countAt(atT0), countAt(later))
+	}
+
+	avgAtT0, err := qe.ExecuteQueryAtTime(avgRequest, t0)
+	if err != nil {
+		t.Fatalf("ExecuteQueryAtTime() error = %v", err)
+	}
+	avgLater, err := qe.ExecuteQueryAtTime(avgRequest, t0.Add(5*time.Minute))
+	if err != nil {
+		t.Fatalf("ExecuteQueryAtTime() error = %v", err)
+	}
+	if avgAtT0.Result != avgBefore.Result || avgAtT0.SampleSize != 30 {
+		t.Fatalf("average at t0 = %v over %d samples, want %v over 30", avgAtT0.Result, avgAtT0.SampleSize, avgBefore.Result)
+	}
+	if avgLater.Result == avgAtT0.Result || avgLater.SampleSize != 35 {
+		t.Fatalf("average at t0+5m = %v over %d samples, want a different value over 35", avgLater.Result, avgLater.SampleSize)
+	}
+}
+
+func TestExecuteQueryAtTimeRequiresTime(t *testing.T) {
+	qe := seededTestEngine(t, HighCPUFixture())
+
+	_, err := qe.ExecuteQueryAtTime(&metrics.QueryRequest{QueryType: metrics.Average}, time.Time{})
+	var invalid *kserrors.ErrInvalidQuery
+	if !errors.As(err, &invalid) {
+		t.Fatalf("ExecuteQueryAtTime() error = %v, want ErrInvalidQuery", err)
+	}
+}
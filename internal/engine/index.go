@@ -0,0 +1,210 @@
+package engine
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const minIndexCompactionSize = 1024
+
+type MetricIndex struct {
+	points     []*metrics.MetricPoint
+	positions  map[*metrics.MetricPoint]int
+	tombstones int
+
+	byCluster    map[string][]int
+	byNamespace  map[string][]int
+	byPodName    map[string][]int
+	byContainer  map[string][]int
+	byMetricName map[string][]int
+}
+
+func NewMetricIndex() *MetricIndex {
+	return &MetricIndex{
+		points:       make([]*metrics.MetricPoint, 0),
+		positions:    make(map[*metrics.MetricPoint]int),
+		byCluster:    make(map[string][]int),
+		byNamespace:  make(map[string][]int),
+		byPodName:    make(map[string][]int),
+		byContainer:  make(map[string][]int),
+		byMetricName: make(map[string][]int),
+	}
+}
+
+func (mi *MetricIndex) Add(metric *metrics.MetricPoint) {
+	pos := len(mi.points)
+	mi.points = append(mi.points, metric)
+	mi.positions[metric] = pos
+
+	mi.byCluster[metric.ClusterID] = append(mi.byCluster[metric.ClusterID], pos)
+	mi.byNamespace[metric.Namespace] = append(mi.byNamespace[metric.Namespace], pos)
+	mi.byPodName[metric.PodName] = append(mi.byPodName[metric.PodName], pos)
+	mi.byContainer[metric.ContainerName] = append(mi.byContainer[metric.ContainerName], pos)
+	mi.byMetricName[metric.MetricName] = append(mi.byMetricName[metric.MetricName], pos)
+}
+
+func (mi *MetricIndex) Remove(metric *metrics.MetricPoint) {
+	pos, exists := mi.positions[metric]
+	if !exists {
+		return
+	}
+
+	mi.points[pos] = nil
+	delete(mi.positions, metric)
+	mi.tombstones++
+
+	if mi.tombstones > minIndexCompactionSize && mi.tombstones > len(mi.points)/2 {
+		mi.rebuild()
+	}
+}
+
+func (mi *MetricIndex) Len() int {
+	return len(mi.positions)
+}
+
+func (mi *MetricIndex) Lookup(filters map[string]string) ([]*metrics.MetricPoint, bool) {
+	matched, ok := mi.matchPositions(filters)
+	if !ok {
+		return nil, false
+	}
+
+	result := make([]*metrics.MetricPoint, 0, len(matched))
+	for _, pos := range matched {
+		if point := mi.points[pos]; point != nil {
+			result = append(result, point)
+		}
+	}
+	return result, true
+}
+
+func (mi *MetricIndex) Count(filters map[string]string) (int, bool) {
+	matched, ok := mi.matchPositions(filters)
+	return len(matched), ok
+}
+
+func (mi *MetricIndex) matchPositions(filters map[string]string) ([]int, bool) {
+	var lists [][]int
+	for key, value := range filters {
+		var index map[string][]int
+		switch key {
+		case "cluster_id":
+			index = mi.byCluster
+		case "namespace":
+			index = mi.byNamespace
+		case "pod_name":
+			index = mi.byPodName
+		case "container_name":
+			index = mi.byContainer
+		case "metric_name":
+			index = mi.byMetricName
+		default:
+			continue
+		}
+
+		positions, exists := index[value]
+		if !exists {
+			return []int{}, true
+		}
+		lists = append(lists, positions)
+	}
+
+	if len(lists) == 0 {
+		return nil, false
+	}
+
+	sort.Slice(lists, func(i, j int) bool {
+		return len(lists[i]) < len(lists[j])
+	})
+
+	matched := lists[0]
+	for _, list := range lists[1:] {
+		matched = intersectPositions(matched, list)
+		if len(matched) == 0 {
+			break
+		}
+	}
+	return matched, true
+}
+
+func (mi *MetricIndex) rebuild() {
+	live := make([]*metrics.MetricPoint, 0, len(mi.positions))
+	for _, point := range mi.points {
+		if point != nil {
+			live = append(live, point)
+		}
+	}
+
+	*mi = *NewMetricIndex()
+	for _, point := range live {
+		mi.Add(point)
+	}
+}
+
+func intersectPositions(a, b []int) []int {
+	result := make([]int, 0, len(a))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return result
+}
+
+func (qe *QueryEngine) GetSamplesByPrefix(prefix string) []*metrics.MetricPoint {
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	return qe.samplesByPrefix(prefix)
+}
+
+func (qe *QueryEngine) samplesByPrefix(prefix string) []*metrics.MetricPoint {
+	var result []*metrics.MetricPoint
+
+	start := sort.SearchStrings(qe.sampleKeys, prefix)
+	for _, key := range qe.sampleKeys[start:] {
+		if !strings.HasPrefix(key, prefix) {
+			break
+		}
+		result = append(result, qe.samples[key]...)
+	}
+
+	return result
+}
+
+func (qe *QueryEngine) insertSampleKey(key string) {
+	idx := sort.SearchStrings(qe.sampleKeys, key)
+	if idx < len(qe.sampleKeys) && qe.sampleKeys[idx] == key {
+		return
+	}
+
+	qe.sampleKeys = append(qe.sampleKeys, "")
+	copy(qe.sampleKeys[idx+1:], qe.sampleKeys[idx:])
+	qe.sampleKeys[idx] = key
+}
+
+func (qe *QueryEngine) stratumPrefix(filters map[string]string) (string, bool) {
+	clusterID, hasCluster := filters["cluster_id"]
+	if !hasCluster || clusterID == "" {
+		return "", false
+	}
+
+	namespace, hasNamespace := filters["namespace"]
+	switch {
+	case len(filters) == 1 && qe.keyHasPrefix("cluster_id"):
+		return clusterID + "/", true
+	case len(filters) == 2 && hasNamespace && namespace != "" && qe.keyHasPrefix("cluster_id", "namespace"):
+		return clusterID + "/" + namespace + "/", true
+	default:
+		return "", false
+	}
+}
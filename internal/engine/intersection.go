@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+func (qe *QueryEngine) executeHistogramIntersection(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+	baselineStart, err := parseBaselineTime(request, "baseline_start")
+	if err != nil {
+		return nil, err
+	}
+	baselineEnd, err := parseBaselineTime(request, "baseline_end")
+	if err != nil {
+		return nil, err
+	}
+	if !baselineEnd.After(baselineStart) {
+		return nil, &kserrors.ErrInvalidQuery{
+			Query:  request.Query,
+			Reason: "baseline_end must be after baseline_start",
+		}
+	}
+
+	baselineRequest := &metrics.QueryRequest{
+		Query:     request.Query,
+		QueryType: request.QueryType,
+		TimeRange: metrics.TimeRange{Start: baselineStart, End: baselineEnd},
+		Filters:   request.Filters,
+	}
+
+	currentSamples := qe.getFilteredSamples(request)
+	baselineSamples := qe.getFilteredSamples(baselineRequest)
+
+	current := qe.sampleHistogram(currentSamples)
+	baseline := qe.sampleHistogram(baselineSamples)
+
+	scale := current.Scale()
+	if baseline.Scale() < scale {
+		scale = baseline.Scale()
+	}
+	current, err = rescaleHistogram(current, scale)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err = rescaleHistogram(baseline, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	histogramA := histogramBuckets(current)
+	histogramB := histogramBuckets(baseline)
+
+	bc := bhattacharyyaCoefficient(histogramA, histogramB)
+	result := &metrics.HistogramIntersectionResult{
+		BC:         bc,
+		Divergence: -math.Log(math.Max(bc, math.SmallestNonzeroFloat64)),
+		HistogramA: histogramA,
+		HistogramB: histogramB,
+	}
+
+	return &metrics.QueryResult{
+		ID:            request.ID,
+		Query:         request.Query,
+		Result:        result,
+		SampleSize:    len(currentSamples) + len(baselineSamples),
+		IsApproximate: true,
+	}, nil
+}
+
+func parseBaselineTime(request *metrics.QueryRequest, key string) (time.Time, error) {
+	value := request.Filters[key]
+	if value == "" {
+		return time.Time{}, &kserrors.ErrInvalidQuery{
+			Query:  request.Query,
+			Reason: fmt.Sprintf("histogram intersection requires the %s filter", key),
+		}
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, &kserrors.ErrInvalidQuery{
+			Query:  request.Query,
+			Reason: fmt.Sprintf("invalid %s %q: %v", key, value, err),
+		}
+	}
+	return parsed, nil
+}
+
+func (qe *QueryEngine) sampleHistogram(samples []*metrics.MetricPoint) *probabilistic.ExponentialHistogram {
+	histogram := probabilistic.NewExponentialHistogram(qe.histogramScale)
+	for _, sample := range samples {
+		histogram.Record(sample.Value)
+	}
+	return histogram
+}
+
+func rescaleHistogram(histogram *probabilistic.ExponentialHistogram, scale int32) (*probabilistic.ExponentialHistogram, error) {
+	if histogram.Scale() == scale {
+		return histogram, nil
+	}
+
+	rescaled := probabilistic.NewExponentialHistogram(scale)
+	if err := rescaled.Merge(histogram); err != nil {
+		return nil, fmt.Errorf("failed to align histogram scales: %w", err)
+	}
+	return rescaled, nil
+}
+
+func bhattacharyyaCoefficient(a, b []metrics.HistogramBucket) float64 {
+	p, q := alignHistograms(a, b)
+
+	bc := 0.0
+	for i := range p {
+		bc += math.Sqrt(p[i] * q[i])
+	}
+	return math.Min(bc, 1)
+}
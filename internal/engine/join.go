@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"sort"
+
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+func (qe *QueryEngine) executeApproxJoin(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+	left, right := request.Filters["left"], request.Filters["right"]
+	if left == "" || right == "" {
+		return nil, &kserrors.ErrInvalidQuery{
+			Query:  request.Query,
+			Reason: "approximate join requires left and right metric filters",
+		}
+	}
+
+	leftRequest := &metrics.QueryRequest{
+		TimeRange: request.TimeRange,
+		Filters:   map[string]string{"metric_name": left},
+	}
+	rightRequest := &metrics.QueryRequest{
+		TimeRange: request.TimeRange,
+		Filters:   map[string]string{"metric_name": right},
+	}
+	for key, value := range request.Filters {
+		if key == "left" || key == "right" || key == "metric_name" {
+			continue
+		}
+		leftRequest.Filters[key] = value
+		rightRequest.Filters[key] = value
+	}
+
+	leftLatest := latestByPod(qe.getFilteredSamples(leftRequest))
+
+	var rightByPod map[string]*metrics.MetricPoint
+	if !qe.keyHasPrefix("cluster_id", "namespace", "pod_name") {
+		rightByPod = latestByPod(qe.getFilteredSamples(rightRequest))
+	}
+
+	pods := make([]metrics.JoinedPodMetric, 0)
+	sampleSize := 0
+	for podKey, leftSample := range leftLatest {
+		if !qe.bloom.Contains([]byte(podKey + "/" + right)) {
+			continue
+		}
+
+		var rightSample *metrics.MetricPoint
+		if rightByPod != nil {
+			rightSample = rightByPod[podKey]
+		} else {
+			for _, sample := range qe.samplesByPrefix(podKey + "/") {
+				if !qe.matchesFilters(sample, rightRequest) {
+					continue
+				}
+				if rightSample == nil || sample.Timestamp.After(rightSample.Timestamp) {
+					rightSample = sample
+				}
+			}
+		}
+		if rightSample == nil {
+			continue
+		}
+
+		pods = append(pods, metrics.JoinedPodMetric{
+			ClusterID: leftSample.ClusterID,
+			Namespace: leftSample.Namespace,
+			PodName:   leftSample.PodName,
+			MetricA:   leftSample.Value,
+			MetricB:   rightSample.Value,
+		})
+		sampleSize += 2
+	}
+
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].ClusterID != pods[j].ClusterID {
+			return pods[i].ClusterID < pods[j].ClusterID
+		}
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].PodName < pods[j].PodName
+	})
+
+	return &metrics.QueryResult{
+		ID:    request.ID,
+		Query: request.Query,
+		Result: &metrics.JoinResult{
+			Left:  left,
+			Right: right,
+			Pods:  pods,
+		},
+		SampleSize:    sampleSize,
+		IsApproximate: true,
+	}, nil
+}
+
+func latestByPod(samples []*metrics.MetricPoint) map[string]*metrics.MetricPoint {
+	latest := make(map[string]*metrics.MetricPoint)
+	for _, sample := range samples {
+		podKey := sample.ClusterID + "/" + sample.Namespace + "/" + sample.PodName
+		if current, exists := latest[podKey]; !exists || sample.Timestamp.After(current.Timestamp) {
+			latest[podKey] = sample
+		}
+	}
+	return latest
+}
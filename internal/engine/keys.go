@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+var DefaultKeyDimensions = []string{"cluster_id", "namespace", "pod_name", "container_name", "metric_name"}
+
+var knownKeyDimensions = map[string]bool{
+	"cluster_id":     true,
+	"namespace":      true,
+	"node_name":      true,
+	"pod_name":       true,
+	"container_name": true,
+	"metric_name":    true,
+}
+
+func ValidateKeyDimensions(dimensions []string) error {
+	if len(dimensions) == 0 {
+		return fmt.Errorf("key dimensions must not be empty")
+	}
+
+	seen := make(map[string]bool, len(dimensions))
+	for _, dimension := range dimensions {
+		if !knownKeyDimensions[dimension] {
+			return fmt.Errorf("unknown key dimension: %s", dimension)
+		}
+		if seen[dimension] {
+			return fmt.Errorf("duplicate key dimension: %s", dimension)
+		}
+		seen[dimension] = true
+	}
+	return nil
+}
+
+func (qe *QueryEngine) getMetricKey(metric *metrics.MetricPoint) string {
+	parts := make([]string, 0, len(qe.keyDimensions))
+	for _, dimension := range qe.keyDimensions {
+		value := keyDimensionValue(metric, dimension)
+		if dimension == "container_name" && value == "" {
+			continue
+		}
+		parts = append(parts, value)
+	}
+	return strings.Join(parts, "/")
+}
+
+func (qe *QueryEngine) getPodMetricKey(metric *metrics.MetricPoint) string {
+	return fmt.Sprintf("%s/%s/%s/%s",
+		metric.ClusterID, metric.Namespace, metric.PodName, metric.MetricName)
+}
+
+func (qe *QueryEngine) keyHasPrefix(dimensions ...string) bool {
+	if len(dimensions) > len(qe.keyDimensions) {
+		return false
+	}
+	for i, dimension := range dimensions {
+		if qe.keyDimensions[i] != dimension {
+			return false
+		}
+	}
+	return true
+}
+
+func keyDimensionValue(metric *metrics.MetricPoint, dimension string) string {
+	switch dimension {
+	case "cluster_id":
+		return metric.ClusterID
+	case "namespace":
+		return metric.Namespace
+	case "node_name":
+		return metric.NodeName
+	case "pod_name":
+		return metric.PodName
+	case "container_name":
+		return metric.ContainerName
+	case "metric_name":
+		return metric.MetricName
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	latencyEMAAlpha         = 0.01
+	latencyBucketCount      = 1024
+	latencyBucketsPerOctave = 32
+)
+
+type latencyHistogram struct {
+	buckets [latencyBucketCount]uint64
+	count   uint64
+}
+
+func (lh *latencyHistogram) record(d time.Duration) {
+	lh.buckets[latencyBucketIndex(d)]++
+	lh.count++
+}
+
+func (lh *latencyHistogram) quantile(q float64) time.Duration {
+	if lh.count == 0 {
+		return 0
+	}
+
+	target := uint64(math.Ceil(q * float64(lh.count)))
+	if target == 0 {
+		target = 1
+	}
+
+	cumulative := uint64(0)
+	for i, count := range lh.buckets {
+		cumulative += count
+		if cumulative >= target {
+			return latencyBucketUpperBound(i)
+		}
+	}
+
+	return latencyBucketUpperBound(latencyBucketCount - 1)
+}
+
+func latencyBucketIndex(d time.Duration) int {
+	if d <= 1 {
+		return 0
+	}
+
+	idx := int(math.Log2(float64(d)) * latencyBucketsPerOctave)
+	if idx >= latencyBucketCount {
+		return latencyBucketCount - 1
+	}
+	return idx
+}
+
+func latencyBucketUpperBound(idx int) time.Duration {
+	return time.Duration(math.Exp2(float64(idx+1) / latencyBucketsPerOctave))
+}
+
+func (qe *QueryEngine) recordLatency(processingTime time.Duration) {
+	if qe.stats.TotalQueries <= 1 {
+		qe.stats.AvgLatency = processingTime
+	} else {
+		qe.stats.AvgLatency = time.Duration((1-latencyEMAAlpha)*float64(qe.stats.AvgLatency) +
+			latencyEMAAlpha*float64(processingTime))
+	}
+
+	qe.latencies.record(processingTime)
+	qe.stats.P95Latency = qe.latencies.quantile(0.95)
+	qe.stats.P99Latency = qe.latencies.quantile(0.99)
+}
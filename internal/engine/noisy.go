@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	DefaultNoisyPods = 10
+	MaxNoisyPods     = 100
+	noisyPodsTTL     = 60 * time.Second
+)
+
+type cachedNoisyPods struct {
+	pods      []metrics.NoisyPod
+	expiresAt time.Time
+}
+
+func (qe *QueryEngine) ExecuteNoisyPods(metricName string, k int) []metrics.NoisyPod {
+	if k <= 0 {
+		k = DefaultNoisyPods
+	}
+	if k > MaxNoisyPods {
+		k = MaxNoisyPods
+	}
+
+	cacheKey := fmt.Sprintf("%s/%d", metricName, k)
+	if cached, exists := qe.noisyPods.Load(cacheKey); exists {
+		entry := cached.(*cachedNoisyPods)
+		if time.Now().Before(entry.expiresAt) {
+			return append([]metrics.NoisyPod(nil), entry.pods...)
+		}
+	}
+
+	qe.mutex.RLock()
+	pods := qe.executeNoisyPods(metricName, k)
+	qe.mutex.RUnlock()
+
+	qe.noisyPods.Store(cacheKey, &cachedNoisyPods{
+		pods:      append([]metrics.NoisyPod(nil), pods...),
+		expiresAt: time.Now().Add(noisyPodsTTL),
+	})
+
+	return pods
+}
+
+func (qe *QueryEngine) executeNoisyPods(metricName string, k int) []metrics.NoisyPod {
+	type podKey struct {
+		clusterID, namespace, podName string
+	}
+
+	seen := make(map[podKey]bool)
+	for _, sample := range qe.getFilteredSamples(&metrics.QueryRequest{
+		Filters: map[string]string{"metric_name": metricName},
+	}) {
+		seen[podKey{sample.ClusterID, sample.Namespace, sample.PodName}] = true
+	}
+
+	noisy := make([]metrics.NoisyPod, 0, len(seen))
+	for key := range seen {
+		samples := qe.getFilteredSamples(&metrics.QueryRequest{
+			Filters: map[string]string{
+				"cluster_id":  key.clusterID,
+				"namespace":   key.namespace,
+				"pod_name":    key.podName,
+				"metric_name": metricName,
+			},
+		})
+		if len(samples) < 2 {
+			continue
+		}
+
+		noisy = append(noisy, metrics.NoisyPod{
+			ClusterID:   key.clusterID,
+			Namespace:   key.namespace,
+			PodName:     key.podName,
+			Variance:    qe.calculateVariance(samples),
+			SampleCount: len(samples),
+		})
+	}
+
+	sort.Slice(noisy, func(i, j int) bool {
+		if noisy[i].Variance != noisy[j].Variance {
+			return noisy[i].Variance > noisy[j].Variance
+		}
+		return noisy[i].PodName < noisy[j].PodName
+	})
+
+	if len(noisy) > k {
+		noisy = noisy[:k]
+	}
+	return noisy
+}
@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	JoinIdentityMetric = "metric"
+	JoinIdentityPod    = "pod"
+)
+
+var joinFilterKeys = map[string]bool{
+	"cluster_id":     true,
+	"namespace":      true,
+	"metric_name":    true,
+	"pod_name":       true,
+	"node_name":      true,
+	"container_name": true,
+	"category":       true,
+}
+
+func ParseJoinFilter(raw string) (map[string]string, error) {
+	filters := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found || key == "" || value == "" {
+			return nil, &kserrors.ErrInvalidQuery{Reason: fmt.Sprintf("filter %q must be key:value", pair)}
+		}
+		if !joinFilterKeys[key] {
+			return nil, &kserrors.ErrInvalidQuery{Reason: fmt.Sprintf("unsupported filter key: %s", key)}
+		}
+		filters[key] = value
+	}
+	return filters, nil
+}
+
+func (qe *QueryEngine) JoinCardinality(left, right map[string]string, identity string) (*metrics.JoinCardinalityResult, error) {
+	if identity == "" {
+		identity = JoinIdentityMetric
+	}
+	if identity != JoinIdentityMetric && identity != JoinIdentityPod {
+		return nil, &kserrors.ErrInvalidQuery{Reason: fmt.Sprintf("unsupported join identity: %s", identity)}
+	}
+	if len(left) == 0 || len(right) == 0 {
+		return nil, &kserrors.ErrInvalidQuery{Reason: "both left and right filters are required"}
+	}
+
+	qe.mutex.RLock()
+	leftHLL := qe.identityHLL(left, identity)
+	rightHLL := qe.identityHLL(right, identity)
+	qe.mutex.RUnlock()
+
+	union := leftHLL.Clone()
+	if err := union.Merge(rightHLL); err != nil {
+		return nil, err
+	}
+
+	leftCount := leftHLL.Count()
+	rightCount := rightHLL.Count()
+	unionCount := union.Count()
+
+	intersection := uint64(0)
+	if leftCount+rightCount > unionCount {
+		intersection = leftCount + rightCount - unionCount
+	}
+	intersection = min(intersection, leftCount, rightCount)
+
+	jaccard := 0.0
+	if unionCount > 0 {
+		jaccard = float64(intersection) / float64(unionCount)
+	}
+
+	return &metrics.JoinCardinalityResult{
+		LeftFilter:              left,
+		RightFilter:             right,
+		Identity:                identity,
+		LeftCardinality:         leftCount,
+		RightCardinality:        rightCount,
+		UnionCardinality:        unionCount,
+		IntersectionCardinality: intersection,
+		JaccardIndex:            jaccard,
+	}, nil
+}
+
+func (qe *QueryEngine) identityHLL(filters map[string]string, identity string) *probabilistic.HyperLogLog {
+	hll := probabilistic.NewHyperLogLog(qe.labelPrecision)
+	for _, sample := range qe.getFilteredSamples(&metrics.QueryRequest{Filters: filters}) {
+		if identity == JoinIdentityPod {
+			hll.Add([]byte(sample.PodName))
+			continue
+		}
+		hll.Add([]byte(qe.getMetricKey(sample)))
+	}
+	return hll
+}
@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+var parserSeedQueries = []string{
+	"",
+	"PERCENTILE(95)",
+	"PERCENTILE(99.9)",
+	"PERCENTILE(-1)",
+	"PERCENTILE(NaN)",
+	"PERCENTILE()",
+	"PERCENTILE)(",
+	"TOP_K(10)",
+	"TOP_K(0)",
+	"TOP_K(-5)",
+	"TOP_K(99999999999999999999)",
+	"CONTAINS('prod/default/api-1/container-1/cpu_usage')",
+	"FREQUENCY('cpu_usage')",
+	"'",
+	"''",
+	"'a'b'",
+}
+
+func FuzzExtractPercentileValue(f *testing.F) {
+	for _, query := range parserSeedQueries {
+		f.Add(query)
+	}
+	qe := newTestEngine(f, 1.0)
+
+	f.Fuzz(func(t *testing.T, query string) {
+		value, err := qe.extractPercentileValue(query)
+		if err != nil {
+			if !errors.As(err, new(*kserrors.ErrInvalidQuery)) {
+				t.Fatalf("extractPercentileValue(%q) error = %v, want ErrInvalidQuery", query, err)
+			}
+			return
+		}
+		if math.IsNaN(value) || value < 0 || value > 100 {
+			t.Errorf("extractPercentileValue(%q) = %v outside [0, 100]", query, value)
+		}
+		if !strings.Contains(query, "PERCENTILE") && value != defaultPercentile {
+			t.Errorf("extractPercentileValue(%q) = %v without PERCENTILE, want default %v", query, value, defaultPercentile)
+		}
+	})
+}
+
+func FuzzExtractKValue(f *testing.F) {
+	for _, query := range parserSeedQueries {
+		f.Add(query)
+	}
+	qe := newTestEngine(f, 1.0)
+
+	f.Fuzz(func(t *testing.T, query string) {
+		k, err := qe.extractKValue(query)
+		if err != nil {
+			if !errors.As(err, new(*kserrors.ErrInvalidQuery)) {
+				t.Fatalf("extractKValue(%q) error = %v, want ErrInvalidQuery", query, err)
+			}
+			return
+		}
+		if k <= 0 {
+			t.Errorf("extractKValue(%q) = %d, want a positive K", query, k)
+		}
+		if !strings.Contains(query, "TOP_K") && k != defaultTopK {
+			t.Errorf("extractKValue(%q) = %d without TOP_K, want default %d", query, k, defaultTopK)
+		}
+	})
+}
+
+func FuzzExtractMembershipItem(f *testing.F) {
+	for _, query := range parserSeedQueries {
+		f.Add(query)
+	}
+	qe := newTestEngine(f, 1.0)
+
+	f.Fuzz(func(t *testing.T, query string) {
+		for name, extract := range map[string]func(string) string{
+			"extractMembershipItem": qe.extractMembershipItem,
+			"extractFrequencyItem":  qe.extractFrequencyItem,
+		} {
+			item := extract(query)
+			if item == "" {
+				continue
+			}
+			if !strings.Contains(query, "'"+item+"'") {
+				t.Errorf("%s(%q) = %q, not a quoted substring of the query", name, query, item)
+			}
+		}
+	})
+}
+
+func FuzzExecutePercentileQuery(f *testing.F) {
+	for _, query := range parserSeedQueries {
+		f.Add(query)
+	}
+	qe := seededTestEngine(f, HighCPUFixture())
+
+	f.Fuzz(func(t *testing.T, query string) {
+		result, err := qe.ExecuteQuery(&metrics.QueryRequest{QueryType: metrics.Percentile, Query: query})
+		if err != nil {
+			if !errors.As(err, new(*kserrors.ErrInvalidQuery)) && !errors.As(err, new(*kserrors.ErrQueryTooComplex)) {
+				t.Fatalf("ExecuteQuery(%q) error = %v, want ErrInvalidQuery", query, err)
+			}
+			return
+		}
+
+		percentile := result.Result.(*metrics.PercentileResult)
+		if math.IsNaN(percentile.Percentile) || percentile.Percentile < 0 || percentile.Percentile > 100 {
+			t.Errorf("ExecuteQuery(%q) returned percentile %v outside [0, 100]", query, percentile.Percentile)
+		}
+	})
+}
+
+func FuzzExecuteTopKQuery(f *testing.F) {
+	for _, query := range parserSeedQueries {
+		f.Add(query)
+	}
+	qe := seededTestEngine(f, HighCPUFixture())
+
+	f.Fuzz(func(t *testing.T, query string) {
+		result, err := qe.ExecuteQuery(&metrics.QueryRequest{QueryType: metrics.TopK, Query: query})
+		if err != nil {
+			if !errors.As(err, new(*kserrors.ErrInvalidQuery)) && !errors.As(err, new(*kserrors.ErrQueryTooComplex)) {
+				t.Fatalf("ExecuteQuery(%q) error = %v, want ErrInvalidQuery", query, err)
+			}
+			return
+		}
+
+		if k, err := qe.extractKValue(query); err != nil || k <= 0 {
+			t.Errorf("ExecuteQuery(%q) accepted invalid K %d: %v", query, k, err)
+		}
+		if result.Result == nil {
+			t.Errorf("ExecuteQuery(%q) returned a nil result", query)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+)
+
+const engineSnapshotVersion uint8 = 1
+
+type engineSnapshotState struct {
+	Version   uint8
+	CreatedAt time.Time
+	HLL       []byte
+	CMS       []byte
+	Bloom     []byte
+}
+
+func (qe *QueryEngine) SaveSnapshot(path string) error {
+	qe.mutex.RLock()
+	state := engineSnapshotState{Version: engineSnapshotVersion, CreatedAt: time.Now()}
+	hllData, hllErr := qe.hll.MarshalBinary()
+	cmsData, cmsErr := qe.cms.Serialize()
+	bloomData, bloomErr := qe.bloom.Serialize()
+	qe.mutex.RUnlock()
+
+	if err := errors.Join(hllErr, cmsErr, bloomErr); err != nil {
+		return fmt.Errorf("failed to serialize engine snapshot: %w", err)
+	}
+	state.HLL = hllData
+	state.CMS = cmsData
+	state.Bloom = bloomData
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return fmt.Errorf("failed to encode engine snapshot: %v", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write engine snapshot: %v", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace engine snapshot file: %v", err)
+	}
+	return nil
+}
+
+func (qe *QueryEngine) restoreSnapshot(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var state engineSnapshotState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state); err != nil {
+		return fmt.Errorf("failed to decode engine snapshot: %v", err)
+	}
+	if state.Version != engineSnapshotVersion {
+		return &kserrors.ErrSnapshotVersion{Expected: engineSnapshotVersion, Actual: state.Version}
+	}
+
+	hll := &probabilistic.HyperLogLog{}
+	if err := hll.UnmarshalBinary(state.HLL); err != nil {
+		return fmt.Errorf("failed to restore hyperloglog: %w", err)
+	}
+	if !qe.autoPrecision && hll.Precision() != qe.hll.Precision() {
+		return &kserrors.ErrPrecisionMismatch{Expected: qe.hll.Precision(), Actual: hll.Precision()}
+	}
+
+	cms, err := probabilistic.DeserializeCountMinSketch(state.CMS)
+	if err != nil {
+		return fmt.Errorf("failed to restore count-min sketch: %w", err)
+	}
+	if stats := cms.GetStats(); stats.Width != qe.cmsWidth || stats.Depth != qe.cmsDepth {
+		return fmt.Errorf("count-min sketch dimensions %dx%d do not match configured %dx%d",
+			stats.Width, stats.Depth, qe.cmsWidth, qe.cmsDepth)
+	}
+
+	bloom, err := probabilistic.DeserializeBloomFilter(state.Bloom)
+	if err != nil {
+		return fmt.Errorf("failed to restore bloom filter: %w", err)
+	}
+	if stats, current := bloom.GetStats(), qe.bloom.GetStats(); stats.Size != current.Size || stats.NumHashes != current.NumHashes {
+		return fmt.Errorf("bloom filter dimensions %d/%d do not match configured %d/%d",
+			stats.Size, stats.NumHashes, current.Size, current.NumHashes)
+	}
+
+	qe.mutex.Lock()
+	qe.hll = hll
+	qe.cms = cms
+	qe.bloom = bloom
+	qe.restoredSnapshot = true
+	qe.mutex.Unlock()
+
+	slog.Info("Restored engine snapshot",
+		"path", path,
+		"created_at", state.CreatedAt,
+		"hll_estimate", hll.Count(),
+		"cms_total", cms.GetStats().TotalCount,
+		"bloom_items", bloom.GetStats().NumItems)
+	return nil
+}
+
+func (qe *QueryEngine) StartSnapshotter(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := qe.SaveSnapshot(path); err != nil {
+				slog.Warn("Failed to save engine snapshot", "path", path, "error", err)
+			}
+		}
+	}
+}
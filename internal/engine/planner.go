@@ -0,0 +1,189 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	BackendExactCount  = "exact_count"
+	BackendHLL         = "hyperloglog"
+	BackendLabelHLL    = "label_hyperloglog"
+	BackendSpaceSaving = "space_saving"
+	BackendCMS         = "count_min_sketch"
+	BackendBloom       = "bloom_filter"
+	BackendSamples     = "samples"
+	BackendHistorical  = "historical_replay"
+
+	exactCountMaxSamples   = 1000
+	hllLargeSampleCount    = 1000000
+	spaceSavingMaxK        = 20
+	spaceSavingSparseKeys  = 10000
+	spaceSavingCapacityMul = 10
+)
+
+type QueryPlan struct {
+	Backend        string  `json:"backend"`
+	Reason         string  `json:"reason"`
+	EstimatedError float64 `json:"estimated_error"`
+}
+
+// readsSamples reports whether the plan is answered from the sample store.
+// Sketch-only plans skip the quality score so they never pay for a sample
+// scan.
+func (p *QueryPlan) readsSamples() bool {
+	switch p.Backend {
+	case BackendSamples, BackendSpaceSaving, BackendHistorical:
+		return true
+	}
+	return false
+}
+
+type QueryPlanner struct {
+	engine *QueryEngine
+}
+
+func NewQueryPlanner(engine *QueryEngine) *QueryPlanner {
+	return &QueryPlanner{engine: engine}
+}
+
+func (qp *QueryPlanner) Plan(request *metrics.QueryRequest) *QueryPlan {
+	qe := qp.engine
+	totalSamples := qe.index.Len()
+
+	switch request.QueryType {
+	case metrics.CountDistinct:
+		switch {
+		case request.GroupByLabel != "":
+			return &QueryPlan{
+				Backend:        BackendLabelHLL,
+				Reason:         "grouped count distinct uses per-label HyperLogLogs",
+				EstimatedError: 1.04 / math.Sqrt(math.Pow(2, float64(qe.labelPrecision))),
+			}
+		case qe.restoredSnapshot:
+			return &QueryPlan{
+				Backend:        BackendHLL,
+				Reason:         "HyperLogLog was restored from a snapshot and covers history the sample store does not",
+				EstimatedError: qe.hll.EstimateError(),
+			}
+		case totalSamples < exactCountMaxSamples:
+			return &QueryPlan{
+				Backend: BackendExactCount,
+				Reason:  fmt.Sprintf("%d samples is below %d, counting sample keys exactly", totalSamples, exactCountMaxSamples),
+			}
+		case totalSamples > hllLargeSampleCount:
+			return &QueryPlan{
+				Backend:        BackendHLL,
+				Reason:         fmt.Sprintf("%d samples exceeds %d, exact counting is too expensive", totalSamples, hllLargeSampleCount),
+				EstimatedError: qe.hll.EstimateError(),
+			}
+		default:
+			return &QueryPlan{
+				Backend:        BackendHLL,
+				Reason:         fmt.Sprintf("%d samples, HyperLogLog estimate is within its error bound", totalSamples),
+				EstimatedError: qe.hll.EstimateError(),
+			}
+		}
+	case metrics.TopK:
+		k, err := qe.extractKValue(request.Query)
+		distinctKeys := len(qe.samples)
+		if err == nil && k < spaceSavingMaxK && distinctKeys <= spaceSavingSparseKeys {
+			return &QueryPlan{
+				Backend:        BackendSpaceSaving,
+				Reason:         fmt.Sprintf("k=%d with %d distinct keys is sparse enough for Space-Saving", k, distinctKeys),
+				EstimatedError: 1 / float64(k*spaceSavingCapacityMul),
+			}
+		}
+		return &QueryPlan{
+			Backend:        BackendCMS,
+			Reason:         fmt.Sprintf("k=%d with %d distinct keys uses the count-min sketch", k, distinctKeys),
+			EstimatedError: math.E / float64(qe.cmsWidth),
+		}
+	case metrics.FrequencyCount:
+		return &QueryPlan{
+			Backend:        BackendCMS,
+			Reason:         "frequency counts are answered by the count-min sketch",
+			EstimatedError: math.E / float64(qe.cmsWidth),
+		}
+	case metrics.Membership:
+		return &QueryPlan{
+			Backend:        BackendBloom,
+			Reason:         "membership tests are answered by the bloom filter",
+			EstimatedError: qe.bloom.FalsePositiveRate(),
+		}
+	default:
+		return &QueryPlan{
+			Backend: BackendSamples,
+			Reason:  fmt.Sprintf("%s queries are computed from the sample store", request.QueryType),
+		}
+	}
+}
+
+func (qe *QueryEngine) executeExactCountDistinct(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+	exactError := 0.0
+
+	return &metrics.QueryResult{
+		ID:    request.ID,
+		Query: request.Query,
+		Result: &metrics.ApproximateCountResult{
+			Count:          uint64(len(qe.samples)),
+			EstimatedError: exactError,
+		},
+		Error:         &exactError,
+		SampleSize:    qe.index.Len(),
+		IsApproximate: false,
+	}, nil
+}
+
+func (qe *QueryEngine) executeSpaceSavingTopK(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+	k, err := qe.extractKValue(request.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]uint64)
+	for _, sample := range qe.getFilteredSamples(request) {
+		counts[qe.getMetricKey(sample)]++
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	summary := probabilistic.NewSpaceSaving(k * spaceSavingCapacityMul)
+	for _, key := range keys {
+		summary.Offer(key, counts[key])
+	}
+
+	total := summary.Total()
+	topItems := summary.TopK(k)
+	items := make([]metrics.TopKItem, len(topItems))
+	for i, item := range topItems {
+		frequency := 0.0
+		if total > 0 {
+			frequency = float64(item.Count) / float64(total)
+		}
+		items[i] = metrics.TopKItem{
+			Key:       item.Item,
+			Count:     item.Count,
+			Frequency: frequency,
+		}
+	}
+
+	return &metrics.QueryResult{
+		ID:    request.ID,
+		Query: request.Query,
+		Result: &metrics.TopKResult{
+			Items: items,
+			K:     k,
+		},
+		SampleSize:    int(total),
+		IsApproximate: true,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"log/slog"
+
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+)
+
+const (
+	AutoPrecisionStart = 8
+	MaxAutoPrecision   = 16
+
+	autoPrecisionCheckInterval = 64
+)
+
+func linearCountingCrossover(precision uint8) uint64 {
+	return (uint64(5) << precision) / 2
+}
+
+func (qe *QueryEngine) maybePromoteHLL(key string) {
+	if !qe.autoPrecision {
+		return
+	}
+
+	precision := qe.hll.Precision()
+	if precision >= MaxAutoPrecision {
+		return
+	}
+
+	qe.hllUpdates++
+	if qe.hllUpdates%autoPrecisionCheckInterval != 0 {
+		return
+	}
+
+	count := qe.hll.Count()
+	if count <= linearCountingCrossover(precision) {
+		return
+	}
+
+	promoted := probabilistic.NewHyperLogLog(precision + 1)
+	for _, sampleKey := range qe.sampleKeys {
+		promoted.Add([]byte(sampleKey))
+	}
+	promoted.Add([]byte(key))
+	qe.hll = promoted
+
+	slog.Info("Promoted HLL precision",
+		"from", precision,
+		"to", precision+1,
+		"estimate_before", count,
+		"estimate_after", promoted.Count(),
+		"replayed_keys", len(qe.sampleKeys)+1)
+}
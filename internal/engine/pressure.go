@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"sort"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	PressureWindow = 15 * time.Minute
+
+	PressureSeverityLow      = "low"
+	PressureSeverityMedium   = "medium"
+	PressureSeverityHigh     = "high"
+	PressureSeverityCritical = "critical"
+)
+
+var DefaultPressureWeights = map[string]float64{
+	"cpu":    0.4,
+	"memory": 0.4,
+	"disk":   0.2,
+}
+
+var pressureMetrics = map[string]string{
+	"cpu_usage":    "cpu",
+	"memory_usage": "memory",
+	"disk_usage":   "disk",
+}
+
+func copyPressureWeights(weights map[string]float64) map[string]float64 {
+	copied := make(map[string]float64, len(weights))
+	for resource, weight := range weights {
+		if weight > 0 {
+			copied[resource] = weight
+		}
+	}
+	return copied
+}
+
+func (qe *QueryEngine) PodPressureScore(podName string) *metrics.PressureScore {
+	qe.mutex.RLock()
+	samples := qe.getFilteredSamples(&metrics.QueryRequest{
+		TimeRange: metrics.TimeRange{Start: time.Now().Add(-PressureWindow)},
+		Filters:   map[string]string{"pod_name": podName},
+	})
+	qe.mutex.RUnlock()
+
+	score := qe.pressureScore(samples)
+	score.PodName = podName
+	return score
+}
+
+func (qe *QueryEngine) NamespacePressureRanking(namespace string) []metrics.PressureScore {
+	qe.mutex.RLock()
+	samples := qe.getFilteredSamples(&metrics.QueryRequest{
+		TimeRange: metrics.TimeRange{Start: time.Now().Add(-PressureWindow)},
+		Filters:   map[string]string{"namespace": namespace},
+	})
+	qe.mutex.RUnlock()
+
+	type podKey struct {
+		clusterID, podName string
+	}
+
+	byPod := make(map[podKey][]*metrics.MetricPoint)
+	for _, sample := range samples {
+		key := podKey{clusterID: sample.ClusterID, podName: sample.PodName}
+		byPod[key] = append(byPod[key], sample)
+	}
+
+	ranking := make([]metrics.PressureScore, 0, len(byPod))
+	for key, podSamples := range byPod {
+		score := qe.pressureScore(podSamples)
+		score.ClusterID = key.clusterID
+		score.Namespace = namespace
+		score.PodName = key.podName
+		ranking = append(ranking, *score)
+	}
+
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Score != ranking[j].Score {
+			return ranking[i].Score > ranking[j].Score
+		}
+		return ranking[i].PodName < ranking[j].PodName
+	})
+
+	return ranking
+}
+
+func (qe *QueryEngine) pressureScore(samples []*metrics.MetricPoint) *metrics.PressureScore {
+	values := make(map[string][]float64)
+	for _, sample := range samples {
+		if resource, tracked := pressureMetrics[sample.MetricName]; tracked {
+			values[resource] = append(values[resource], sample.Value)
+		}
+	}
+
+	breakdown := make(map[string]float64)
+	var weighted, totalWeight float64
+	for resource, weight := range qe.pressureWeights {
+		resourceValues, exists := values[resource]
+		if !exists {
+			continue
+		}
+
+		usage := clampUnit(percentileOf(resourceValues, 95)) * 100
+		breakdown[resource] = usage
+		weighted += weight * usage
+		totalWeight += weight
+	}
+
+	score := 0.0
+	if totalWeight > 0 {
+		score = weighted / totalWeight
+	}
+
+	return &metrics.PressureScore{
+		Score:     score,
+		Breakdown: breakdown,
+		Severity:  pressureSeverity(score),
+	}
+}
+
+func clampUnit(value float64) float64 {
+	switch {
+	case value < 0:
+		return 0
+	case value > 1:
+		return 1
+	default:
+		return value
+	}
+}
+
+func pressureSeverity(score float64) string {
+	switch {
+	case score >= 90:
+		return PressureSeverityCritical
+	case score >= 75:
+		return PressureSeverityHigh
+	case score >= 50:
+		return PressureSeverityMedium
+	default:
+		return PressureSeverityLow
+	}
+}
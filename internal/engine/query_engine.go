@@ -1,8 +1,11 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -11,6 +14,7 @@ import (
 
 	"github.com/asmit27rai/kubesight/internal/probabilistic"
 	"github.com/asmit27rai/kubesight/internal/sampling"
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
 	"github.com/asmit27rai/kubesight/pkg/metrics"
 )
 
@@ -20,73 +24,294 @@ type QueryEngine struct {
 	bloom   *probabilistic.BloomFilter
 	sampler *sampling.AdaptiveSampler
 	samples map[string][]*metrics.MetricPoint
+	index   *MetricIndex
 	mutex   sync.RWMutex
 	stats   QueryEngineStats
+
+	startedAt time.Time
+
+	keyDimensions []string
+
+	latencies latencyHistogram
+
+	sampleKeys []string
+
+	autoPrecision bool
+	hllUpdates    uint64
+
+	restoredSnapshot bool
+
+	labelHLLs      map[string]*probabilistic.HyperLogLog
+	labelPrecision uint8
+
+	slidingCMS *probabilistic.SlidingWindowCMS
+
+	metricCMS map[string]*probabilistic.CountMinSketch
+	cmsWidth  uint32
+	cmsDepth  uint32
+
+	categoryCMS  map[string]*probabilistic.CountMinSketch
+	categoryHLLs map[string]*probabilistic.HyperLogLog
+
+	namespacedHLL      map[string]*probabilistic.HyperLogLog
+	namespaceOrder     []string
+	namespacePrecision uint8
+
+	heatmapMaxSize int
+	histogramType  string
+	histogramScale int32
+
+	quantileAlgorithm string
+
+	pressureWeights map[string]float64
+
+	cardinalityAlerts    map[string]CardinalityAlert
+	cardinalityThrottle  time.Duration
+	lastCardinalityAlert map[string]time.Time
+
+	resolutions *sampling.MultiResolutionSampler
+
+	planner    *QueryPlanner
+	complexity *QueryComplexityEstimator
+
+	listeners       map[int]chan uint64
+	changeListeners map[int]chan ChangeEvent
+	nextListenerID  int
+	listenerMutex   sync.Mutex
+
+	inFlight   sync.WaitGroup
+	drainMutex sync.RWMutex
+	draining   bool
+
+	clusterSummaries sync.Map
+	noisyPods        sync.Map
+
+	dashboardCache *metrics.DashboardOverview
+	dashboardMutex sync.Mutex
 }
 
 type QueryEngineStats struct {
 	TotalQueries   uint64        `json:"total_queries"`
 	ApproxQueries  uint64        `json:"approx_queries"`
 	AvgLatency     time.Duration `json:"avg_latency"`
-	TotalSamples   uint64        `json:"total_samples"`
+	P95Latency     time.Duration `json:"p95_latency"`
+	P99Latency     time.Duration `json:"p99_latency"`
+	TotalReceived  uint64        `json:"total_received"`
+	TotalSampled   uint64        `json:"total_sampled"`
 	ErrorRate      float64       `json:"error_rate"`
 	LastUpdateTime time.Time     `json:"last_update"`
 }
 
 func NewQueryEngine(config QueryEngineConfig) *QueryEngine {
-	return &QueryEngine{
-		hll:     probabilistic.NewHyperLogLog(config.HLLPrecision),
+	if config.HeatmapMaxSize <= 0 {
+		config.HeatmapMaxSize = DefaultHeatmapMaxSize
+	}
+	if err := ValidateKeyDimensions(config.KeyDimensions); err != nil {
+		if len(config.KeyDimensions) > 0 {
+			slog.Warn("Falling back to default key dimensions", "error", err)
+		}
+		config.KeyDimensions = DefaultKeyDimensions
+	}
+	if config.QuantileAlgorithm == "" {
+		config.QuantileAlgorithm = QuantileAlgorithmKLL
+	}
+	if len(config.PressureWeights) == 0 {
+		config.PressureWeights = DefaultPressureWeights
+	}
+	if config.CardinalityAlertThrottle <= 0 {
+		config.CardinalityAlertThrottle = DefaultCardinalityAlertThrottle
+	}
+
+	hllPrecision := config.HLLPrecision
+	if config.AutoPrecision {
+		hllPrecision = AutoPrecisionStart
+	}
+
+	qe := &QueryEngine{
+		hll:     probabilistic.NewHyperLogLog(hllPrecision),
 		cms:     probabilistic.NewCountMinSketch(config.CMSWidth, config.CMSDepth),
 		bloom:   probabilistic.NewBloomFilter(config.BloomSize, config.BloomHashes),
 		sampler: sampling.NewAdaptiveSampler(config.SamplingConfig),
 		samples: make(map[string][]*metrics.MetricPoint),
+		index:   NewMetricIndex(),
 		stats:   QueryEngineStats{LastUpdateTime: time.Now()},
+
+		startedAt: time.Now(),
+
+		keyDimensions: append([]string{}, config.KeyDimensions...),
+
+		autoPrecision: config.AutoPrecision,
+
+		labelHLLs:      make(map[string]*probabilistic.HyperLogLog),
+		labelPrecision: labelHLLPrecision(config.HLLPrecision),
+
+		slidingCMS: probabilistic.NewSlidingWindowCMS(config.CMSWidth, config.CMSDepth, SlidingWindowSlots, time.Minute),
+
+		metricCMS: make(map[string]*probabilistic.CountMinSketch),
+		cmsWidth:  config.CMSWidth,
+		cmsDepth:  config.CMSDepth,
+
+		categoryCMS:  make(map[string]*probabilistic.CountMinSketch),
+		categoryHLLs: make(map[string]*probabilistic.HyperLogLog),
+
+		namespacedHLL:      make(map[string]*probabilistic.HyperLogLog),
+		namespacePrecision: namespaceHLLPrecision(config.HLLPrecision),
+
+		heatmapMaxSize: config.HeatmapMaxSize,
+		histogramType:  config.HistogramType,
+		histogramScale: config.HistogramScale,
+
+		quantileAlgorithm: config.QuantileAlgorithm,
+
+		pressureWeights: copyPressureWeights(config.PressureWeights),
+
+		cardinalityAlerts:    make(map[string]CardinalityAlert),
+		cardinalityThrottle:  config.CardinalityAlertThrottle,
+		lastCardinalityAlert: make(map[string]time.Time),
+
+		resolutions: sampling.NewMultiResolutionSampler(config.ResolutionTiers, sampling.DefaultResolutionWindowCapacity),
+
+		listeners:       make(map[int]chan uint64),
+		changeListeners: make(map[int]chan ChangeEvent),
+	}
+	qe.planner = NewQueryPlanner(qe)
+	qe.complexity = NewQueryComplexityEstimator(qe, config.MaxQueryComplexity)
+
+	for _, alert := range config.CardinalityAlerts {
+		if alert.MetricName == "" || alert.WebhookURL == "" {
+			continue
+		}
+		qe.cardinalityAlerts[alert.MetricName] = alert
 	}
+
+	if config.SnapshotPath != "" {
+		if err := qe.restoreSnapshot(config.SnapshotPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("Failed to restore engine snapshot", "path", config.SnapshotPath, "error", err)
+		}
+	}
+
+	return qe
 }
 
 type QueryEngineConfig struct {
 	HLLPrecision   uint8                   `json:"hll_precision"`
+	AutoPrecision  bool                    `json:"auto_precision"`
 	CMSWidth       uint32                  `json:"cms_width"`
 	CMSDepth       uint32                  `json:"cms_depth"`
 	BloomSize      uint32                  `json:"bloom_size"`
 	BloomHashes    uint32                  `json:"bloom_hashes"`
 	SamplingConfig sampling.SamplingConfig `json:"sampling_config"`
+	HeatmapMaxSize int                     `json:"heatmap_max_size"`
+	HistogramType  string                  `json:"histogram_type"`
+	HistogramScale int32                   `json:"histogram_scale"`
+
+	QuantileAlgorithm string `json:"quantile_algorithm"`
+
+	PressureWeights map[string]float64 `json:"pressure_weights"`
+
+	CardinalityAlerts        []CardinalityAlert `json:"cardinality_alerts"`
+	CardinalityAlertThrottle time.Duration      `json:"cardinality_alert_throttle"`
+
+	ResolutionTiers []time.Duration `json:"resolution_tiers"`
+
+	KeyDimensions []string `json:"key_dimensions"`
+
+	MaxQueryComplexity int `json:"max_query_complexity"`
+
+	SnapshotPath string `json:"snapshot_path"`
 }
 
+const (
+	HistogramTypeExact       = "exact"
+	HistogramTypeExponential = "exponential"
+)
+
+const SlidingWindowSlots = 60
+
+const (
+	qualityExpectedSampleSize = 1000
+	qualityMaxSampleAge       = time.Hour
+)
+
 func (qe *QueryEngine) ProcessMetric(metric *metrics.MetricPoint) {
+	qe.ProcessMetricWithSampler(metric, qe.sampler)
+}
+
+func (qe *QueryEngine) ProcessMetricWithSampler(metric *metrics.MetricPoint, sampler *sampling.AdaptiveSampler) {
+	qe.drainMutex.RLock()
+	if qe.draining {
+		qe.drainMutex.RUnlock()
+		return
+	}
+	qe.inFlight.Add(1)
+	qe.drainMutex.RUnlock()
+	defer qe.inFlight.Done()
+
 	qe.mutex.Lock()
 	defer qe.mutex.Unlock()
 
-	if sampled, shouldSample := qe.sampler.Sample(metric); shouldSample && sampled != nil {
+	if sampled, shouldSample := sampler.Sample(metric); shouldSample && sampled != nil {
 		qe.updateDataStructures(sampled)
 
 		key := qe.getMetricKey(sampled)
+		if existing, exists := qe.samples[key]; !exists {
+			qe.insertSampleKey(key)
+		} else {
+			qe.detectIngestGap(key, existing[len(existing)-1], sampled)
+		}
 		qe.samples[key] = append(qe.samples[key], sampled)
+		qe.index.Add(sampled)
+		qe.resolutions.Add(sampled)
 
 		if len(qe.samples[key]) > 1000 {
-			qe.samples[key] = qe.samples[key][len(qe.samples[key])-1000:]
+			evicted := len(qe.samples[key]) - 1000
+			for _, evictedSample := range qe.samples[key][:evicted] {
+				qe.index.Remove(evictedSample)
+			}
+			qe.samples[key] = qe.samples[key][evicted:]
 		}
+
+		qe.stats.TotalSampled++
 	}
 
-	qe.stats.TotalSamples++
+	qe.stats.TotalReceived++
+	qe.notifyProcessed(qe.stats.TotalReceived)
 }
 
 func (qe *QueryEngine) ExecuteQuery(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+	return qe.executeQuery(request, time.Time{})
+}
+
+func (qe *QueryEngine) ExecuteQueryAtTime(request *metrics.QueryRequest, asOf time.Time) (*metrics.QueryResult, error) {
+	if asOf.IsZero() {
+		return nil, &kserrors.ErrInvalidQuery{Query: request.Query, Reason: "as_of time must be set"}
+	}
+	return qe.executeQuery(request, asOf)
+}
+
+func (qe *QueryEngine) executeQuery(request *metrics.QueryRequest, asOf time.Time) (*metrics.QueryResult, error) {
 	startTime := time.Now()
 
 	qe.mutex.Lock()
 	qe.stats.TotalQueries++
 	qe.mutex.Unlock()
 
-	result, err := qe.processQuery(request)
+	result, plan, err := qe.processQuery(request, asOf)
 	if err != nil {
 		return nil, err
 	}
 
+	slog.Debug("Query planned",
+		"query_id", request.ID,
+		"type", request.QueryType,
+		"backend", plan.Backend,
+		"reason", plan.Reason,
+		"estimated_error", plan.EstimatedError)
+
 	processingTime := time.Since(startTime)
 
 	qe.mutex.Lock()
-	qe.stats.AvgLatency = time.Duration((int64(qe.stats.AvgLatency)*int64(qe.stats.TotalQueries-1) + int64(processingTime)) / int64(qe.stats.TotalQueries))
+	qe.recordLatency(processingTime)
 	if result.IsApproximate {
 		qe.stats.ApproxQueries++
 	}
@@ -98,9 +323,47 @@ func (qe *QueryEngine) ExecuteQuery(request *metrics.QueryRequest) (*metrics.Que
 	return result, nil
 }
 
-func (qe *QueryEngine) processQuery(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+func (qe *QueryEngine) processQuery(request *metrics.QueryRequest, asOf time.Time) (*metrics.QueryResult, *QueryPlan, error) {
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	if complexity := qe.complexity.Estimate(request); !complexity.Allowed {
+		return nil, nil, &kserrors.ErrQueryTooComplex{Complexity: complexity.Complexity, Limit: complexity.Limit}
+	}
+
+	var (
+		plan   *QueryPlan
+		result *metrics.QueryResult
+		err    error
+	)
+
+	if asOf.IsZero() {
+		plan = qe.planner.Plan(request)
+		result, err = qe.dispatchQuery(request, plan)
+	} else {
+		request = historicalRequest(request, asOf)
+		plan = &QueryPlan{
+			Backend: BackendHistorical,
+			Reason:  fmt.Sprintf("replaying samples up to %s", asOf.Format(time.RFC3339)),
+		}
+		result, err = qe.dispatchHistoricalQuery(request, asOf)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if plan.readsSamples() {
+		result.QualityScore = qe.qualityScore(request, result)
+	}
+	return result, plan, nil
+}
+
+func (qe *QueryEngine) dispatchQuery(request *metrics.QueryRequest, plan *QueryPlan) (*metrics.QueryResult, error) {
 	switch request.QueryType {
 	case metrics.CountDistinct:
+		if plan.Backend == BackendExactCount {
+			return qe.executeExactCountDistinct(request)
+		}
 		return qe.executeCountDistinct(request)
 	case metrics.Sum:
 		return qe.executeSum(request)
@@ -109,19 +372,67 @@ func (qe *QueryEngine) processQuery(request *metrics.QueryRequest) (*metrics.Que
 	case metrics.Percentile:
 		return qe.executePercentile(request)
 	case metrics.TopK:
+		if plan.Backend == BackendSpaceSaving {
+			return qe.executeSpaceSavingTopK(request)
+		}
 		return qe.executeTopK(request)
 	case metrics.Membership:
 		return qe.executeMembership(request)
 	case metrics.FrequencyCount:
 		return qe.executeFrequencyCount(request)
+	case metrics.ApproxJoin:
+		return qe.executeApproxJoin(request)
+	case metrics.HistogramIntersection:
+		return qe.executeHistogramIntersection(request)
 	default:
-		return nil, fmt.Errorf("unsupported query type: %s", request.QueryType)
+		return nil, &kserrors.ErrUnsupportedQueryType{Type: request.QueryType}
 	}
 }
 
+func (qe *QueryEngine) qualityScore(request *metrics.QueryRequest, result *metrics.QueryResult) float64 {
+	errorBound := request.ErrorBound
+	if result.Error != nil {
+		errorBound = *result.Error
+	}
+
+	var oldestSample time.Time
+	for _, sample := range qe.getFilteredSamples(request) {
+		if oldestSample.IsZero() || sample.Timestamp.Before(oldestSample) {
+			oldestSample = sample.Timestamp
+		}
+	}
+
+	samplingRate := 0.0
+	if qe.stats.TotalReceived > 0 {
+		samplingRate = float64(qe.stats.TotalSampled) / float64(qe.stats.TotalReceived)
+	}
+
+	return computeQualityScore(result.SampleSize, samplingRate, errorBound, oldestSample)
+}
+
+func computeQualityScore(sampleSize int, samplingRate, errorBound float64, oldestSample time.Time) float64 {
+	sizeScore := math.Min(float64(sampleSize)/qualityExpectedSampleSize, 1)
+	rateScore := math.Sqrt(math.Min(math.Max(samplingRate, 0), 1))
+
+	freshnessScore := 0.0
+	if !oldestSample.IsZero() {
+		age := time.Since(oldestSample)
+		freshnessScore = 1 - math.Min(math.Max(age.Seconds()/qualityMaxSampleAge.Seconds(), 0), 1)
+	}
+
+	errorScore := 1.0
+	if !math.IsNaN(errorBound) {
+		errorScore = 1 - math.Min(math.Abs(errorBound), 1)
+	}
+
+	score := 0.4*sizeScore + 0.3*rateScore + 0.2*freshnessScore + 0.1*errorScore
+	return math.Round(score*1000) / 1000
+}
+
 func (qe *QueryEngine) executeCountDistinct(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
-	qe.mutex.RLock()
-	defer qe.mutex.RUnlock()
+	if request.GroupByLabel != "" {
+		return qe.executeGroupedCountDistinct(request)
+	}
 
 	count := qe.hll.Count()
 	error := qe.hll.EstimateError()
@@ -141,6 +452,29 @@ func (qe *QueryEngine) executeCountDistinct(request *metrics.QueryRequest) (*met
 	}, nil
 }
 
+func (qe *QueryEngine) executeGroupedCountDistinct(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+	prefix := request.GroupByLabel + ":"
+	groups := make(map[string]uint64)
+
+	var estimatedError float64
+	for key, hll := range qe.labelHLLs {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		groups[strings.TrimPrefix(key, prefix)] = hll.Count()
+		estimatedError = math.Max(estimatedError, hll.EstimateError())
+	}
+
+	return &metrics.QueryResult{
+		ID:            request.ID,
+		Query:         request.Query,
+		Result:        groups,
+		Error:         &estimatedError,
+		SampleSize:    len(qe.getAllSamples()),
+		IsApproximate: true,
+	}, nil
+}
+
 func (qe *QueryEngine) executeSum(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
 	samples := qe.getFilteredSamples(request)
 
@@ -216,6 +550,11 @@ func (qe *QueryEngine) executeAverage(request *metrics.QueryRequest) (*metrics.Q
 }
 
 func (qe *QueryEngine) executePercentile(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+	percentileValue, err := qe.extractPercentileValue(request.Query)
+	if err != nil {
+		return nil, err
+	}
+
 	samples := qe.getFilteredSamples(request)
 
 	if len(samples) == 0 {
@@ -228,9 +567,13 @@ func (qe *QueryEngine) executePercentile(request *metrics.QueryRequest) (*metric
 		}, nil
 	}
 
-	percentileValue := qe.extractPercentileValue(request.Query)
-	if percentileValue < 0 || percentileValue > 100 {
-		return nil, fmt.Errorf("invalid percentile value: %f", percentileValue)
+	if qe.histogramType == HistogramTypeExponential {
+		return qe.executeExponentialPercentile(request, samples, percentileValue)
+	}
+
+	bootstrapSamples, confidence, err := bootstrapParams(request)
+	if err != nil {
+		return nil, err
 	}
 
 	values := make([]float64, len(samples))
@@ -239,22 +582,40 @@ func (qe *QueryEngine) executePercentile(request *metrics.QueryRequest) (*metric
 	}
 	sort.Float64s(values)
 
-	index := (percentileValue / 100.0) * float64(len(values)-1)
-	lowerIndex := int(math.Floor(index))
-	upperIndex := int(math.Ceil(index))
+	lowerCI, upperCI := Bootstrap(values, percentileValue, bootstrapSamples, confidence)
+	ciWidth := upperCI - lowerCI
 
-	var percentileResult float64
-	if lowerIndex == upperIndex {
-		percentileResult = values[lowerIndex]
-	} else {
-		weight := index - float64(lowerIndex)
-		percentileResult = values[lowerIndex]*(1-weight) + values[upperIndex]*weight
+	result := &metrics.PercentileResult{
+		Percentile: percentileValue,
+		Value:      interpolatedPercentile(values, percentileValue),
+		SampleSize: len(samples),
+		LowerCI:    lowerCI,
+		UpperCI:    upperCI,
 	}
 
-	result := &metrics.PercentileResult{
+	return &metrics.QueryResult{
+		ID:            request.ID,
+		Query:         request.Query,
+		Result:        result,
+		Error:         &ciWidth,
+		Confidence:    &confidence,
+		SampleSize:    len(samples),
+		IsApproximate: true,
+	}, nil
+}
+
+func (qe *QueryEngine) executeExponentialPercentile(request *metrics.QueryRequest, samples []*metrics.MetricPoint, percentileValue float64) (*metrics.QueryResult, error) {
+	histogram := probabilistic.NewExponentialHistogram(qe.histogramScale)
+	for _, sample := range samples {
+		histogram.Record(sample.Value)
+	}
+
+	result := &metrics.HistogramResult{
 		Percentile: percentileValue,
-		Value:      percentileResult,
+		Value:      histogram.Quantile(percentileValue / 100.0),
 		SampleSize: len(samples),
+		Scale:      histogram.Scale(),
+		Buckets:    histogramBuckets(histogram),
 	}
 
 	return &metrics.QueryResult{
@@ -266,21 +627,45 @@ func (qe *QueryEngine) executePercentile(request *metrics.QueryRequest) (*metric
 	}, nil
 }
 
-func (qe *QueryEngine) executeTopK(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
-	qe.mutex.RLock()
-	defer qe.mutex.RUnlock()
+func histogramBuckets(histogram *probabilistic.ExponentialHistogram) []metrics.HistogramBucket {
+	buckets := histogram.Buckets()
+	resultBuckets := make([]metrics.HistogramBucket, len(buckets))
+	for i, bucket := range buckets {
+		resultBuckets[i] = metrics.HistogramBucket{
+			Lower: bucket.Lower,
+			Upper: bucket.Upper,
+			Count: bucket.Count,
+		}
+	}
+	return resultBuckets
+}
 
-	k := qe.extractKValue(request.Query)
-	if k <= 0 {
-		return nil, fmt.Errorf("invalid K value: %d", k)
+func (qe *QueryEngine) executeTopK(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+	k, err := qe.extractKValue(request.Query)
+	if err != nil {
+		return nil, err
 	}
 
-	heavyHitters := qe.cms.TopK(k)
+	cms, exists := qe.getSketchForRequest(request)
+	if !exists {
+		return &metrics.QueryResult{
+			ID:            request.ID,
+			Query:         request.Query,
+			Result:        &metrics.TopKResult{Items: []metrics.TopKItem{}, K: k},
+			SampleSize:    0,
+			IsApproximate: true,
+		}, nil
+	}
+	heavyHitters := cms.TopK(k)
 
 	items := make([]metrics.TopKItem, len(heavyHitters))
 	for i, hh := range heavyHitters {
+		key := hh.Key
+		if key == "" {
+			key = fmt.Sprintf("bucket_%d", hh.Bucket)
+		}
 		items[i] = metrics.TopKItem{
-			Key:       fmt.Sprintf("bucket_%d", hh.Bucket),
+			Key:       key,
 			Count:     uint64(hh.Count),
 			Frequency: hh.Frequency,
 		}
@@ -295,18 +680,15 @@ func (qe *QueryEngine) executeTopK(request *metrics.QueryRequest) (*metrics.Quer
 		ID:            request.ID,
 		Query:         request.Query,
 		Result:        result,
-		SampleSize:    int(qe.cms.GetStats().TotalCount),
+		SampleSize:    int(cms.GetStats().TotalCount),
 		IsApproximate: true,
 	}, nil
 }
 
 func (qe *QueryEngine) executeMembership(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
-	qe.mutex.RLock()
-	defer qe.mutex.RUnlock()
-
 	item := qe.extractMembershipItem(request.Query)
 	if item == "" {
-		return nil, fmt.Errorf("no item specified for membership test")
+		return nil, &kserrors.ErrInvalidQuery{Query: request.Query, Reason: "no item specified for membership test"}
 	}
 
 	isMember := qe.bloom.Contains([]byte(item))
@@ -327,21 +709,53 @@ func (qe *QueryEngine) executeMembership(request *metrics.QueryRequest) (*metric
 }
 
 func (qe *QueryEngine) executeFrequencyCount(request *metrics.QueryRequest) (*metrics.QueryResult, error) {
-	qe.mutex.RLock()
-	defer qe.mutex.RUnlock()
-
 	item := qe.extractFrequencyItem(request.Query)
 	if item == "" {
-		return nil, fmt.Errorf("no item specified for frequency count")
+		return nil, &kserrors.ErrInvalidQuery{Query: request.Query, Reason: "no item specified for frequency count"}
+	}
+
+	if minutesStr, ok := request.Filters["sliding_window_minutes"]; ok {
+		return qe.executeSlidingFrequencyCount(request, item, minutesStr)
+	}
+
+	cms, exists := qe.getSketchForRequest(request)
+	if !exists {
+		return &metrics.QueryResult{
+			ID:            request.ID,
+			Query:         request.Query,
+			Result:        uint32(0),
+			SampleSize:    0,
+			IsApproximate: true,
+		}, nil
+	}
+	count := cms.Estimate([]byte(item))
+
+	return &metrics.QueryResult{
+		ID:            request.ID,
+		Query:         request.Query,
+		Result:        count,
+		SampleSize:    int(cms.GetStats().TotalCount),
+		IsApproximate: true,
+	}, nil
+}
+
+func (qe *QueryEngine) executeSlidingFrequencyCount(request *metrics.QueryRequest, item, minutesStr string) (*metrics.QueryResult, error) {
+	minutes, err := strconv.Atoi(minutesStr)
+	if err != nil || minutes <= 0 || minutes > SlidingWindowSlots {
+		return nil, &kserrors.ErrInvalidQuery{
+			Query:  request.Query,
+			Reason: fmt.Sprintf("sliding_window_minutes must be between 1 and %d", SlidingWindowSlots),
+		}
 	}
 
-	count := qe.cms.Estimate([]byte(item))
+	since := time.Now().Add(-time.Duration(minutes) * time.Minute)
+	count := qe.slidingCMS.EstimateSince([]byte(item), since)
 
 	return &metrics.QueryResult{
 		ID:            request.ID,
 		Query:         request.Query,
 		Result:        count,
-		SampleSize:    int(qe.cms.GetStats().TotalCount),
+		SampleSize:    int(count),
 		IsApproximate: true,
 	}, nil
 }
@@ -349,24 +763,146 @@ func (qe *QueryEngine) executeFrequencyCount(request *metrics.QueryRequest) (*me
 func (qe *QueryEngine) updateDataStructures(metric *metrics.MetricPoint) {
 	key := qe.getMetricKey(metric)
 	qe.hll.Add([]byte(key))
+	qe.maybePromoteHLL(key)
 
-	qe.cms.Update([]byte(key), 1)
+	qe.cms.UpdateWithKey(key, 1)
+	qe.slidingCMS.Update([]byte(key), 1, metric.Timestamp)
+	metricCMS := qe.getOrCreateMetricCMS(metric.MetricName)
+	metricCMS.UpdateWithKey(key, 1)
+	qe.checkCardinality(metric.MetricName, metricCMS)
 
 	qe.bloom.Add([]byte(key))
+	if metric.ContainerName != "" {
+		qe.bloom.Add([]byte(qe.getPodMetricKey(metric)))
+	}
+
+	qe.updateLabelHLLs(metric)
+	qe.updateCategorySketches(metric, key)
+	qe.updateNamespaceHLL(metric)
 }
 
-func (qe *QueryEngine) getMetricKey(metric *metrics.MetricPoint) string {
-	return fmt.Sprintf("%s/%s/%s/%s",
-		metric.ClusterID, metric.Namespace, metric.PodName, metric.MetricName)
+func (qe *QueryEngine) updateNamespaceHLL(metric *metrics.MetricPoint) {
+	if metric.Namespace == "" {
+		return
+	}
+
+	hll, exists := qe.namespacedHLL[metric.Namespace]
+	if !exists {
+		if len(qe.namespaceOrder) >= MaxNamespaceHLLs {
+			oldest := qe.namespaceOrder[0]
+			qe.namespaceOrder = qe.namespaceOrder[1:]
+			delete(qe.namespacedHLL, oldest)
+		}
+		hll = probabilistic.NewHyperLogLog(qe.namespacePrecision)
+		qe.namespacedHLL[metric.Namespace] = hll
+		qe.namespaceOrder = append(qe.namespaceOrder, metric.Namespace)
+	}
+	hll.Add([]byte(metric.PodName))
 }
 
-func (qe *QueryEngine) getFilteredSamples(request *metrics.QueryRequest) []*metrics.MetricPoint {
+func (qe *QueryEngine) updateCategorySketches(metric *metrics.MetricPoint, key string) {
+	if metric.Category == "" {
+		return
+	}
+
+	cms, exists := qe.categoryCMS[metric.Category]
+	if !exists {
+		cms = probabilistic.NewCountMinSketch(qe.cmsWidth, qe.cmsDepth)
+		qe.categoryCMS[metric.Category] = cms
+	}
+	cms.UpdateWithKey(key, 1)
+
+	hll, exists := qe.categoryHLLs[metric.Category]
+	if !exists {
+		hll = probabilistic.NewHyperLogLog(qe.labelPrecision)
+		qe.categoryHLLs[metric.Category] = hll
+	}
+	hll.Add([]byte(key))
+}
+
+func (qe *QueryEngine) getOrCreateMetricCMS(metricName string) *probabilistic.CountMinSketch {
+	if cms, exists := qe.metricCMS[metricName]; exists {
+		return cms
+	}
+
+	cms := probabilistic.NewCountMinSketch(qe.cmsWidth, qe.cmsDepth)
+	qe.metricCMS[metricName] = cms
+	return cms
+}
+
+func (qe *QueryEngine) getSketchForRequest(request *metrics.QueryRequest) (*probabilistic.CountMinSketch, bool) {
+	if metricName := request.Filters["metric_name"]; metricName != "" {
+		cms, exists := qe.metricCMS[metricName]
+		return cms, exists
+	}
+	return qe.cms, true
+}
+
+func (qe *QueryEngine) GetMetricCMSStats(metricName string) (probabilistic.CMSStats, bool) {
 	qe.mutex.RLock()
 	defer qe.mutex.RUnlock()
 
+	cms, exists := qe.metricCMS[metricName]
+	if !exists {
+		return probabilistic.CMSStats{}, false
+	}
+	return cms.GetStats(), true
+}
+
+func (qe *QueryEngine) updateLabelHLLs(metric *metrics.MetricPoint) {
+	if metric.PodName == "" {
+		return
+	}
+
+	dimensions := map[string]string{
+		"cluster_id":     metric.ClusterID,
+		"namespace":      metric.Namespace,
+		"container_name": metric.ContainerName,
+		"metric_name":    metric.MetricName,
+	}
+	for key, value := range metric.Labels {
+		if _, builtin := dimensions[key]; !builtin {
+			dimensions[key] = value
+		}
+	}
+
+	for key, value := range dimensions {
+		if value == "" {
+			continue
+		}
+
+		labelKey := key + ":" + value
+		hll, exists := qe.labelHLLs[labelKey]
+		if !exists {
+			hll = probabilistic.NewHyperLogLog(qe.labelPrecision)
+			qe.labelHLLs[labelKey] = hll
+		}
+		hll.Add([]byte(metric.PodName))
+	}
+}
+
+func namespaceHLLPrecision(precision uint8) uint8 {
+	if precision < 5 {
+		return 4
+	}
+	return precision - 1
+}
+
+func labelHLLPrecision(precision uint8) uint8 {
+	if precision < 6 {
+		return 4
+	}
+	return precision - 2
+}
+
+func (qe *QueryEngine) getFilteredSamples(request *metrics.QueryRequest) []*metrics.MetricPoint {
 	var allSamples []*metrics.MetricPoint
-	for _, samples := range qe.samples {
-		allSamples = append(allSamples, samples...)
+	if prefix, ok := qe.stratumPrefix(request.Filters); ok {
+		allSamples = qe.samplesByPrefix(prefix)
+	} else if indexed, ok := qe.index.Lookup(request.Filters); ok {
+		allSamples = indexed
+	} else {
+		allSamples = qe.getAllSamples()
 	}
 
 	var filtered []*metrics.MetricPoint
@@ -405,6 +941,18 @@ func (qe *QueryEngine) matchesFilters(metric *metrics.MetricPoint, request *metr
 			if metric.PodName != value {
 				return false
 			}
+		case "node_name":
+			if metric.NodeName != value {
+				return false
+			}
+		case "container_name":
+			if metric.ContainerName != value {
+				return false
+			}
+		case "category":
+			if metric.Category != value {
+				return false
+			}
 		}
 	}
 
@@ -439,30 +987,69 @@ func (qe *QueryEngine) calculateVariance(samples []*metrics.MetricPoint) float64
 	return sumSquares / float64(len(samples)-1)
 }
 
-func (qe *QueryEngine) extractPercentileValue(query string) float64 {
-	if strings.Contains(query, "PERCENTILE") {
-		start := strings.Index(query, "(") + 1
-		end := strings.Index(query, ")")
-		if start > 0 && end > start {
-			if val, err := strconv.ParseFloat(query[start:end], 64); err == nil {
-				return val
-			}
-		}
+const (
+	defaultPercentile = 95.0
+	defaultTopK       = 10
+)
+
+// queryArgument returns the first argument of keyword(...) in the query, so
+// both "PERCENTILE(99) cpu_usage" and "PERCENTILE(99, cpu_usage)" yield "99".
+// found is false when the keyword does not appear at all.
+func queryArgument(query, keyword string) (arg string, found bool, err error) {
+	start := strings.Index(query, keyword)
+	if start < 0 {
+		return "", false, nil
+	}
+
+	rest := strings.TrimLeft(query[start+len(keyword):], " ")
+	if !strings.HasPrefix(rest, "(") {
+		return "", true, &kserrors.ErrInvalidQuery{Query: query, Reason: fmt.Sprintf("%s requires an argument", keyword)}
 	}
-	return 95.0
+	end := strings.Index(rest, ")")
+	if end < 0 {
+		return "", true, &kserrors.ErrInvalidQuery{Query: query, Reason: fmt.Sprintf("unterminated %s argument", keyword)}
+	}
+
+	arg, _, _ = strings.Cut(rest[1:end], ",")
+	return strings.TrimSpace(arg), true, nil
 }
 
-func (qe *QueryEngine) extractKValue(query string) int {
-	if strings.Contains(query, "TOP_K") {
-		start := strings.Index(query, "(") + 1
-		end := strings.Index(query, ")")
-		if start > 0 && end > start {
-			if val, err := strconv.Atoi(query[start:end]); err == nil {
-				return val
-			}
-		}
+func (qe *QueryEngine) extractPercentileValue(query string) (float64, error) {
+	arg, found, err := queryArgument(query, "PERCENTILE")
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return defaultPercentile, nil
+	}
+
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, &kserrors.ErrInvalidQuery{Query: query, Reason: fmt.Sprintf("invalid percentile value: %q", arg)}
+	}
+	if math.IsNaN(value) || value < 0 || value > 100 {
+		return 0, &kserrors.ErrInvalidQuery{Query: query, Reason: fmt.Sprintf("percentile %v is outside [0, 100]", value)}
+	}
+	return value, nil
+}
+
+func (qe *QueryEngine) extractKValue(query string) (int, error) {
+	arg, found, err := queryArgument(query, "TOP_K")
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return defaultTopK, nil
+	}
+
+	k, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, &kserrors.ErrInvalidQuery{Query: query, Reason: fmt.Sprintf("invalid K value: %q", arg)}
+	}
+	if k <= 0 {
+		return 0, &kserrors.ErrInvalidQuery{Query: query, Reason: fmt.Sprintf("invalid K value: %d", k)}
 	}
-	return 10
+	return k, nil
 }
 
 func (qe *QueryEngine) extractMembershipItem(query string) string {
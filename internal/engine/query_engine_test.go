@@ -0,0 +1,532 @@
+package engine
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/sampling"
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const highCPUKey = "prod/default/api-1/container-1/cpu_usage"
+
+func newTestEngine(t testing.TB, baseRate float64) *QueryEngine {
+	t.Helper()
+
+	return NewQueryEngine(QueryEngineConfig{
+		HLLPrecision: 12,
+		CMSWidth:     2048,
+		CMSDepth:     4,
+		BloomSize:    1 << 16,
+		BloomHashes:  4,
+		SamplingConfig: sampling.SamplingConfig{
+			BaseRate:         baseRate,
+			AnomalyRate:      baseRate,
+			WindowSize:       time.Hour,
+			ReservoirSize:    10000,
+			MetricPriorities: map[string]int{},
+		},
+	})
+}
+
+func seededTestEngine(t testing.TB, fixtures ...[]*metrics.MetricPoint) *QueryEngine {
+	t.Helper()
+
+	qe := newTestEngine(t, 1.0)
+	for _, fixture := range fixtures {
+		SeedEngine(qe, fixture)
+	}
+	return qe
+}
+
+func TestExecuteQueryDispatch(t *testing.T) {
+	qe := seededTestEngine(t, HighCPUFixture(), NormalTrafficFixture())
+
+	tests := []struct {
+		name          string
+		request       *metrics.QueryRequest
+		isApproximate bool
+		check         func(t *testing.T, result *metrics.QueryResult)
+	}{
+		{
+			name:    "count distinct",
+			request: &metrics.QueryRequest{QueryType: metrics.CountDistinct, Query: "COUNT_DISTINCT(pod_name)"},
+			check: func(t *testing.T, result *metrics.QueryResult) {
+				count := result.Result.(*metrics.ApproximateCountResult)
+				if count.Count != 7 {
+					t.Errorf("count = %d, want 7 distinct series", count.Count)
+				}
+			},
+		},
+		{
+			name: "count distinct grouped by label",
+			request: &metrics.QueryRequest{
+				QueryType:    metrics.CountDistinct,
+				Query:        "COUNT_DISTINCT(pod_name)",
+				GroupByLabel: "metric_name",
+			},
+			isApproximate: true,
+			check: func(t *testing.T, result *metrics.QueryResult) {
+				groups := result.Result.(map[string]uint64)
+				if groups["cpu_usage"] != 3 || groups["network_in"] != 2 {
+					t.Errorf("groups = %v, want cpu_usage=3 and network_in=2", groups)
+				}
+			},
+		},
+		{
+			name: "sum",
+			request: &metrics.QueryRequest{
+				QueryType: metrics.Sum,
+				Query:     "SUM(cpu_usage)",
+				Filters:   map[string]string{"metric_name": "cpu_usage"},
+			},
+			isApproximate: true,
+			check: func(t *testing.T, result *metrics.QueryResult) {
+				if sum := result.Result.(float64); math.Abs(sum-27.6) > 1e-9 {
+					t.Errorf("sum = %v, want 27.6", sum)
+				}
+			},
+		},
+		{
+			name: "average",
+			request: &metrics.QueryRequest{
+				QueryType: metrics.Average,
+				Query:     "AVG(cpu_usage)",
+				Filters:   map[string]string{"metric_name": "cpu_usage"},
+			},
+			isApproximate: true,
+			check: func(t *testing.T, result *metrics.QueryResult) {
+				if avg := result.Result.(float64); math.Abs(avg-0.92) > 1e-9 {
+					t.Errorf("average = %v, want 0.92", avg)
+				}
+			},
+		},
+		{
+			name: "percentile",
+			request: &metrics.QueryRequest{
+				QueryType: metrics.Percentile,
+				Query:     "PERCENTILE(50)",
+				Filters:   map[string]string{"metric_name": "cpu_usage"},
+			},
+			isApproximate: true,
+			check: func(t *testing.T, result *metrics.QueryResult) {
+				percentile := result.Result.(*metrics.PercentileResult)
+				if math.Abs(percentile.Value-0.92) > 1e-9 {
+					t.Errorf("p50 = %v, want 0.92", percentile.Value)
+				}
+				if percentile.LowerCI > percentile.Value || percentile.UpperCI < percentile.Value {
+					t.Errorf("confidence interval [%v, %v] does not contain %v", percentile.LowerCI, percentile.UpperCI, percentile.Value)
+				}
+			},
+		},
+		{
+			name: "top k",
+			request: &metrics.QueryRequest{
+				QueryType: metrics.TopK,
+				Query:     "TOP_K(2)",
+				Filters:   map[string]string{"metric_name": "cpu_usage"},
+			},
+			isApproximate: true,
+			check: func(t *testing.T, result *metrics.QueryResult) {
+				topK := result.Result.(*metrics.TopKResult)
+				if len(topK.Items) != 2 {
+					t.Fatalf("got %d items, want 2", len(topK.Items))
+				}
+				for _, item := range topK.Items {
+					if item.Count != 10 {
+						t.Errorf("item %s count = %d, want 10", item.Key, item.Count)
+					}
+				}
+			},
+		},
+		{
+			name:          "membership",
+			request:       &metrics.QueryRequest{QueryType: metrics.Membership, Query: "MEMBERSHIP('" + highCPUKey + "')"},
+			isApproximate: true,
+			check: func(t *testing.T, result *metrics.QueryResult) {
+				if !result.Result.(*metrics.MembershipResult).Member {
+					t.Errorf("%s should be a member", highCPUKey)
+				}
+			},
+		},
+		{
+			name:          "frequency count",
+			request:       &metrics.QueryRequest{QueryType: metrics.FrequencyCount, Query: "FREQUENCY('" + highCPUKey + "')"},
+			isApproximate: true,
+			check: func(t *testing.T, result *metrics.QueryResult) {
+				if count := result.Result.(uint32); count < 10 {
+					t.Errorf("frequency = %d, want at least 10", count)
+				}
+			},
+		},
+		{
+			name: "approximate join",
+			request: &metrics.QueryRequest{
+				QueryType: metrics.ApproxJoin,
+				Query:     "JOIN(network_in, network_out)",
+				Filters:   map[string]string{"left": "network_in", "right": "network_out"},
+			},
+			isApproximate: true,
+			check: func(t *testing.T, result *metrics.QueryResult) {
+				join := result.Result.(*metrics.JoinResult)
+				if len(join.Pods) != 2 {
+					t.Errorf("joined %d pods, want 2", len(join.Pods))
+				}
+			},
+		},
+		{
+			name: "histogram intersection",
+			request: &metrics.QueryRequest{
+				QueryType: metrics.HistogramIntersection,
+				Query:     "INTERSECT(cpu_usage)",
+				Filters: map[string]string{
+					"metric_name":    "cpu_usage",
+					"baseline_start": FixtureEpoch.Format(time.RFC3339),
+					"baseline_end":   FixtureEpoch.Add(15 * time.Minute).Format(time.RFC3339),
+				},
+			},
+			isApproximate: true,
+			check: func(t *testing.T, result *metrics.QueryResult) {
+				intersection := result.Result.(*metrics.HistogramIntersectionResult)
+				if intersection.BC <= 0 || intersection.BC > 1 {
+					t.Errorf("Bhattacharyya coefficient = %v, want (0, 1]", intersection.BC)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := qe.ExecuteQuery(tt.request)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			if result.Result == nil {
+				t.Fatal("Result is nil")
+			}
+			if result.SampleSize <= 0 {
+				t.Errorf("SampleSize = %d, want > 0", result.SampleSize)
+			}
+			if result.IsApproximate != tt.isApproximate {
+				t.Errorf("IsApproximate = %v, want %v", result.IsApproximate, tt.isApproximate)
+			}
+			tt.check(t, result)
+		})
+	}
+}
+
+func TestExecuteQueryErrors(t *testing.T) {
+	qe := seededTestEngine(t, HighCPUFixture())
+
+	tests := []struct {
+		name    string
+		request *metrics.QueryRequest
+		target  interface{}
+	}{
+		{
+			name:    "unsupported type",
+			request: &metrics.QueryRequest{QueryType: metrics.QueryType("median_of_means")},
+			target:  new(*kserrors.ErrUnsupportedQueryType),
+		},
+		{
+			name:    "empty type",
+			request: &metrics.QueryRequest{},
+			target:  new(*kserrors.ErrUnsupportedQueryType),
+		},
+		{
+			name:    "percentile out of range",
+			request: &metrics.QueryRequest{QueryType: metrics.Percentile, Query: "PERCENTILE(150)"},
+			target:  new(*kserrors.ErrInvalidQuery),
+		},
+		{
+			name:    "non-positive k",
+			request: &metrics.QueryRequest{QueryType: metrics.TopK, Query: "TOP_K(0)"},
+			target:  new(*kserrors.ErrInvalidQuery),
+		},
+		{
+			name:    "malformed percentile",
+			request: &metrics.QueryRequest{QueryType: metrics.Percentile, Query: "PERCENTILE(p99)"},
+			target:  new(*kserrors.ErrInvalidQuery),
+		},
+		{
+			name:    "malformed k",
+			request: &metrics.QueryRequest{QueryType: metrics.TopK, Query: "TOP_K(ten)"},
+			target:  new(*kserrors.ErrInvalidQuery),
+		},
+		{
+			name:    "negative k on an empty filter",
+			request: &metrics.QueryRequest{QueryType: metrics.TopK, Query: "TOP_K(-3)", Filters: map[string]string{"metric_name": "missing"}},
+			target:  new(*kserrors.ErrInvalidQuery),
+		},
+		{
+			name:    "malformed percentile with no matching samples",
+			request: &metrics.QueryRequest{QueryType: metrics.Percentile, Query: "PERCENTILE(abc)", Filters: map[string]string{"metric_name": "missing"}},
+			target:  new(*kserrors.ErrInvalidQuery),
+		},
+		{
+			name:    "membership without item",
+			request: &metrics.QueryRequest{QueryType: metrics.Membership, Query: "MEMBERSHIP()"},
+			target:  new(*kserrors.ErrInvalidQuery),
+		},
+		{
+			name:    "frequency without item",
+			request: &metrics.QueryRequest{QueryType: metrics.FrequencyCount, Query: "FREQUENCY()"},
+			target:  new(*kserrors.ErrInvalidQuery),
+		},
+		{
+			name:    "join without right metric",
+			request: &metrics.QueryRequest{QueryType: metrics.ApproxJoin, Filters: map[string]string{"left": "cpu_usage"}},
+			target:  new(*kserrors.ErrInvalidQuery),
+		},
+		{
+			name:    "intersection without baseline",
+			request: &metrics.QueryRequest{QueryType: metrics.HistogramIntersection},
+			target:  new(*kserrors.ErrInvalidQuery),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := qe.ExecuteQuery(tt.request)
+			if err == nil {
+				t.Fatalf("ExecuteQuery() = %+v, want error", result)
+			}
+			if !errors.As(err, tt.target) {
+				t.Fatalf("ExecuteQuery() error = %T (%v), want %T", err, err, tt.target)
+			}
+		})
+	}
+}
+
+func TestExecuteQueryEmptyEngine(t *testing.T) {
+	qe := newTestEngine(t, 1.0)
+
+	for _, queryType := range []metrics.QueryType{metrics.Sum, metrics.Average, metrics.Percentile} {
+		t.Run(string(queryType), func(t *testing.T) {
+			result, err := qe.ExecuteQuery(&metrics.QueryRequest{QueryType: queryType})
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			if result.SampleSize != 0 || result.IsApproximate {
+				t.Fatalf("SampleSize = %d, IsApproximate = %v, want 0 and false", result.SampleSize, result.IsApproximate)
+			}
+		})
+	}
+
+	result, err := qe.ExecuteQuery(&metrics.QueryRequest{QueryType: metrics.CountDistinct})
+	if err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", err)
+	}
+	if count := result.Result.(*metrics.ApproximateCountResult).Count; count != 0 {
+		t.Fatalf("count distinct on empty engine = %d, want 0", count)
+	}
+
+	result, err = qe.ExecuteQuery(&metrics.QueryRequest{QueryType: metrics.Membership, Query: "MEMBERSHIP('" + highCPUKey + "')"})
+	if err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", err)
+	}
+	if result.Result.(*metrics.MembershipResult).Member {
+		t.Fatal("empty bloom filter reported a member")
+	}
+}
+
+func TestExecuteQuerySingleSample(t *testing.T) {
+	qe := seededTestEngine(t, NewFixtureBuilder().WithValues([]float64{0.42}).Build())
+
+	for _, queryType := range []metrics.QueryType{metrics.Average, metrics.Sum} {
+		result, err := qe.ExecuteQuery(&metrics.QueryRequest{QueryType: queryType})
+		if err != nil {
+			t.Fatalf("%s: ExecuteQuery() error = %v", queryType, err)
+		}
+		if value := result.Result.(float64); math.Abs(value-0.42) > 1e-9 {
+			t.Fatalf("%s = %v, want 0.42", queryType, value)
+		}
+		if *result.Error != 0 {
+			t.Fatalf("%s error = %v, want 0 for a single sample", queryType, *result.Error)
+		}
+	}
+
+	result, err := qe.ExecuteQuery(&metrics.QueryRequest{QueryType: metrics.Percentile, Query: "PERCENTILE(99)"})
+	if err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", err)
+	}
+	if value := result.Result.(*metrics.PercentileResult).Value; value != 0.42 {
+		t.Fatalf("p99 = %v, want 0.42", value)
+	}
+}
+
+func TestExecuteQueryDuplicateKeys(t *testing.T) {
+	qe := seededTestEngine(t, NewFixtureBuilder().WithValues([]float64{0.5, 0.5, 0.5, 0.5}).Build())
+
+	result, err := qe.ExecuteQuery(&metrics.QueryRequest{QueryType: metrics.CountDistinct})
+	if err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", err)
+	}
+	if count := result.Result.(*metrics.ApproximateCountResult).Count; count != 1 {
+		t.Fatalf("count distinct = %d, want 1 for repeated samples of one series", count)
+	}
+	if result.SampleSize != 4 {
+		t.Fatalf("SampleSize = %d, want 4", result.SampleSize)
+	}
+
+	key := "test-cluster/default/pod-1/container-1/cpu_usage"
+	result, err = qe.ExecuteQuery(&metrics.QueryRequest{QueryType: metrics.FrequencyCount, Query: "FREQUENCY('" + key + "')"})
+	if err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", err)
+	}
+	if count := result.Result.(uint32); count != 4 {
+		t.Fatalf("frequency = %d, want 4", count)
+	}
+}
+
+func TestExecuteQueryUnknownMetricSketch(t *testing.T) {
+	qe := seededTestEngine(t, HighCPUFixture())
+
+	result, err := qe.ExecuteQuery(&metrics.QueryRequest{
+		QueryType: metrics.FrequencyCount,
+		Query:     "FREQUENCY('" + highCPUKey + "')",
+		Filters:   map[string]string{"metric_name": "does_not_exist"},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", err)
+	}
+	if count := result.Result.(uint32); count != 0 || result.SampleSize != 0 {
+		t.Fatalf("frequency = %d with SampleSize %d, want 0 and 0", count, result.SampleSize)
+	}
+	if _, exists := qe.GetMetricCMSStats("does_not_exist"); exists {
+		t.Fatal("querying an unknown metric created a sketch for it")
+	}
+}
+
+func TestStatsTotalSampledTracksBaseRate(t *testing.T) {
+	const baseRate = 0.3
+
+	qe := newTestEngine(t, baseRate)
+	SeedEngine(qe, NewFixtureBuilder().
+		WithPods("pod-1", "pod-2", "pod-3", "pod-4", "pod-5").
+		WithValues(rampValues(5000, 0.2, 0.4)).
+		WithTimestamps(time.Now().Add(-time.Minute), time.Millisecond).
+		Build())
+
+	stats := qe.GetStats()
+	if stats.TotalReceived != 5000 {
+		t.Fatalf("TotalReceived = %d, want 5000", stats.TotalReceived)
+	}
+
+	rate := float64(stats.TotalSampled) / float64(stats.TotalReceived)
+	if math.Abs(rate-baseRate) > 0.1*baseRate {
+		t.Fatalf("TotalSampled/TotalReceived = %.3f (%d/%d), want %.2f ± 10%%",
+			rate, stats.TotalSampled, stats.TotalReceived, baseRate)
+	}
+}
+
+func TestExtractPercentileValue(t *testing.T) {
+	qe := newTestEngine(t, 1.0)
+
+	tests := []struct {
+		query   string
+		want    float64
+		wantErr bool
+	}{
+		{query: "", want: 95},
+		{query: "cpu_usage", want: 95},
+		{query: "PERCENTILE(99) cpu_usage", want: 99},
+		{query: "PERCENTILE(99.9, cpu_usage)", want: 99.9},
+		{query: "PERCENTILE ( 50 )", want: 50},
+		{query: "PERCENTILE(0)", want: 0},
+		{query: "PERCENTILE(100)", want: 100},
+		{query: "PERCENTILE(abc)", wantErr: true},
+		{query: "PERCENTILE()", wantErr: true},
+		{query: "PERCENTILE(NaN)", wantErr: true},
+		{query: "PERCENTILE(Inf)", wantErr: true},
+		{query: "PERCENTILE(-1)", wantErr: true},
+		{query: "PERCENTILE(100.5)", wantErr: true},
+		{query: "PERCENTILE(95", wantErr: true},
+		{query: "PERCENTILE cpu_usage", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := qe.extractPercentileValue(tt.query)
+			if tt.wantErr {
+				if !errors.As(err, new(*kserrors.ErrInvalidQuery)) {
+					t.Fatalf("extractPercentileValue(%q) = %v, %v; want ErrInvalidQuery", tt.query, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("extractPercentileValue(%q) = %v, %v; want %v", tt.query, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractKValue(t *testing.T) {
+	qe := newTestEngine(t, 1.0)
+
+	tests := []struct {
+		query   string
+		want    int
+		wantErr bool
+	}{
+		{query: "", want: 10},
+		{query: "memory_usage", want: 10},
+		{query: "TOP_K(5) memory_usage", want: 5},
+		{query: "TOP_K(10, memory_usage)", want: 10},
+		{query: "TOP_K(1)", want: 1},
+		{query: "TOP_K(0)", wantErr: true},
+		{query: "TOP_K(-5)", wantErr: true},
+		{query: "TOP_K(2.5)", wantErr: true},
+		{query: "TOP_K(ten)", wantErr: true},
+		{query: "TOP_K(99999999999999999999)", wantErr: true},
+		{query: "TOP_K()", wantErr: true},
+		{query: "TOP_K(5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := qe.extractKValue(tt.query)
+			if tt.wantErr {
+				if !errors.As(err, new(*kserrors.ErrInvalidQuery)) {
+					t.Fatalf("extractKValue(%q) = %v, %v; want ErrInvalidQuery", tt.query, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("extractKValue(%q) = %v, %v; want %v", tt.query, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualityScoreOnlyForSampleBackedPlans(t *testing.T) {
+	qe := seededTestEngine(t, HighCPUFixture())
+
+	tests := []struct {
+		name      string
+		request   *metrics.QueryRequest
+		wantScore bool
+	}{
+		{name: "percentile", request: &metrics.QueryRequest{QueryType: metrics.Percentile, Query: "PERCENTILE(95)"}, wantScore: true},
+		{name: "average", request: &metrics.QueryRequest{QueryType: metrics.Average}, wantScore: true},
+		{name: "space-saving top-k", request: &metrics.QueryRequest{QueryType: metrics.TopK, Query: "TOP_K(5)"}, wantScore: true},
+		{name: "cms top-k", request: &metrics.QueryRequest{QueryType: metrics.TopK, Query: "TOP_K(50)"}},
+		{name: "membership", request: &metrics.QueryRequest{QueryType: metrics.Membership, Query: "CONTAINS('" + highCPUKey + "')"}},
+		{name: "frequency", request: &metrics.QueryRequest{QueryType: metrics.FrequencyCount, Query: "FREQUENCY('" + highCPUKey + "')"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := qe.ExecuteQuery(tt.request)
+			if err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			if got := result.QualityScore > 0; got != tt.wantScore {
+				t.Errorf("QualityScore = %v, want scored = %v", result.QualityScore, tt.wantScore)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"math"
+	"strings"
+
+	"github.com/asmit27rai/kubesight/internal/sampling"
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	RepresentativenessBuckets      = 10
+	RepresentativenessSignificance = 0.05
+)
+
+func (qe *QueryEngine) SampleRepresentativeness(stratum string) (*metrics.RepresentativenessResult, error) {
+	qe.mutex.RLock()
+	samples, exists := qe.samples[stratum]
+	if !exists {
+		samples = qe.samplesByPrefix(strings.TrimSuffix(stratum, "/") + "/")
+	}
+
+	values := make([]float64, len(samples))
+	isCPU := true
+	for i, sample := range samples {
+		values[i] = sample.Value
+		if sample.MetricName != "cpu_usage" {
+			isCPU = false
+		}
+	}
+	qe.mutex.RUnlock()
+
+	if len(values) == 0 {
+		return nil, &kserrors.ErrMetricNotFound{MetricName: stratum}
+	}
+
+	lower, upper := 0.0, 1.0
+	if !isCPU {
+		lower, upper = math.Inf(1), math.Inf(-1)
+		for _, value := range values {
+			lower = math.Min(lower, value)
+			upper = math.Max(upper, value)
+		}
+	}
+
+	chiSquared := sampling.ChiSquaredUniform(values, lower, upper, RepresentativenessBuckets)
+	if chiSquared.DegreesOfFreedom == 0 {
+		return nil, &kserrors.ErrInvalidQuery{
+			Reason: "not enough samples in stratum " + stratum + " for a chi-squared test",
+		}
+	}
+
+	return &metrics.RepresentativenessResult{
+		Stratum:          stratum,
+		ChiSquared:       chiSquared.Statistic,
+		DegreesOfFreedom: chiSquared.DegreesOfFreedom,
+		PValue:           chiSquared.PValue,
+		SampleCount:      len(values),
+		IsRepresentative: chiSquared.PValue > RepresentativenessSignificance,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package engine
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/sampling"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+func (qe *QueryEngine) SamplesAtResolution(timestamp time.Time, resolution time.Duration) []*metrics.MetricPoint {
+	return qe.resolutions.QueryAtResolution(timestamp, resolution)
+}
+
+func (qe *QueryEngine) ResolutionTiers() []sampling.ResolutionTierInfo {
+	return qe.resolutions.Tiers()
+}
+
+func (qe *QueryEngine) RecentSamples(window time.Duration) []*metrics.MetricPoint {
+	now := time.Now()
+	return qe.resolutions.QueryRange(now.Add(-window), now, qe.resolutions.Finest())
+}
+
+func (qe *QueryEngine) trendSamples(metricName string) []*metrics.MetricPoint {
+	coarsest := qe.resolutions.Coarsest()
+	now := time.Now()
+
+	var result []*metrics.MetricPoint
+	for _, sample := range qe.resolutions.QueryRange(now.Add(-qe.resolutions.Retention(coarsest)), now, coarsest) {
+		if sample.MetricName == metricName {
+			result = append(result, sample)
+		}
+	}
+	return result
+}
+
+func sampleIdentity(sample *metrics.MetricPoint) string {
+	return sample.GetKey() + "@" + strconv.FormatInt(sample.Timestamp.UnixNano(), 10)
+}
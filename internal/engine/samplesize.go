@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const SampleSizePercentile = "percentile"
+
+func MassartSampleSize(errorBound, confidence float64) int {
+	delta := 1 - confidence
+	return int(math.Ceil(math.Log(2/delta) / (2 * errorBound * errorBound)))
+}
+
+func (qe *QueryEngine) SampleSize(request *metrics.QueryRequest, estimateType string, percentile, errorBound, confidence float64) (*metrics.SampleSizeResult, error) {
+	if estimateType != SampleSizePercentile {
+		return nil, &kserrors.ErrInvalidQuery{Reason: fmt.Sprintf("unsupported sample size type: %s", estimateType)}
+	}
+	if percentile <= 0 || percentile > 100 {
+		return nil, &kserrors.ErrInvalidQuery{Reason: "p must be in (0, 100]"}
+	}
+	if errorBound <= 0 || errorBound >= 1 {
+		return nil, &kserrors.ErrInvalidQuery{Reason: "error must be in (0, 1)"}
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return nil, &kserrors.ErrInvalidQuery{Reason: "confidence must be in (0, 1)"}
+	}
+
+	qe.mutex.RLock()
+	current := len(qe.getFilteredSamples(request))
+	totalSampled := qe.stats.TotalSampled
+	elapsed := time.Since(qe.startedAt)
+	qe.mutex.RUnlock()
+
+	required := MassartSampleSize(errorBound, confidence)
+	result := &metrics.SampleSizeResult{
+		Type:            estimateType,
+		Percentile:      percentile,
+		ErrorBound:      errorBound,
+		Confidence:      confidence,
+		Filters:         request.Filters,
+		RequiredSamples: required,
+		CurrentSamples:  current,
+		Deficit:         max(required-current, 0),
+	}
+
+	if elapsed > 0 {
+		result.SamplesPerSecond = float64(totalSampled) / elapsed.Seconds()
+	}
+
+	wait := 0.0
+	if result.Deficit > 0 {
+		if result.SamplesPerSecond == 0 {
+			return result, nil
+		}
+		wait = float64(result.Deficit) / result.SamplesPerSecond
+	}
+	result.ExpectedWaitSeconds = &wait
+
+	return result, nil
+}
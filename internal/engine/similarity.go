@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"math"
+	"sort"
+
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	SimilarityBuckets  = 10
+	DefaultSimilarPods = 5
+	MaxSimilarPods     = 100
+)
+
+func (qe *QueryEngine) SimilarPods(metricName, podName string, k int) []metrics.SimilarPod {
+	if k <= 0 {
+		k = DefaultSimilarPods
+	}
+	if k > MaxSimilarPods {
+		k = MaxSimilarPods
+	}
+
+	qe.mutex.RLock()
+	samples := qe.getFilteredSamples(&metrics.QueryRequest{
+		Filters: map[string]string{"metric_name": metricName},
+	})
+	qe.mutex.RUnlock()
+
+	type podKey struct {
+		clusterID, namespace, podName string
+	}
+
+	byPod := make(map[podKey][]float64)
+	var target []float64
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+
+	for _, sample := range samples {
+		if sample.PodName == podName {
+			target = append(target, sample.Value)
+		} else {
+			key := podKey{sample.ClusterID, sample.Namespace, sample.PodName}
+			byPod[key] = append(byPod[key], sample.Value)
+		}
+		minValue = math.Min(minValue, sample.Value)
+		maxValue = math.Max(maxValue, sample.Value)
+	}
+
+	similar := make([]metrics.SimilarPod, 0, len(byPod))
+	if len(target) == 0 {
+		return similar
+	}
+
+	targetHistogram := valueHistogram(target, minValue, maxValue)
+	for key, values := range byPod {
+		similar = append(similar, metrics.SimilarPod{
+			ClusterID:   key.clusterID,
+			Namespace:   key.namespace,
+			PodName:     key.podName,
+			Divergence:  probabilistic.JSDivergence(targetHistogram, valueHistogram(values, minValue, maxValue)),
+			SampleCount: len(values),
+		})
+	}
+
+	sort.Slice(similar, func(i, j int) bool {
+		if similar[i].Divergence != similar[j].Divergence {
+			return similar[i].Divergence < similar[j].Divergence
+		}
+		return similar[i].PodName < similar[j].PodName
+	})
+
+	if len(similar) > k {
+		similar = similar[:k]
+	}
+	return similar
+}
+
+func valueHistogram(values []float64, minValue, maxValue float64) []float64 {
+	histogram := make([]float64, SimilarityBuckets)
+	width := (maxValue - minValue) / SimilarityBuckets
+
+	for _, value := range values {
+		bucket := 0
+		if width > 0 {
+			bucket = int((value - minValue) / width)
+		}
+		if bucket >= SimilarityBuckets {
+			bucket = SimilarityBuckets - 1
+		}
+		histogram[bucket]++
+	}
+	return histogram
+}
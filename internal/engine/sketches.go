@@ -0,0 +1,205 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	QuantileAlgorithmKLL         = "kll"
+	QuantileAlgorithmExponential = "exponential"
+)
+
+const MaxQuantilesPerQuery = 100
+
+type quantileSketch interface {
+	Quantile(q float64) float64
+	Quantiles(qs []float64) []float64
+	CDF(value float64) float64
+	Count() uint64
+}
+
+func (qe *QueryEngine) ExecuteQuantiles(metricName string, quantiles []float64) (*metrics.QuantileQueryResult, error) {
+	if len(quantiles) == 0 || len(quantiles) > MaxQuantilesPerQuery {
+		return nil, &kserrors.ErrInvalidQuery{
+			Reason: fmt.Sprintf("between 1 and %d quantiles are required", MaxQuantilesPerQuery),
+		}
+	}
+	for _, q := range quantiles {
+		if math.IsNaN(q) || q < 0 || q > 1 {
+			return nil, &kserrors.ErrInvalidQuery{Reason: fmt.Sprintf("quantile %v is outside [0, 1]", q)}
+		}
+	}
+
+	sketch, err := qe.metricQuantileSketch(metricName)
+	if err != nil {
+		return nil, err
+	}
+
+	values := sketch.Quantiles(quantiles)
+	result := &metrics.QuantileQueryResult{
+		MetricName: metricName,
+		Quantiles:  make(map[string]float64, len(quantiles)),
+		Count:      sketch.Count(),
+		Algorithm:  qe.quantileAlgorithm,
+	}
+	for i, q := range quantiles {
+		result.Quantiles[strconv.FormatFloat(q, 'f', -1, 64)] = values[i]
+	}
+
+	return result, nil
+}
+
+func (qe *QueryEngine) ExecuteCDF(metricName string, value float64) (*metrics.CDFResult, error) {
+	if math.IsNaN(value) {
+		return nil, &kserrors.ErrInvalidQuery{Reason: "value must be a number"}
+	}
+
+	sketch, err := qe.metricQuantileSketch(metricName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metrics.CDFResult{
+		MetricName: metricName,
+		Value:      value,
+		Fraction:   sketch.CDF(value),
+		Count:      sketch.Count(),
+		Algorithm:  qe.quantileAlgorithm,
+	}, nil
+}
+
+func (qe *QueryEngine) metricQuantileSketch(metricName string) (quantileSketch, error) {
+	if metricName == "" {
+		return nil, &kserrors.ErrInvalidQuery{Reason: "metric name is required"}
+	}
+
+	qe.mutex.RLock()
+	samples := qe.getFilteredSamples(&metrics.QueryRequest{
+		Filters: map[string]string{"metric_name": metricName},
+	})
+	qe.mutex.RUnlock()
+
+	if len(samples) == 0 {
+		return nil, &kserrors.ErrMetricNotFound{MetricName: metricName}
+	}
+
+	switch qe.quantileAlgorithm {
+	case QuantileAlgorithmKLL:
+		sketch := probabilistic.NewKLLSketch(probabilistic.DefaultKLLK)
+		for _, sample := range samples {
+			sketch.Update(sample.Value)
+		}
+		return sketch, nil
+	case QuantileAlgorithmExponential:
+		histogram := probabilistic.NewExponentialHistogram(qe.histogramScale)
+		for _, sample := range samples {
+			histogram.Record(sample.Value)
+		}
+		return histogram, nil
+	default:
+		return nil, fmt.Errorf("unsupported quantile algorithm: %s", qe.quantileAlgorithm)
+	}
+}
+
+func (qe *QueryEngine) MergeQuantileSketches(payloads [][]byte) (*metrics.QuantileMergeResult, error) {
+	if len(payloads) == 0 {
+		return nil, fmt.Errorf("no quantile sketches provided")
+	}
+
+	var merged quantileSketch
+
+	switch qe.quantileAlgorithm {
+	case QuantileAlgorithmKLL:
+		sketch := probabilistic.NewKLLSketch(probabilistic.DefaultKLLK)
+		for i, payload := range payloads {
+			other, err := probabilistic.DeserializeKLLSketch(payload)
+			if err != nil {
+				return nil, fmt.Errorf("sketch %d: %v", i, err)
+			}
+			if err := sketch.Merge(other); err != nil {
+				return nil, fmt.Errorf("sketch %d: %w", i, err)
+			}
+		}
+		merged = sketch
+	case QuantileAlgorithmExponential:
+		histogram := probabilistic.NewExponentialHistogram(probabilistic.MaxExponentialScale)
+		for i, payload := range payloads {
+			other, err := probabilistic.DeserializeExponentialHistogram(payload)
+			if err != nil {
+				return nil, fmt.Errorf("sketch %d: %v", i, err)
+			}
+			if err := histogram.Merge(other); err != nil {
+				return nil, fmt.Errorf("sketch %d: %w", i, err)
+			}
+		}
+		merged = histogram
+	default:
+		return nil, fmt.Errorf("unsupported quantile algorithm: %s", qe.quantileAlgorithm)
+	}
+
+	if merged.Count() == 0 {
+		return nil, fmt.Errorf("merged quantile sketch is empty")
+	}
+
+	return &metrics.QuantileMergeResult{
+		P50:       merged.Quantile(0.5),
+		P90:       merged.Quantile(0.9),
+		P95:       merged.Quantile(0.95),
+		P99:       merged.Quantile(0.99),
+		Count:     merged.Count(),
+		Algorithm: qe.quantileAlgorithm,
+	}, nil
+}
+
+func (qe *QueryEngine) HLLDelta(since []uint8) ([]probabilistic.BucketEntry, error) {
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	return qe.hll.DeltaSince(since)
+}
+
+func (qe *QueryEngine) HLLMerkleTree() probabilistic.HLLMerkleTree {
+	qe.mutex.RLock()
+	defer qe.mutex.RUnlock()
+
+	return qe.hll.MerkleTree()
+}
+
+func (qe *QueryEngine) ApplyHLLDelta(delta []probabilistic.BucketEntry) (probabilistic.HLLStats, error) {
+	qe.mutex.Lock()
+	defer qe.mutex.Unlock()
+
+	if err := qe.hll.ApplyDelta(delta); err != nil {
+		return probabilistic.HLLStats{}, err
+	}
+	return qe.hll.GetStats(), nil
+}
+
+func (qe *QueryEngine) ExportCMS() ([]byte, error) {
+	qe.mutex.Lock()
+	defer qe.mutex.Unlock()
+
+	return qe.cms.Serialize()
+}
+
+func (qe *QueryEngine) MergeCMS(data []byte) (probabilistic.CMSStats, error) {
+	qe.mutex.Lock()
+	defer qe.mutex.Unlock()
+
+	other, err := probabilistic.DeserializeCountMinSketch(data)
+	if err != nil {
+		return probabilistic.CMSStats{}, err
+	}
+
+	if err := qe.cms.Merge(other); err != nil {
+		return probabilistic.CMSStats{}, err
+	}
+
+	return qe.cms.GetStats(), nil
+}
@@ -0,0 +1,166 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/asmit27rai/kubesight/internal/probabilistic"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+// skewedFixture gives pod-i (i+1)*4 samples so every series has a distinct
+// frequency and the top-K order is unambiguous.
+func skewedFixture(pods int) []*metrics.MetricPoint {
+	var fixture []*metrics.MetricPoint
+	for i := 0; i < pods; i++ {
+		fixture = append(fixture, NewFixtureBuilder().
+			WithPod(fmt.Sprintf("pod-%02d", i)).
+			WithValues(rampValues((i+1)*4, 0.1, 0.5)).
+			Build()...)
+	}
+	return fixture
+}
+
+func topKKeys(t *testing.T, qe *QueryEngine, k int) []string {
+	t.Helper()
+
+	result, err := qe.ExecuteQuery(&metrics.QueryRequest{
+		QueryType: metrics.TopK,
+		Query:     fmt.Sprintf("TOP_K(%d)", k),
+	})
+	if err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", err)
+	}
+
+	items := result.Result.(*metrics.TopKResult).Items
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = fmt.Sprintf("%s=%d", item.Key, item.Count)
+	}
+	return keys
+}
+
+func TestMergeCMSMatchesSingleEngineTopK(t *testing.T) {
+	const k = spaceSavingMaxK + 5
+
+	stream := skewedFixture(k + 10)
+	single := seededTestEngine(t, stream)
+
+	halves := [2][]*metrics.MetricPoint{}
+	for i, point := range stream {
+		halves[i%2] = append(halves[i%2], point)
+	}
+
+	engines := [2]*QueryEngine{newTestEngine(t, 1.0), newTestEngine(t, 1.0)}
+	var wg sync.WaitGroup
+	for i := range engines {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			SeedEngine(engines[i], halves[i])
+		}(i)
+	}
+	wg.Wait()
+
+	exported, err := engines[1].ExportCMS()
+	if err != nil {
+		t.Fatalf("ExportCMS() error = %v", err)
+	}
+	stats, err := engines[0].MergeCMS(exported)
+	if err != nil {
+		t.Fatalf("MergeCMS() error = %v", err)
+	}
+	if stats.TotalCount != uint64(len(stream)) {
+		t.Fatalf("merged total = %d, want %d", stats.TotalCount, len(stream))
+	}
+
+	want := topKKeys(t, single, k)
+	got := topKKeys(t, engines[0], k)
+	if len(got) != len(want) {
+		t.Fatalf("merged top-K has %d items, single engine %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("merged top-K[%d] = %s, single engine %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestExecuteQuantilesAccuracy(t *testing.T) {
+	const n = 10000
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+
+	for _, algorithm := range []string{QuantileAlgorithmKLL, QuantileAlgorithmExponential} {
+		t.Run(algorithm, func(t *testing.T) {
+			qe := newTestEngine(t, 1.0)
+			qe.quantileAlgorithm = algorithm
+			qe.histogramScale = 6
+			SeedEngine(qe, NewFixtureBuilder().
+				WithPods("pod-1", "pod-2", "pod-3", "pod-4", "pod-5", "pod-6", "pod-7", "pod-8", "pod-9", "pod-10").
+				WithValues(values).
+				Build())
+
+			quantiles := []float64{0.1, 0.5, 0.9, 0.99}
+			result, err := qe.ExecuteQuantiles("cpu_usage", quantiles)
+			if err != nil {
+				t.Fatalf("ExecuteQuantiles() error = %v", err)
+			}
+			if result.Count != n {
+				t.Fatalf("Count = %d, want %d", result.Count, n)
+			}
+
+			for _, q := range quantiles {
+				got := result.Quantiles[fmt.Sprint(q)]
+				want := q * n
+				if relErr := math.Abs(got-want) / want; relErr > 0.02 {
+					t.Errorf("q%.2f = %v, want %v within 2%% (got %.2f%%)", q, got, want, relErr*100)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeQuantileSketchesAccuracy(t *testing.T) {
+	qe := newTestEngine(t, 1.0)
+
+	var payloads [][]byte
+	for part := 0; part < 4; part++ {
+		sketch := probabilistic.NewKLLSketch(probabilistic.DefaultKLLK)
+		for i := part; i < 20000; i += 4 {
+			sketch.Update(float64(i + 1))
+		}
+		payload, err := sketch.Serialize()
+		if err != nil {
+			t.Fatalf("Serialize() error = %v", err)
+		}
+		payloads = append(payloads, payload)
+	}
+
+	result, err := qe.MergeQuantileSketches(payloads)
+	if err != nil {
+		t.Fatalf("MergeQuantileSketches() error = %v", err)
+	}
+	if result.Count != 20000 {
+		t.Fatalf("Count = %d, want 20000", result.Count)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"p50", result.P50, 10000},
+		{"p90", result.P90, 18000},
+		{"p95", result.P95, 19000},
+		{"p99", result.P99, 19800},
+	} {
+		if relErr := math.Abs(tc.got-tc.want) / tc.want; relErr > 0.02 {
+			t.Errorf("%s = %v, want %v within 2%%", tc.name, tc.got, tc.want)
+		}
+	}
+}
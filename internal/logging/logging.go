@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level: %s", level)
+	}
+}
+
+func Setup(w io.Writer, level string) error {
+	parsed, err := ParseLevel(level)
+
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: parsed})
+	slog.SetDefault(slog.New(handler))
+
+	return err
+}
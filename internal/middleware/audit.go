@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+type contextKey string
+
+const userContextKey contextKey = "kubesight-user"
+
+type AuditRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status"`
+	User      string    `json:"user"`
+	RequestID string    `json:"request_id"`
+	IP        string    `json:"ip"`
+	BodySize  int64     `json:"body_size"`
+}
+
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+func UserFromContext(ctx context.Context) string {
+	if user, ok := ctx.Value(userContextKey).(string); ok && user != "" {
+		return user
+	}
+	return "anonymous"
+}
+
+func AuditLogger(auditLog io.Writer) func(http.Handler) http.Handler {
+	var writeMutex sync.Mutex
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			requestID := r.Header.Get("X-Request-ID")
+			if requestID == "" {
+				requestID = fmt.Sprintf("req_%d", time.Now().UnixNano())
+			}
+
+			body := &countingReader{ReadCloser: r.Body}
+			r.Body = body
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			record := AuditRecord{
+				Timestamp: time.Now(),
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Status:    recorder.status,
+				User:      UserFromContext(r.Context()),
+				RequestID: requestID,
+				IP:        clientIP(r),
+				BodySize:  body.count,
+			}
+
+			data, err := json.Marshal(record)
+			if err != nil {
+				LoggerFromContext(r.Context()).Error("Failed to encode audit record", "error", err)
+				return
+			}
+
+			writeMutex.Lock()
+			defer writeMutex.Unlock()
+			if _, err := auditLog.Write(append(data, '\n')); err != nil {
+				LoggerFromContext(r.Context()).Error("Failed to write audit record", "error", err)
+			}
+		})
+	}
+}
+
+type RotatingFile struct {
+	path  string
+	file  *os.File
+	mutex sync.Mutex
+}
+
+func OpenAuditLog(path string) (io.Writer, error) {
+	if path == "" {
+		return os.Stderr, nil
+	}
+
+	rf := &RotatingFile{path: path}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	return rf.file.Write(p)
+}
+
+func (rf *RotatingFile) Rotate() error {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	if err := rf.file.Close(); err != nil {
+		slog.Error("Failed to close audit log", "path", rf.path, "error", err)
+	}
+
+	rotated := fmt.Sprintf("%s.%s", rf.path, time.Now().Format("20060102T150405"))
+	if err := os.Rename(rf.path, rotated); err != nil && !os.IsNotExist(err) {
+		slog.Error("Failed to rename audit log", "path", rf.path, "error", err)
+	}
+
+	return rf.open()
+}
+
+func (rf *RotatingFile) Close() error {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	return rf.file.Close()
+}
+
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	rf.file = file
+	return nil
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	if !sr.wroteHeader {
+		sr.status = status
+		sr.wroteHeader = true
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Flush() {
+	if err := http.NewResponseController(sr.ResponseWriter).Flush(); err != nil {
+		slog.Debug("Underlying response writer does not support flushing", "error", err)
+	}
+}
+
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+type countingReader struct {
+	io.ReadCloser
+	count int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.ReadCloser.Read(p)
+	cr.count += int64(n)
+	return n, err
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
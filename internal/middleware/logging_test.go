@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func sseHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "data: %d\n\n", 1)
+	flusher.Flush()
+}
+
+func TestMiddlewarePreservesFlusher(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		wrap   func(http.Handler) http.Handler
+	}{
+		{name: "request logger", method: http.MethodGet, wrap: RequestLogger},
+		{name: "audit logger", method: http.MethodPost, wrap: AuditLogger(&bytes.Buffer{})},
+		{name: "full stack", method: http.MethodPost, wrap: func(next http.Handler) http.Handler {
+			return RequestLogger(AuditLogger(&bytes.Buffer{})(next))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			tt.wrap(http.HandlerFunc(sseHandler)).ServeHTTP(recorder, httptest.NewRequest(tt.method, "/api/v1/stream", nil))
+
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body.String())
+			}
+			if !recorder.Flushed {
+				t.Error("handler output was not flushed through the middleware")
+			}
+			if got := recorder.Body.String(); got != "data: 1\n\n" {
+				t.Errorf("body = %q, want one SSE event", got)
+			}
+		})
+	}
+}
+
+func TestStatusRecorderFlushWithoutFlusher(t *testing.T) {
+	recorder := &statusRecorder{ResponseWriter: struct{ http.ResponseWriter }{httptest.NewRecorder()}}
+	recorder.Flush()
+}
@@ -0,0 +1,100 @@
+package probabilistic
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+var benchSizes = []int{1_000, 100_000, 1_000_000}
+
+var (
+	benchItemsOnce sync.Once
+	benchItemsAll  [][]byte
+)
+
+// benchItems returns the first n items of a shared pool so the 1M-item
+// inputs are only built once per test binary.
+func benchItems(n int) [][]byte {
+	benchItemsOnce.Do(func() {
+		benchItemsAll = hllTestItems(benchSizes[len(benchSizes)-1])
+	})
+	return benchItemsAll[:n]
+}
+
+func runSketchBenchmarks(b *testing.B, op func(items [][]byte) func(item []byte)) {
+	for _, n := range benchSizes {
+		items := benchItems(n)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			fn := op(items)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				fn(items[i%len(items)])
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/parallel", n), func(b *testing.B) {
+			fn := op(items)
+			var next atomic.Uint64
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					fn(items[next.Add(1)%uint64(len(items))])
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkHLLAdd(b *testing.B) {
+	runSketchBenchmarks(b, func(items [][]byte) func([]byte) {
+		hll := NewHyperLogLog(14)
+		return hll.Add
+	})
+}
+
+func BenchmarkHLLCount(b *testing.B) {
+	runSketchBenchmarks(b, func(items [][]byte) func([]byte) {
+		hll := NewHyperLogLog(14)
+		for _, item := range items {
+			hll.Add(item)
+		}
+		return func([]byte) { hll.Count() }
+	})
+}
+
+func BenchmarkCMSUpdate(b *testing.B) {
+	runSketchBenchmarks(b, func(items [][]byte) func([]byte) {
+		cms := NewCountMinSketch(2048, 4)
+		return func(item []byte) { cms.Update(item, 1) }
+	})
+}
+
+func BenchmarkCMSEstimate(b *testing.B) {
+	runSketchBenchmarks(b, func(items [][]byte) func([]byte) {
+		cms := NewCountMinSketch(2048, 4)
+		for _, item := range items {
+			cms.Update(item, 1)
+		}
+		return func(item []byte) { cms.Estimate(item) }
+	})
+}
+
+func BenchmarkBloomAdd(b *testing.B) {
+	runSketchBenchmarks(b, func(items [][]byte) func([]byte) {
+		bloom := NewBloomFilterOptimal(uint32(len(items)), 0.01)
+		return bloom.Add
+	})
+}
+
+func BenchmarkBloomContains(b *testing.B) {
+	runSketchBenchmarks(b, func(items [][]byte) func([]byte) {
+		bloom := NewBloomFilterOptimal(uint32(len(items)), 0.01)
+		for _, item := range items {
+			bloom.Add(item)
+		}
+		return func(item []byte) { bloom.Contains(item) }
+	})
+}
@@ -1,9 +1,12 @@
 package probabilistic
 
 import (
+	"bytes"
+	"encoding/gob"
 	"fmt"
 	"hash/fnv"
 	"math"
+	"os"
 	"sync"
 )
 
@@ -186,6 +189,76 @@ func (bf *BloomFilter) countSetBits() uint32 {
 	return count
 }
 
+type bloomState struct {
+	Size      uint32
+	NumHashes uint32
+	NumItems  uint32
+	Bits      []byte
+}
+
+func (bf *BloomFilter) Serialize() ([]byte, error) {
+	bf.mutex.RLock()
+	state := bloomState{
+		Size:      bf.size,
+		NumHashes: bf.numHashes,
+		NumItems:  bf.numItems,
+		Bits:      make([]byte, (bf.size+7)/8),
+	}
+	for i, set := range bf.bits {
+		if set {
+			state.Bits[i/8] |= 1 << (i % 8)
+		}
+	}
+	bf.mutex.RUnlock()
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return nil, fmt.Errorf("failed to encode bloom filter: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func DeserializeBloomFilter(data []byte) (*BloomFilter, error) {
+	var state bloomState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state); err != nil {
+		return nil, fmt.Errorf("failed to decode bloom filter: %v", err)
+	}
+	if state.Size == 0 || state.NumHashes == 0 || uint32(len(state.Bits)) != (state.Size+7)/8 {
+		return nil, fmt.Errorf("invalid bloom filter dimensions: size %d, hashes %d", state.Size, state.NumHashes)
+	}
+
+	bf := NewBloomFilter(state.Size, state.NumHashes)
+	bf.numItems = state.NumItems
+	for i := range bf.bits {
+		bf.bits[i] = state.Bits[i/8]&(1<<(i%8)) != 0
+	}
+	return bf, nil
+}
+
+func (bf *BloomFilter) SaveToFile(path string) error {
+	data, err := bf.Serialize()
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write bloom filter: %v", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace bloom filter file: %v", err)
+	}
+	return nil
+}
+
+func LoadBloomFilterFromFile(path string) (*BloomFilter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeBloomFilter(data)
+}
+
 type BloomStats struct {
 	Size              uint32  `json:"size"`
 	NumHashes         uint32  `json:"num_hashes"`
@@ -0,0 +1,102 @@
+package probabilistic
+
+import (
+	"math"
+	"sort"
+)
+
+const bucketEntryBytes = 8
+
+type CompressedHLL struct {
+	precision uint8
+	m         uint32
+	alpha     float64
+	entries   []BucketEntry
+}
+
+type CompressionStats struct {
+	Precision         uint8   `json:"precision"`
+	Entries           int     `json:"entries"`
+	DenseBytes        int     `json:"dense_bytes"`
+	CompressedBytes   int     `json:"compressed_bytes"`
+	CompressionRatio  float64 `json:"compression_ratio"`
+	EstimatedItems    uint64  `json:"estimated_items"`
+	CompressThreshold uint64  `json:"compress_threshold"`
+	ShouldDecompress  bool    `json:"should_decompress"`
+}
+
+func CompressionThreshold(precision uint8) uint64 {
+	return uint64(1) << (precision / 2)
+}
+
+func (hll *HyperLogLog) IsCompressible() bool {
+	return hll.Count() < CompressionThreshold(hll.precision)
+}
+
+func (hll *HyperLogLog) Compress() *CompressedHLL {
+	hll.mutex.RLock()
+	defer hll.mutex.RUnlock()
+
+	return &CompressedHLL{
+		precision: hll.precision,
+		m:         hll.m,
+		alpha:     hll.alpha,
+		entries:   hll.populatedEntries(),
+	}
+}
+
+func (c *CompressedHLL) Decompress() *HyperLogLog {
+	hll := NewHyperLogLog(c.precision)
+	for _, entry := range c.entries {
+		hll.setBucket(entry.Index, entry.Value)
+	}
+	return hll
+}
+
+func (c *CompressedHLL) Bucket(index uint32) uint8 {
+	i := sort.Search(len(c.entries), func(i int) bool {
+		return c.entries[i].Index >= index
+	})
+	if i < len(c.entries) && c.entries[i].Index == index {
+		return c.entries[i].Value
+	}
+	return 0
+}
+
+func (c *CompressedHLL) Count() uint64 {
+	emptyBuckets := int(c.m) - len(c.entries)
+
+	sum := float64(emptyBuckets)
+	for _, entry := range c.entries {
+		sum += math.Pow(2, -float64(entry.Value))
+	}
+
+	return estimateCardinality(c.precision, c.m, c.alpha, sum, emptyBuckets)
+}
+
+func (c *CompressedHLL) Len() int {
+	return len(c.entries)
+}
+
+func (c *CompressedHLL) GetStats() CompressionStats {
+	denseBytes := int(c.m)
+	compressedBytes := len(c.entries) * bucketEntryBytes
+	estimated := c.Count()
+	threshold := CompressionThreshold(c.precision)
+
+	ratio := 0.0
+	if compressedBytes > 0 {
+		ratio = float64(denseBytes) / float64(compressedBytes)
+	}
+
+	return CompressionStats{
+		Precision:         c.precision,
+		Entries:           len(c.entries),
+		DenseBytes:        denseBytes,
+		CompressedBytes:   compressedBytes,
+		CompressionRatio:  ratio,
+		EstimatedItems:    estimated,
+		CompressThreshold: threshold,
+		ShouldDecompress:  estimated >= threshold,
+	}
+}
@@ -1,6 +1,8 @@
 package probabilistic
 
 import (
+	"bytes"
+	"encoding/gob"
 	"fmt"
 	"hash/fnv"
 	"math"
@@ -14,17 +16,25 @@ type CountMinSketch struct {
 	count [][]uint32
 	hashA []uint32
 	hashB []uint32
+	seed  uint64
 	mutex sync.RWMutex
 	total uint64
+
+	registry *ItemRegistry
 }
 
 func NewCountMinSketch(width, depth uint32) *CountMinSketch {
+	return NewCountMinSketchWithSeed(width, depth, 0)
+}
+
+func NewCountMinSketchWithSeed(width, depth uint32, seed uint64) *CountMinSketch {
 	cms := &CountMinSketch{
 		width: width,
 		depth: depth,
 		count: make([][]uint32, depth),
 		hashA: make([]uint32, depth),
 		hashB: make([]uint32, depth),
+		seed:  seed,
 		total: 0,
 	}
 
@@ -33,13 +43,22 @@ func NewCountMinSketch(width, depth uint32) *CountMinSketch {
 	}
 
 	for i := uint32(0); i < depth; i++ {
-		cms.hashA[i] = uint32(i*2 + 1)
-		cms.hashB[i] = uint32(i*3 + 7)
+		if seed == 0 {
+			cms.hashA[i] = uint32(i*2 + 1)
+			cms.hashB[i] = uint32(i*3 + 7)
+			continue
+		}
+		cms.hashA[i] = uint32(murmur3Mix(seed+uint64(i)*2)) | 1
+		cms.hashB[i] = uint32(murmur3Mix(seed + uint64(i)*2 + 1))
 	}
 
 	return cms
 }
 
+func (cms *CountMinSketch) Seed() uint64 {
+	return cms.seed
+}
+
 func NewCountMinSketchFromErrorRate(errorRate, confidence float64) *CountMinSketch {
 	width := uint32(math.Ceil(math.E / errorRate))
 	depth := uint32(math.Ceil(math.Log(1 / confidence)))
@@ -50,6 +69,21 @@ func (cms *CountMinSketch) Update(item []byte, count uint32) {
 	cms.mutex.Lock()
 	defer cms.mutex.Unlock()
 
+	cms.update(item, count)
+}
+
+func (cms *CountMinSketch) UpdateWithKey(key string, count uint32) {
+	cms.mutex.Lock()
+	defer cms.mutex.Unlock()
+
+	if cms.registry == nil {
+		cms.registry = NewItemRegistry(DefaultRegistryCapacity)
+	}
+	cms.registry.Register(key)
+	cms.update([]byte(key), count)
+}
+
+func (cms *CountMinSketch) update(item []byte, count uint32) {
 	hash := cms.hash(item)
 
 	for i := uint32(0); i < cms.depth; i++ {
@@ -64,6 +98,10 @@ func (cms *CountMinSketch) Estimate(item []byte) uint32 {
 	cms.mutex.RLock()
 	defer cms.mutex.RUnlock()
 
+	return cms.estimate(item)
+}
+
+func (cms *CountMinSketch) estimate(item []byte) uint32 {
 	hash := cms.hash(item)
 	minCount := uint32(math.MaxUint32)
 
@@ -91,10 +129,13 @@ func (cms *CountMinSketch) HeavyHitters(threshold float64) []HeavyHitterItem {
 		}
 	}
 
+	keys := cms.resolveBucketKeys(candidates)
+
 	var results []HeavyHitterItem
 	for bucket, count := range candidates {
 		results = append(results, HeavyHitterItem{
 			Bucket:    bucket,
+			Key:       keys[bucket],
 			Count:     count,
 			Frequency: float64(count) / float64(cms.total),
 		})
@@ -107,6 +148,30 @@ func (cms *CountMinSketch) HeavyHitters(threshold float64) []HeavyHitterItem {
 	return results
 }
 
+func (cms *CountMinSketch) resolveBucketKeys(candidates map[uint32]uint32) map[uint32]string {
+	keys := make(map[uint32]string)
+	if cms.registry == nil || len(candidates) == 0 {
+		return keys
+	}
+
+	best := make(map[uint32]uint32)
+	for _, key := range cms.registry.Keys() {
+		item := []byte(key)
+		bucket := cms.getBucket(cms.hash(item), 0)
+		if _, isCandidate := candidates[bucket]; !isCandidate {
+			continue
+		}
+
+		estimate := cms.estimate(item)
+		if current, exists := keys[bucket]; !exists || estimate > best[bucket] || (estimate == best[bucket] && key < current) {
+			keys[bucket] = key
+			best[bucket] = estimate
+		}
+	}
+
+	return keys
+}
+
 func (cms *CountMinSketch) TopK(k int) []HeavyHitterItem {
 	heavyHitters := cms.HeavyHitters(0.0)
 
@@ -121,6 +186,9 @@ func (cms *CountMinSketch) Merge(other *CountMinSketch) error {
 	if cms.width != other.width || cms.depth != other.depth {
 		return fmt.Errorf("dimension mismatch: cannot merge CMS of different dimensions")
 	}
+	if cms.seed != other.seed {
+		return fmt.Errorf("seed mismatch: cannot merge CMS with seeds %d and %d", cms.seed, other.seed)
+	}
 
 	cms.mutex.Lock()
 	other.mutex.RLock()
@@ -135,9 +203,67 @@ func (cms *CountMinSketch) Merge(other *CountMinSketch) error {
 
 	cms.total += other.total
 
+	if other.registry != nil {
+		if cms.registry == nil {
+			cms.registry = NewItemRegistry(DefaultRegistryCapacity)
+		}
+		for _, key := range other.registry.Keys() {
+			cms.registry.Register(key)
+		}
+	}
+
 	return nil
 }
 
+type cmsState struct {
+	Width uint32
+	Depth uint32
+	Count [][]uint32
+	Total uint64
+	Seed  uint64
+}
+
+func (cms *CountMinSketch) Serialize() ([]byte, error) {
+	cms.mutex.RLock()
+	defer cms.mutex.RUnlock()
+
+	state := cmsState{
+		Width: cms.width,
+		Depth: cms.depth,
+		Count: cms.count,
+		Total: cms.total,
+		Seed:  cms.seed,
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return nil, fmt.Errorf("failed to encode count-min sketch: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func DeserializeCountMinSketch(data []byte) (*CountMinSketch, error) {
+	var state cmsState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state); err != nil {
+		return nil, fmt.Errorf("failed to decode count-min sketch: %v", err)
+	}
+
+	if state.Width == 0 || state.Depth == 0 || uint32(len(state.Count)) != state.Depth {
+		return nil, fmt.Errorf("invalid count-min sketch dimensions: %dx%d", state.Width, state.Depth)
+	}
+	for _, row := range state.Count {
+		if uint32(len(row)) != state.Width {
+			return nil, fmt.Errorf("invalid count-min sketch row width: %d", len(row))
+		}
+	}
+
+	cms := NewCountMinSketchWithSeed(state.Width, state.Depth, state.Seed)
+	cms.count = state.Count
+	cms.total = state.Total
+
+	return cms, nil
+}
+
 func (cms *CountMinSketch) Clear() {
 	cms.mutex.Lock()
 	defer cms.mutex.Unlock()
@@ -148,6 +274,9 @@ func (cms *CountMinSketch) Clear() {
 		}
 	}
 	cms.total = 0
+	if cms.registry != nil {
+		cms.registry.Clear()
+	}
 }
 
 func (cms *CountMinSketch) GetStats() CMSStats {
@@ -186,14 +315,27 @@ func (cms *CountMinSketch) hash(data []byte) uint64 {
 	return hasher.Sum64()
 }
 
+func murmur3Mix(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
+
 func (cms *CountMinSketch) getBucket(hash uint64, row uint32) uint32 {
 	a := uint64(cms.hashA[row])
 	b := uint64(cms.hashB[row])
+	if cms.seed != 0 {
+		return uint32(murmur3Mix(a*hash+b) % uint64(cms.width))
+	}
 	return uint32((a*hash + b) % uint64(cms.width))
 }
 
 type HeavyHitterItem struct {
 	Bucket    uint32  `json:"bucket"`
+	Key       string  `json:"key,omitempty"`
 	Count     uint32  `json:"count"`
 	Frequency float64 `json:"frequency"`
 }
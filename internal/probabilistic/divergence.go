@@ -0,0 +1,53 @@
+package probabilistic
+
+import "math"
+
+func JSDivergence(p, q []float64) float64 {
+	n := len(p)
+	if len(q) > n {
+		n = len(q)
+	}
+
+	pNorm := normalizeDistribution(p, n)
+	qNorm := normalizeDistribution(q, n)
+	if pNorm == nil || qNorm == nil {
+		return 1
+	}
+
+	m := make([]float64, n)
+	for i := range m {
+		m[i] = (pNorm[i] + qNorm[i]) / 2
+	}
+
+	return (klDivergence(pNorm, m) + klDivergence(qNorm, m)) / 2
+}
+
+func normalizeDistribution(values []float64, size int) []float64 {
+	total := 0.0
+	for _, v := range values {
+		if v > 0 {
+			total += v
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	normalized := make([]float64, size)
+	for i, v := range values {
+		if v > 0 {
+			normalized[i] = v / total
+		}
+	}
+	return normalized
+}
+
+func klDivergence(p, q []float64) float64 {
+	divergence := 0.0
+	for i := range p {
+		if p[i] > 0 && q[i] > 0 {
+			divergence += p[i] * math.Log2(p[i]/q[i])
+		}
+	}
+	return divergence
+}
@@ -0,0 +1,369 @@
+package probabilistic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+const (
+	MinExponentialScale          = -10
+	MaxExponentialScale          = 20
+	DefaultExponentialScale      = 8
+	DefaultExponentialMaxBuckets = 160
+)
+
+type ExponentialHistogram struct {
+	scale      int32
+	maxBuckets int
+	positive   map[int32]uint64
+	negative   map[int32]uint64
+	zeroCount  uint64
+	count      uint64
+	sum        float64
+	min        float64
+	max        float64
+	mutex      sync.RWMutex
+}
+
+type ExponentialBucket struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count uint64  `json:"count"`
+}
+
+func NewExponentialHistogram(scale int32) *ExponentialHistogram {
+	if scale < MinExponentialScale || scale > MaxExponentialScale {
+		scale = DefaultExponentialScale
+	}
+
+	return &ExponentialHistogram{
+		scale:      scale,
+		maxBuckets: DefaultExponentialMaxBuckets,
+		positive:   make(map[int32]uint64),
+		negative:   make(map[int32]uint64),
+		min:        math.Inf(1),
+		max:        math.Inf(-1),
+	}
+}
+
+func (eh *ExponentialHistogram) Record(value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+
+	eh.mutex.Lock()
+	defer eh.mutex.Unlock()
+
+	eh.count++
+	eh.sum += value
+	eh.min = math.Min(eh.min, value)
+	eh.max = math.Max(eh.max, value)
+
+	switch {
+	case value > 0:
+		eh.positive[bucketIndex(value, eh.scale)]++
+	case value < 0:
+		eh.negative[bucketIndex(-value, eh.scale)]++
+	default:
+		eh.zeroCount++
+	}
+
+	for eh.scale > MinExponentialScale &&
+		(bucketSpan(eh.positive) > eh.maxBuckets || bucketSpan(eh.negative) > eh.maxBuckets) {
+		eh.downscale(1)
+	}
+}
+
+func (eh *ExponentialHistogram) Merge(other *ExponentialHistogram) error {
+	if other == nil {
+		return fmt.Errorf("cannot merge nil exponential histogram")
+	}
+	if eh == other {
+		return fmt.Errorf("cannot merge exponential histogram with itself")
+	}
+
+	eh.mutex.Lock()
+	other.mutex.RLock()
+	defer eh.mutex.Unlock()
+	defer other.mutex.RUnlock()
+
+	if other.scale < eh.scale {
+		eh.downscale(eh.scale - other.scale)
+	}
+	shift := other.scale - eh.scale
+
+	for idx, count := range other.positive {
+		eh.positive[idx>>shift] += count
+	}
+	for idx, count := range other.negative {
+		eh.negative[idx>>shift] += count
+	}
+
+	eh.zeroCount += other.zeroCount
+	eh.count += other.count
+	eh.sum += other.sum
+	eh.min = math.Min(eh.min, other.min)
+	eh.max = math.Max(eh.max, other.max)
+
+	return nil
+}
+
+func (eh *ExponentialHistogram) Quantile(q float64) float64 {
+	eh.mutex.RLock()
+	defer eh.mutex.RUnlock()
+
+	return eh.quantile(eh.buckets(), q)
+}
+
+func (eh *ExponentialHistogram) Quantiles(qs []float64) []float64 {
+	eh.mutex.RLock()
+	defer eh.mutex.RUnlock()
+
+	buckets := eh.buckets()
+	result := make([]float64, len(qs))
+	for i, q := range qs {
+		result[i] = eh.quantile(buckets, q)
+	}
+
+	return result
+}
+
+func (eh *ExponentialHistogram) CDF(value float64) float64 {
+	eh.mutex.RLock()
+	defer eh.mutex.RUnlock()
+
+	if eh.count == 0 {
+		return math.NaN()
+	}
+	if value < eh.min {
+		return 0
+	}
+	if value >= eh.max {
+		return 1
+	}
+
+	below := 0.0
+	for _, bucket := range eh.buckets() {
+		if bucket.Lower > value {
+			break
+		}
+		if bucket.Upper <= value {
+			below += float64(bucket.Count)
+			continue
+		}
+		fraction := (value - bucket.Lower) / (bucket.Upper - bucket.Lower)
+		below += float64(bucket.Count) * fraction
+	}
+
+	return below / float64(eh.count)
+}
+
+func (eh *ExponentialHistogram) quantile(buckets []ExponentialBucket, q float64) float64 {
+	if eh.count == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return eh.min
+	}
+	if q >= 1 {
+		return eh.max
+	}
+
+	rank := q * float64(eh.count-1)
+	seen := 0.0
+
+	for _, bucket := range buckets {
+		next := seen + float64(bucket.Count)
+		if rank < next {
+			fraction := (rank - seen + 0.5) / float64(bucket.Count)
+			value := bucket.Lower + (bucket.Upper-bucket.Lower)*fraction
+			return math.Min(math.Max(value, eh.min), eh.max)
+		}
+		seen = next
+	}
+
+	return eh.max
+}
+
+func (eh *ExponentialHistogram) Buckets() []ExponentialBucket {
+	eh.mutex.RLock()
+	defer eh.mutex.RUnlock()
+
+	return eh.buckets()
+}
+
+func (eh *ExponentialHistogram) Scale() int32 {
+	eh.mutex.RLock()
+	defer eh.mutex.RUnlock()
+
+	return eh.scale
+}
+
+func (eh *ExponentialHistogram) Count() uint64 {
+	eh.mutex.RLock()
+	defer eh.mutex.RUnlock()
+
+	return eh.count
+}
+
+func (eh *ExponentialHistogram) Serialize() []byte {
+	eh.mutex.RLock()
+	defer eh.mutex.RUnlock()
+
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.BigEndian, eh.scale)
+	binary.Write(buf, binary.BigEndian, uint32(eh.maxBuckets))
+	binary.Write(buf, binary.BigEndian, eh.zeroCount)
+	binary.Write(buf, binary.BigEndian, eh.count)
+	binary.Write(buf, binary.BigEndian, eh.sum)
+	binary.Write(buf, binary.BigEndian, eh.min)
+	binary.Write(buf, binary.BigEndian, eh.max)
+	writeBucketMap(buf, eh.positive)
+	writeBucketMap(buf, eh.negative)
+
+	return buf.Bytes()
+}
+
+func DeserializeExponentialHistogram(data []byte) (*ExponentialHistogram, error) {
+	reader := bytes.NewReader(data)
+	eh := NewExponentialHistogram(DefaultExponentialScale)
+
+	var maxBuckets uint32
+	fields := []interface{}{&eh.scale, &maxBuckets, &eh.zeroCount, &eh.count, &eh.sum, &eh.min, &eh.max}
+	for _, field := range fields {
+		if err := binary.Read(reader, binary.BigEndian, field); err != nil {
+			return nil, fmt.Errorf("failed to decode exponential histogram header: %v", err)
+		}
+	}
+	if eh.scale < MinExponentialScale || eh.scale > MaxExponentialScale {
+		return nil, fmt.Errorf("invalid exponential histogram scale: %d", eh.scale)
+	}
+	eh.maxBuckets = int(maxBuckets)
+
+	var err error
+	if eh.positive, err = readBucketMap(reader); err != nil {
+		return nil, err
+	}
+	if eh.negative, err = readBucketMap(reader); err != nil {
+		return nil, err
+	}
+
+	return eh, nil
+}
+
+func (eh *ExponentialHistogram) buckets() []ExponentialBucket {
+	result := make([]ExponentialBucket, 0, len(eh.negative)+len(eh.positive)+1)
+
+	for _, idx := range sortedBucketKeys(eh.negative, true) {
+		lower, upper := bucketBounds(idx, eh.scale)
+		result = append(result, ExponentialBucket{Lower: -upper, Upper: -lower, Count: eh.negative[idx]})
+	}
+
+	if eh.zeroCount > 0 {
+		result = append(result, ExponentialBucket{Lower: 0, Upper: 0, Count: eh.zeroCount})
+	}
+
+	for _, idx := range sortedBucketKeys(eh.positive, false) {
+		lower, upper := bucketBounds(idx, eh.scale)
+		result = append(result, ExponentialBucket{Lower: lower, Upper: upper, Count: eh.positive[idx]})
+	}
+
+	return result
+}
+
+func (eh *ExponentialHistogram) downscale(change int32) {
+	if change <= 0 {
+		return
+	}
+
+	eh.positive = downscaleBuckets(eh.positive, change)
+	eh.negative = downscaleBuckets(eh.negative, change)
+	eh.scale -= change
+}
+
+func bucketIndex(value float64, scale int32) int32 {
+	return int32(math.Ceil(math.Log2(value)*math.Ldexp(1, int(scale)))) - 1
+}
+
+func bucketBounds(idx, scale int32) (float64, float64) {
+	lower := math.Exp2(math.Ldexp(float64(idx), -int(scale)))
+	upper := math.Exp2(math.Ldexp(float64(idx+1), -int(scale)))
+	return lower, upper
+}
+
+func bucketSpan(buckets map[int32]uint64) int {
+	if len(buckets) == 0 {
+		return 0
+	}
+
+	first := true
+	var low, high int32
+	for idx := range buckets {
+		if first || idx < low {
+			low = idx
+		}
+		if first || idx > high {
+			high = idx
+		}
+		first = false
+	}
+	return int(high-low) + 1
+}
+
+func downscaleBuckets(buckets map[int32]uint64, change int32) map[int32]uint64 {
+	result := make(map[int32]uint64, len(buckets))
+	for idx, count := range buckets {
+		result[idx>>change] += count
+	}
+	return result
+}
+
+func sortedBucketKeys(buckets map[int32]uint64, descending bool) []int32 {
+	keys := make([]int32, 0, len(buckets))
+	for idx := range buckets {
+		keys = append(keys, idx)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if descending {
+			return keys[i] > keys[j]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func writeBucketMap(buf *bytes.Buffer, buckets map[int32]uint64) {
+	binary.Write(buf, binary.BigEndian, uint32(len(buckets)))
+	for _, idx := range sortedBucketKeys(buckets, false) {
+		binary.Write(buf, binary.BigEndian, idx)
+		binary.Write(buf, binary.BigEndian, buckets[idx])
+	}
+}
+
+func readBucketMap(reader *bytes.Reader) (map[int32]uint64, error) {
+	var size uint32
+	if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+		return nil, fmt.Errorf("failed to decode bucket count: %v", err)
+	}
+	if int(size) > reader.Len()/12 {
+		return nil, fmt.Errorf("bucket count %d exceeds payload size", size)
+	}
+
+	buckets := make(map[int32]uint64, size)
+	for i := uint32(0); i < size; i++ {
+		var idx int32
+		var count uint64
+		if err := binary.Read(reader, binary.BigEndian, &idx); err != nil {
+			return nil, fmt.Errorf("failed to decode bucket index: %v", err)
+		}
+		if err := binary.Read(reader, binary.BigEndian, &count); err != nil {
+			return nil, fmt.Errorf("failed to decode bucket count: %v", err)
+		}
+		buckets[idx] = count
+	}
+	return buckets, nil
+}
@@ -1,10 +1,15 @@
 package probabilistic
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"hash/fnv"
 	"math"
+	"sort"
 	"sync"
+
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
 )
 
 type HyperLogLog struct {
@@ -13,10 +18,18 @@ type HyperLogLog struct {
 	buckets   []uint8
 	alpha     float64
 	mutex     sync.RWMutex
+
+	sparse      []uint32
+	sparseLimit uint32
 }
 
+const (
+	MinHLLPrecision uint8 = 4
+	MaxHLLPrecision uint8 = 18
+)
+
 func NewHyperLogLog(precision uint8) *HyperLogLog {
-	if precision < 4 || precision > 16 {
+	if precision < MinHLLPrecision || precision > MaxHLLPrecision {
 		precision = 14
 	}
 
@@ -24,13 +37,19 @@ func NewHyperLogLog(precision uint8) *HyperLogLog {
 	hll := &HyperLogLog{
 		precision: precision,
 		m:         m,
-		buckets:   make([]uint8, m),
 		alpha:     calculateAlpha(m),
+
+		sparse:      make([]uint32, 0),
+		sparseLimit: sparseLimit(m, DefaultSparseThreshold),
 	}
 
 	return hll
 }
 
+func (hll *HyperLogLog) Precision() uint8 {
+	return hll.precision
+}
+
 func (hll *HyperLogLog) Add(data []byte) {
 	hll.mutex.Lock()
 	defer hll.mutex.Unlock()
@@ -45,8 +64,8 @@ func (hll *HyperLogLog) Add(data []byte) {
 		leadingZeros = uint8(countLeadingZeros(w)) + 1
 	}
 
-	if leadingZeros > hll.buckets[bucketIdx] {
-		hll.buckets[bucketIdx] = leadingZeros
+	if leadingZeros > hll.bucket(uint32(bucketIdx)) {
+		hll.setBucket(uint32(bucketIdx), leadingZeros)
 	}
 }
 
@@ -54,6 +73,16 @@ func (hll *HyperLogLog) Count() uint64 {
 	hll.mutex.RLock()
 	defer hll.mutex.RUnlock()
 
+	if hll.sparse != nil {
+		emptyBuckets := int(hll.m) - len(hll.sparse)
+		sum := float64(emptyBuckets)
+		for _, entry := range hll.sparse {
+			_, value := unpackSparseEntry(entry)
+			sum += math.Pow(2, -float64(value))
+		}
+		return estimateCardinality(hll.precision, hll.m, hll.alpha, sum, emptyBuckets)
+	}
+
 	sum := 0.0
 	emptyBuckets := 0
 
@@ -64,32 +93,107 @@ func (hll *HyperLogLog) Count() uint64 {
 		sum += math.Pow(2, -float64(bucket))
 	}
 
-	estimate := hll.alpha * math.Pow(float64(hll.m), 2) / sum
+	return estimateCardinality(hll.precision, hll.m, hll.alpha, sum, emptyBuckets)
+}
+
+var linearCountingThresholds = [...]float64{
+	10, 16, 36, 80, 208, 416, 1088, 2176, 5376, 10752, 17408, 34816, 102400, 204800, 475136,
+}
+
+const biasNeighbours = 6
+
+type hllBiasTable struct {
+	raw  []float64
+	bias []float64
+}
+
+func hasBiasTable(precision uint8) bool {
+	return precision >= MinHLLPrecision && int(precision-MinHLLPrecision) < len(hllBiasTables)
+}
+
+func estimateCardinality(precision uint8, m uint32, alpha, sum float64, emptyBuckets int) uint64 {
+	estimate := alpha * math.Pow(float64(m), 2) / sum
 
-	if estimate <= 2.5*float64(hll.m) && emptyBuckets > 0 {
-		estimate = float64(hll.m) * math.Log(float64(hll.m)/float64(emptyBuckets))
+	if !hasBiasTable(precision) {
+		if estimate <= 2.5*float64(m) && emptyBuckets > 0 {
+			estimate = float64(m) * math.Log(float64(m)/float64(emptyBuckets))
+		}
+		return uint64(estimate)
 	}
 
-	if estimate > (1.0/30.0)*math.Pow(2, 32) {
-		estimate = -math.Pow(2, 32) * math.Log(1-estimate/math.Pow(2, 32))
+	if estimate <= 5*float64(m) {
+		estimate -= estimateBias(precision, estimate)
+	}
+	if emptyBuckets > 0 {
+		linear := float64(m) * math.Log(float64(m)/float64(emptyBuckets))
+		if linear <= linearCountingThresholds[precision-MinHLLPrecision] {
+			estimate = linear
+		}
 	}
 
-	return uint64(estimate)
+	return uint64(math.Max(estimate, 0))
+}
+
+func estimateBias(precision uint8, estimate float64) float64 {
+	table := hllBiasTables[precision-MinHLLPrecision]
+
+	hi := sort.SearchFloat64s(table.raw, estimate)
+	lo := hi - 1
+	total := 0.0
+	for n := 0; n < biasNeighbours; n++ {
+		switch {
+		case lo < 0 && hi >= len(table.raw):
+			return total / float64(n)
+		case lo < 0 || (hi < len(table.raw) && table.raw[hi]-estimate < estimate-table.raw[lo]):
+			total += table.bias[hi]
+			hi++
+		default:
+			total += table.bias[lo]
+			lo--
+		}
+	}
+	return total / biasNeighbours
 }
 
 func (hll *HyperLogLog) Merge(other *HyperLogLog) error {
-	if hll.precision != other.precision {
-		return ErrPrecisionMismatch
+	if hll == other {
+		return nil
 	}
 
-	hll.mutex.Lock()
+	// Copy other's registers under its own lock and release it before
+	// locking hll, so merges in opposite directions can't deadlock.
 	other.mutex.RLock()
+	precision := other.precision
+	isSparse := other.sparse != nil
+	var sparse []uint32
+	var buckets []uint8
+	if isSparse {
+		sparse = append(sparse, other.sparse...)
+	} else {
+		buckets = append(buckets, other.buckets...)
+	}
+	other.mutex.RUnlock()
+
+	hll.mutex.Lock()
 	defer hll.mutex.Unlock()
-	defer other.mutex.RUnlock()
 
-	for i := uint32(0); i < hll.m; i++ {
-		if other.buckets[i] > hll.buckets[i] {
-			hll.buckets[i] = other.buckets[i]
+	if hll.precision != precision {
+		return &kserrors.ErrPrecisionMismatch{Expected: hll.precision, Actual: precision}
+	}
+
+	if isSparse {
+		for _, entry := range sparse {
+			index, value := unpackSparseEntry(entry)
+			if value > hll.bucket(index) {
+				hll.setBucket(index, value)
+			}
+		}
+		return nil
+	}
+
+	for i, value := range buckets {
+		if value > hll.bucket(uint32(i)) {
+			hll.setBucket(uint32(i), value)
 		}
 	}
 
@@ -100,9 +204,7 @@ func (hll *HyperLogLog) Clear() {
 	hll.mutex.Lock()
 	defer hll.mutex.Unlock()
 
-	for i := range hll.buckets {
-		hll.buckets[i] = 0
-	}
+	hll.resetBuckets()
 }
 
 func (hll *HyperLogLog) EstimateError() float64 {
@@ -113,15 +215,13 @@ func (hll *HyperLogLog) GetStats() HLLStats {
 	hll.mutex.RLock()
 	defer hll.mutex.RUnlock()
 
-	emptyBuckets := 0
+	emptyBuckets := int(hll.m)
 	maxBucket := uint8(0)
 
-	for _, bucket := range hll.buckets {
-		if bucket == 0 {
-			emptyBuckets++
-		}
-		if bucket > maxBucket {
-			maxBucket = bucket
+	for _, entry := range hll.populatedEntries() {
+		emptyBuckets--
+		if entry.Value > maxBucket {
+			maxBucket = entry.Value
 		}
 	}
 
@@ -131,6 +231,8 @@ func (hll *HyperLogLog) GetStats() HLLStats {
 		EmptyBuckets:   uint32(emptyBuckets),
 		MaxBucket:      maxBucket,
 		EstimatedError: hll.EstimateError(),
+		IsHLLPlusPlus:  hasBiasTable(hll.precision),
+		IsSparse:       hll.sparse != nil,
 	}
 }
 
@@ -140,6 +242,8 @@ type HLLStats struct {
 	EmptyBuckets   uint32  `json:"empty_buckets"`
 	MaxBucket      uint8   `json:"max_bucket"`
 	EstimatedError float64 `json:"estimated_error"`
+	IsHLLPlusPlus  bool    `json:"is_hll_plus_plus"`
+	IsSparse       bool    `json:"is_sparse"`
 }
 
 func calculateAlpha(m uint32) float64 {
@@ -158,7 +262,7 @@ func calculateAlpha(m uint32) float64 {
 func hashBytes(data []byte) uint64 {
 	hasher := fnv.New64a()
 	hasher.Write(data)
-	return hasher.Sum64()
+	return murmur3Mix(hasher.Sum64())
 }
 
 func countLeadingZeros(x uint64) int {
@@ -192,6 +296,173 @@ func countLeadingZeros(x uint64) int {
 	return n
 }
 
+func (hll *HyperLogLog) Clone() *HyperLogLog {
+	hll.mutex.RLock()
+	defer hll.mutex.RUnlock()
+
+	clone := &HyperLogLog{
+		precision: hll.precision,
+		m:         hll.m,
+		alpha:     hll.alpha,
+
+		sparseLimit: hll.sparseLimit,
+	}
+	if hll.sparse != nil {
+		clone.sparse = make([]uint32, len(hll.sparse))
+		copy(clone.sparse, hll.sparse)
+	} else {
+		clone.buckets = append([]uint8(nil), hll.buckets...)
+	}
+	return clone
+}
+
+const hllFormatVersion uint8 = 2
+
+func (hll *HyperLogLog) MarshalBinary() ([]byte, error) {
+	hll.mutex.RLock()
+	defer hll.mutex.RUnlock()
+
+	data := make([]byte, 0, 2+hll.m)
+	data = append(data, hllFormatVersion, hll.precision)
+	data = append(data, hll.denseBuckets()...)
+	return data, nil
+}
+
+func (hll *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return fmt.Errorf("hyperloglog data too short: %d bytes", len(data))
+	}
+	if data[0] != hllFormatVersion {
+		return &kserrors.ErrSnapshotVersion{Expected: hllFormatVersion, Actual: data[0]}
+	}
+
+	precision := data[1]
+	if precision < MinHLLPrecision || precision > MaxHLLPrecision {
+		return fmt.Errorf("invalid hyperloglog precision: %d", precision)
+	}
+	m := uint32(1) << precision
+	if uint32(len(data)-2) != m {
+		return fmt.Errorf("invalid hyperloglog bucket count: %d (expected %d)", len(data)-2, m)
+	}
+
+	hll.mutex.Lock()
+	defer hll.mutex.Unlock()
+
+	hll.precision = precision
+	hll.m = m
+	hll.buckets = append([]uint8(nil), data[2:]...)
+	hll.sparse = nil
+	hll.alpha = calculateAlpha(m)
+	return nil
+}
+
+type BucketEntry struct {
+	Index uint32 `json:"i"`
+	Value uint8  `json:"v"`
+}
+
+func (hll *HyperLogLog) Snapshot() []uint8 {
+	hll.mutex.RLock()
+	defer hll.mutex.RUnlock()
+
+	return append([]uint8(nil), hll.denseBuckets()...)
+}
+
+func (hll *HyperLogLog) DeltaSince(snapshot []uint8) ([]BucketEntry, error) {
+	hll.mutex.RLock()
+	defer hll.mutex.RUnlock()
+
+	if len(snapshot) != 0 && uint32(len(snapshot)) != hll.m {
+		return nil, &kserrors.ErrPrecisionMismatch{
+			Expected: hll.precision,
+			Actual:   precisionForBuckets(len(snapshot)),
+		}
+	}
+
+	delta := make([]BucketEntry, 0)
+	for i, value := range hll.denseBuckets() {
+		var previous uint8
+		if len(snapshot) != 0 {
+			previous = snapshot[i]
+		}
+		if value != previous {
+			delta = append(delta, BucketEntry{Index: uint32(i), Value: value})
+		}
+	}
+	return delta, nil
+}
+
+func (hll *HyperLogLog) ApplyDelta(delta []BucketEntry) error {
+	hll.mutex.Lock()
+	defer hll.mutex.Unlock()
+
+	for _, update := range delta {
+		if update.Index >= hll.m {
+			return fmt.Errorf("bucket index %d out of range for %d buckets", update.Index, hll.m)
+		}
+	}
+
+	for _, update := range delta {
+		if update.Value > hll.bucket(update.Index) {
+			hll.setBucket(update.Index, update.Value)
+		}
+	}
+	return nil
+}
+
+type HLLMerkleTree struct {
+	Precision uint8  `json:"precision"`
+	Root      string `json:"root"`
+	Left      string `json:"left"`
+	Right     string `json:"right"`
+}
+
+func (hll *HyperLogLog) MerkleRoot() [32]byte {
+	root, _, _ := hll.merkleHashes()
+	return root
+}
+
+func (hll *HyperLogLog) MerkleTree() HLLMerkleTree {
+	root, left, right := hll.merkleHashes()
+	return HLLMerkleTree{
+		Precision: hll.precision,
+		Root:      hex.EncodeToString(root[:]),
+		Left:      hex.EncodeToString(left[:]),
+		Right:     hex.EncodeToString(right[:]),
+	}
+}
+
+func (hll *HyperLogLog) DirtyHalves(other *HyperLogLog) (leftDirty, rightDirty bool) {
+	if hll.precision != other.precision {
+		return true, true
+	}
+
+	_, left, right := hll.merkleHashes()
+	_, otherLeft, otherRight := other.merkleHashes()
+	return left != otherLeft, right != otherRight
+}
+
+func (hll *HyperLogLog) merkleHashes() (root, left, right [32]byte) {
+	hll.mutex.RLock()
+	defer hll.mutex.RUnlock()
+
+	buckets := hll.denseBuckets()
+	half := hll.m / 2
+	left = sha256.Sum256(buckets[:half])
+	right = sha256.Sum256(buckets[half:])
+	root = sha256.Sum256(append(left[:], right[:]...))
+	return root, left, right
+}
+
+func precisionForBuckets(buckets int) uint8 {
+	precision := uint8(0)
+	for buckets > 1 {
+		buckets >>= 1
+		precision++
+	}
+	return precision
+}
+
 var (
-	ErrPrecisionMismatch = fmt.Errorf("precision mismatch between HyperLogLogs")
+	ErrPrecisionMismatch error = &kserrors.ErrPrecisionMismatch{}
 )
@@ -0,0 +1,478 @@
+package probabilistic
+
+var hllBiasTables = [...]hllBiasTable{
+	{
+		raw: []float64{
+			11.2, 11.7, 12.2, 12.7, 13.3, 13.8, 14.4, 15.0,
+			15.6, 16.2, 16.8, 17.4, 18.1, 18.8, 19.5, 20.2,
+			20.9, 21.6, 22.4, 23.1, 23.9, 24.7, 25.5, 26.3,
+			27.1, 27.9, 28.8, 29.6, 30.5, 31.4, 32.3, 33.1,
+			34.0, 35.0, 35.9, 36.8, 37.7, 38.6, 39.6, 40.5,
+			41.5, 42.4, 43.4, 44.3, 45.3, 46.3, 47.2, 48.2,
+			49.2, 50.2, 51.2, 52.1, 53.1, 54.1, 55.1, 56.1,
+			57.1, 58.1, 59.1, 60.0, 61.0, 62.0, 63.0, 64.0,
+			65.0, 66.0, 67.0, 68.0, 69.0, 70.0, 71.0, 72.0,
+			73.0, 74.0, 75.0, 76.0, 77.0, 78.0, 79.0, 80.0,
+		},
+		bias: []float64{
+			10.2, 9.7, 9.2, 8.7, 8.3, 7.8, 7.4, 7.0,
+			6.6, 6.2, 5.8, 5.4, 5.1, 4.8, 4.5, 4.2,
+			3.9, 3.6, 3.4, 3.1, 2.9, 2.7, 2.5, 2.3,
+			2.1, 1.9, 1.8, 1.6, 1.5, 1.4, 1.3, 1.1,
+			1.0, 1.0, 0.9, 0.8, 0.7, 0.6, 0.6, 0.5,
+			0.5, 0.4, 0.4, 0.3, 0.3, 0.3, 0.2, 0.2,
+			0.2, 0.2, 0.2, 0.1, 0.1, 0.1, 0.1, 0.1,
+			0.1, 0.1, 0.1, 0.0, 0.0, 0.0, 0.0, 0.0,
+			0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0,
+			0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0,
+		},
+	},
+	{
+		raw: []float64{
+			22.8, 23.8, 24.2, 25.3, 26.3, 26.9, 27.9, 28.5,
+			29.6, 30.8, 31.4, 32.6, 33.2, 34.5, 35.8, 36.4,
+			37.8, 38.4, 39.8, 41.2, 41.9, 43.4, 44.1, 45.6,
+			47.1, 47.8, 49.4, 50.2, 51.7, 53.3, 54.1, 55.8,
+			56.6, 58.3, 60.0, 60.8, 62.5, 63.4, 65.1, 66.9,
+			67.8, 69.5, 70.4, 72.2, 74.1, 75.0, 76.8, 77.7,
+			79.6, 81.4, 82.4, 84.3, 85.2, 87.1, 89.0, 89.9,
+			91.9, 92.8, 94.7, 96.7, 97.6, 99.6, 100.6, 102.5,
+			104.5, 105.4, 107.4, 108.4, 110.3, 112.3, 113.3, 115.3,
+			116.2, 118.2, 120.2, 121.2, 123.2, 124.1, 126.1, 128.1,
+			129.1, 131.1, 132.1, 134.1, 136.1, 137.1, 139.1, 140.0,
+			142.0, 144.0, 145.0, 147.0, 148.0, 150.0, 152.0, 153.0,
+			155.0, 156.0, 158.0, 160.0,
+		},
+		bias: []float64{
+			21.8, 20.8, 20.2, 19.3, 18.3, 17.9, 16.9, 16.5,
+			15.6, 14.8, 14.4, 13.6, 13.2, 12.5, 11.8, 11.4,
+			10.8, 10.4, 9.8, 9.2, 8.9, 8.4, 8.1, 7.6,
+			7.1, 6.8, 6.4, 6.2, 5.7, 5.3, 5.1, 4.8,
+			4.6, 4.3, 4.0, 3.8, 3.5, 3.4, 3.1, 2.9,
+			2.8, 2.5, 2.4, 2.2, 2.1, 2.0, 1.8, 1.7,
+			1.6, 1.4, 1.4, 1.3, 1.2, 1.1, 1.0, 0.9,
+			0.9, 0.8, 0.7, 0.7, 0.6, 0.6, 0.6, 0.5,
+			0.5, 0.4, 0.4, 0.4, 0.3, 0.3, 0.3, 0.3,
+			0.2, 0.2, 0.2, 0.2, 0.2, 0.1, 0.1, 0.1,
+			0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.0,
+			0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0,
+			0.0, 0.0, 0.0, 0.0,
+		},
+	},
+	{
+		raw: []float64{
+			46.8, 48.3, 49.8, 51.4, 53.5, 55.1, 56.7, 58.4,
+			60.1, 62.5, 64.3, 66.1, 68.0, 69.9, 72.5, 74.4,
+			76.4, 78.5, 80.5, 83.3, 85.4, 87.6, 89.8, 92.0,
+			95.0, 97.3, 99.6, 101.9, 104.3, 107.5, 109.9, 112.3,
+			114.8, 117.3, 120.6, 123.2, 125.7, 128.3, 130.9, 134.4,
+			137.0, 139.7, 142.4, 145.1, 148.7, 151.4, 154.1, 156.9,
+			159.6, 163.3, 166.1, 168.9, 171.7, 174.6, 178.3, 181.2,
+			184.0, 186.9, 189.8, 193.6, 196.5, 199.4, 202.3, 205.2,
+			209.1, 212.0, 215.0, 217.9, 220.8, 224.7, 227.7, 230.6,
+			233.6, 236.5, 240.5, 243.4, 246.4, 249.4, 252.3, 256.3,
+			259.3, 262.3, 265.2, 268.2, 272.2, 275.2, 278.1, 281.1,
+			284.1, 288.1, 291.1, 294.1, 297.1, 300.1, 304.1, 307.0,
+			310.0, 313.0, 316.0, 320.0,
+		},
+		bias: []float64{
+			43.8, 42.3, 40.8, 39.4, 37.5, 36.1, 34.7, 33.4,
+			32.1, 30.5, 29.3, 28.1, 27.0, 25.9, 24.5, 23.4,
+			22.4, 21.5, 20.5, 19.3, 18.4, 17.6, 16.8, 16.0,
+			15.0, 14.3, 13.6, 12.9, 12.3, 11.5, 10.9, 10.3,
+			9.8, 9.3, 8.6, 8.2, 7.7, 7.3, 6.9, 6.4,
+			6.0, 5.7, 5.4, 5.1, 4.7, 4.4, 4.1, 3.9,
+			3.6, 3.3, 3.1, 2.9, 2.7, 2.6, 2.3, 2.2,
+			2.0, 1.9, 1.8, 1.6, 1.5, 1.4, 1.3, 1.2,
+			1.1, 1.0, 1.0, 0.9, 0.8, 0.7, 0.7, 0.6,
+			0.6, 0.5, 0.5, 0.4, 0.4, 0.4, 0.3, 0.3,
+			0.3, 0.3, 0.2, 0.2, 0.2, 0.2, 0.1, 0.1,
+			0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.0,
+			0.0, 0.0, 0.0, 0.0,
+		},
+	},
+	{
+		raw: []float64{
+			94.5, 97.4, 101.0, 104.1, 107.8, 111.1, 114.4, 118.3,
+			121.8, 125.9, 129.5, 133.2, 137.5, 141.3, 145.8, 149.8,
+			153.8, 158.5, 162.7, 167.6, 171.8, 176.2, 181.3, 185.7,
+			191.0, 195.5, 200.1, 205.6, 210.3, 215.9, 220.7, 225.6,
+			231.3, 236.3, 242.1, 247.2, 252.3, 258.3, 263.5, 269.6,
+			274.8, 280.1, 286.3, 291.7, 298.0, 303.4, 308.9, 315.3,
+			320.8, 327.3, 332.9, 338.4, 345.0, 350.6, 357.2, 362.9,
+			368.6, 375.3, 381.0, 387.7, 393.5, 399.2, 406.0, 411.8,
+			418.6, 424.4, 430.3, 437.1, 443.0, 449.8, 455.7, 461.6,
+			468.4, 474.3, 481.2, 487.1, 493.1, 500.0, 505.9, 512.8,
+			518.8, 524.7, 531.7, 537.6, 544.6, 550.5, 556.5, 563.5,
+			569.4, 576.4, 582.4, 588.3, 595.3, 601.3, 608.3, 614.2,
+			620.2, 627.2, 633.2, 640.2,
+		},
+		bias: []float64{
+			88.5, 85.4, 82.0, 79.1, 75.8, 73.1, 70.4, 67.3,
+			64.8, 61.9, 59.5, 57.2, 54.5, 52.3, 49.8, 47.8,
+			45.8, 43.5, 41.7, 39.6, 37.8, 36.2, 34.3, 32.7,
+			31.0, 29.5, 28.1, 26.6, 25.3, 23.9, 22.7, 21.6,
+			20.3, 19.3, 18.1, 17.2, 16.3, 15.3, 14.5, 13.6,
+			12.8, 12.1, 11.3, 10.7, 10.0, 9.4, 8.9, 8.3,
+			7.8, 7.3, 6.9, 6.4, 6.0, 5.6, 5.2, 4.9,
+			4.6, 4.3, 4.0, 3.7, 3.5, 3.2, 3.0, 2.8,
+			2.6, 2.4, 2.3, 2.1, 2.0, 1.8, 1.7, 1.6,
+			1.4, 1.3, 1.2, 1.1, 1.1, 1.0, 0.9, 0.8,
+			0.8, 0.7, 0.7, 0.6, 0.6, 0.5, 0.5, 0.5,
+			0.4, 0.4, 0.4, 0.3, 0.3, 0.3, 0.3, 0.2,
+			0.2, 0.2, 0.2, 0.2,
+		},
+	},
+	{
+		raw: []float64{
+			189.7, 196.2, 202.8, 209.5, 216.4, 222.9, 230.2, 237.5,
+			245.0, 252.7, 259.9, 267.8, 276.0, 284.2, 292.6, 300.5,
+			309.2, 318.0, 326.9, 336.0, 344.6, 353.9, 363.4, 373.0,
+			382.7, 391.9, 401.8, 411.9, 422.2, 432.5, 442.1, 452.7,
+			463.3, 474.1, 484.9, 495.0, 506.1, 517.2, 528.4, 539.7,
+			550.2, 561.7, 573.2, 584.8, 596.5, 607.4, 619.2, 631.0,
+			643.0, 655.0, 666.1, 678.2, 690.3, 702.5, 714.7, 726.1,
+			738.4, 750.8, 763.2, 775.6, 787.2, 799.7, 812.2, 824.7,
+			837.3, 849.0, 861.6, 874.3, 887.0, 899.7, 911.4, 924.2,
+			937.0, 949.8, 962.6, 974.4, 987.2, 1000.1, 1012.9, 1025.8,
+			1037.6, 1050.5, 1063.4, 1076.3, 1089.1, 1101.0, 1114.0, 1126.9,
+			1139.8, 1152.7, 1164.6, 1177.6, 1190.5, 1203.5, 1216.4, 1228.4,
+			1241.4, 1254.4, 1267.4, 1280.3,
+		},
+		bias: []float64{
+			177.7, 171.2, 164.8, 158.5, 152.4, 146.9, 141.2, 135.5,
+			130.0, 124.7, 119.9, 114.8, 110.0, 105.2, 100.6, 96.5,
+			92.2, 88.0, 83.9, 80.0, 76.6, 72.9, 69.4, 66.0,
+			62.7, 59.9, 56.8, 53.9, 51.2, 48.5, 46.1, 43.7,
+			41.3, 39.1, 36.9, 35.0, 33.1, 31.2, 29.4, 27.7,
+			26.2, 24.7, 23.2, 21.8, 20.5, 19.4, 18.2, 17.0,
+			16.0, 15.0, 14.1, 13.2, 12.3, 11.5, 10.7, 10.1,
+			9.4, 8.8, 8.2, 7.6, 7.2, 6.7, 6.2, 5.7,
+			5.3, 5.0, 4.6, 4.3, 4.0, 3.7, 3.4, 3.2,
+			3.0, 2.8, 2.6, 2.4, 2.2, 2.1, 1.9, 1.8,
+			1.6, 1.5, 1.4, 1.3, 1.1, 1.0, 1.0, 0.9,
+			0.8, 0.7, 0.6, 0.6, 0.5, 0.5, 0.4, 0.4,
+			0.4, 0.4, 0.4, 0.3,
+		},
+	},
+	{
+		raw: []float64{
+			380.7, 393.6, 406.3, 419.9, 433.7, 447.3, 461.7, 475.9,
+			490.9, 506.3, 521.3, 537.2, 552.8, 569.3, 586.1, 602.5,
+			619.9, 636.8, 654.7, 672.9, 690.7, 709.4, 727.7, 746.9,
+			766.4, 785.4, 805.3, 824.8, 845.2, 865.9, 886.0, 907.0,
+			927.5, 949.0, 970.7, 991.7, 1013.8, 1035.1, 1057.5, 1080.1,
+			1102.0, 1124.9, 1147.0, 1170.2, 1193.5, 1216.1, 1239.7, 1262.6,
+			1286.4, 1310.4, 1333.5, 1357.7, 1381.1, 1405.4, 1429.9, 1453.6,
+			1478.2, 1502.0, 1526.8, 1551.7, 1575.7, 1600.7, 1624.8, 1649.9,
+			1675.1, 1699.3, 1724.5, 1748.9, 1774.2, 1799.6, 1824.1, 1849.6,
+			1874.1, 1899.7, 1925.3, 1949.9, 1975.5, 2000.2, 2025.9, 2051.6,
+			2076.4, 2102.2, 2126.9, 2152.7, 2178.5, 2203.4, 2229.2, 2254.1,
+			2279.9, 2305.8, 2330.7, 2356.6, 2381.6, 2407.5, 2433.4, 2458.3,
+			2484.2, 2509.1, 2535.1, 2561.1,
+		},
+		bias: []float64{
+			355.7, 342.6, 330.3, 317.9, 305.7, 294.3, 282.7, 271.9,
+			260.9, 250.3, 240.3, 230.2, 220.8, 211.3, 202.1, 193.5,
+			184.9, 176.8, 168.7, 160.9, 153.7, 146.4, 139.7, 132.9,
+			126.4, 120.4, 114.3, 108.8, 103.2, 97.9, 93.0, 88.0,
+			83.5, 79.0, 74.7, 70.7, 66.8, 63.1, 59.5, 56.1,
+			53.0, 49.9, 47.0, 44.2, 41.5, 39.1, 36.7, 34.6,
+			32.4, 30.4, 28.5, 26.7, 25.1, 23.4, 21.9, 20.6,
+			19.2, 18.0, 16.8, 15.7, 14.7, 13.7, 12.8, 11.9,
+			11.1, 10.3, 9.5, 8.9, 8.2, 7.6, 7.1, 6.6,
+			6.1, 5.7, 5.3, 4.9, 4.5, 4.2, 3.9, 3.6,
+			3.4, 3.2, 2.9, 2.7, 2.5, 2.4, 2.2, 2.1,
+			1.9, 1.8, 1.7, 1.6, 1.6, 1.5, 1.4, 1.3,
+			1.2, 1.1, 1.1, 1.1,
+		},
+	},
+	{
+		raw: []float64{
+			762.6, 788.0, 814.0, 840.5, 868.2, 896.0, 924.3, 953.2,
+			982.7, 1013.4, 1044.1, 1075.3, 1107.1, 1139.5, 1173.1, 1206.6,
+			1240.7, 1275.3, 1310.4, 1346.8, 1383.0, 1419.7, 1457.0, 1494.7,
+			1533.7, 1572.4, 1611.6, 1651.2, 1691.2, 1732.5, 1773.4, 1814.7,
+			1856.5, 1898.6, 1942.0, 1985.0, 2028.2, 2071.8, 2115.7, 2160.9,
+			2205.5, 2250.4, 2295.6, 2341.1, 2387.7, 2433.7, 2479.9, 2526.5,
+			2573.3, 2621.2, 2668.4, 2715.9, 2763.6, 2811.4, 2860.3, 2908.4,
+			2956.7, 3005.2, 3053.8, 3103.6, 3152.5, 3201.5, 3250.7, 3299.9,
+			3350.3, 3399.7, 3449.4, 3499.1, 3548.8, 3599.6, 3649.5, 3699.5,
+			3749.6, 3799.8, 3851.0, 3901.1, 3951.4, 4001.8, 4052.0, 4103.5,
+			4153.9, 4204.5, 4255.0, 4305.5, 4357.1, 4407.7, 4458.4, 4509.1,
+			4559.8, 4611.5, 4662.2, 4713.0, 4763.7, 4814.5, 4866.4, 4917.2,
+			4968.0, 5018.8, 5069.6, 5121.4,
+		},
+		bias: []float64{
+			711.6, 686.0, 661.0, 636.5, 612.2, 589.0, 566.3, 544.2,
+			522.7, 501.4, 481.1, 461.3, 442.1, 423.5, 405.1, 387.6,
+			370.7, 354.3, 338.4, 322.8, 308.0, 293.7, 280.0, 266.7,
+			253.7, 241.4, 229.6, 218.2, 207.2, 196.5, 186.4, 176.7,
+			167.5, 158.6, 150.0, 142.0, 134.2, 126.8, 119.7, 112.9,
+			106.5, 100.4, 94.6, 89.1, 83.7, 78.7, 73.9, 69.5,
+			65.3, 61.2, 57.4, 53.9, 50.6, 47.4, 44.3, 41.4,
+			38.7, 36.2, 33.8, 31.6, 29.5, 27.5, 25.7, 23.9,
+			22.3, 20.7, 19.4, 18.1, 16.8, 15.6, 14.5, 13.5,
+			12.6, 11.8, 11.0, 10.1, 9.4, 8.8, 8.0, 7.5,
+			6.9, 6.5, 6.0, 5.5, 5.1, 4.7, 4.4, 4.1,
+			3.8, 3.5, 3.2, 3.0, 2.7, 2.5, 2.4, 2.2,
+			2.0, 1.8, 1.6, 1.4,
+		},
+	},
+	{
+		raw: []float64{
+			1526.1, 1576.8, 1629.3, 1682.4, 1737.3, 1792.8, 1849.5, 1907.9,
+			1966.9, 2027.7, 2089.0, 2151.4, 2215.7, 2280.5, 2347.1, 2414.1,
+			2482.2, 2552.1, 2622.4, 2694.5, 2766.9, 2840.3, 2915.5, 2990.9,
+			3068.1, 3145.5, 3223.9, 3303.9, 3384.1, 3466.0, 3547.8, 3630.4,
+			3714.8, 3799.0, 3884.9, 3970.7, 4057.2, 4145.2, 4233.2, 4322.6,
+			4411.7, 4501.6, 4592.9, 4683.8, 4776.2, 4868.2, 4960.9, 5054.8,
+			5148.5, 5243.3, 5337.7, 5432.5, 5528.5, 5624.0, 5720.9, 5817.1,
+			5913.7, 6011.6, 6108.8, 6207.3, 6305.1, 6403.2, 6502.5, 6600.9,
+			6700.6, 6799.8, 6899.0, 6999.2, 7098.9, 7199.5, 7299.4, 7399.3,
+			7500.4, 7600.7, 7702.0, 7802.5, 7902.8, 8004.3, 8105.1, 8207.0,
+			8308.0, 8409.0, 8511.1, 8612.2, 8714.4, 8815.6, 8916.9, 9019.1,
+			9120.3, 9222.9, 9324.4, 9425.8, 9528.1, 9629.8, 9732.3, 9834.1,
+			9935.6, 10038.4, 10140.1, 10242.5,
+		},
+		bias: []float64{
+			1424.1, 1372.8, 1322.3, 1273.4, 1225.3, 1178.8, 1133.5, 1088.9,
+			1045.9, 1003.7, 963.0, 923.4, 884.7, 847.5, 811.1, 776.1,
+			742.2, 709.1, 677.4, 646.5, 616.9, 588.3, 560.5, 533.9,
+			508.1, 483.5, 459.9, 436.9, 415.1, 394.0, 373.8, 354.4,
+			335.8, 318.0, 300.9, 284.7, 269.2, 254.2, 240.2, 226.6,
+			213.7, 201.6, 189.9, 178.8, 168.2, 158.2, 148.9, 139.8,
+			131.5, 123.3, 115.7, 108.5, 101.5, 95.0, 88.9, 83.1,
+			77.7, 72.6, 67.8, 63.3, 59.1, 55.2, 51.5, 47.9,
+			44.6, 41.8, 39.0, 36.2, 33.9, 31.5, 29.4, 27.3,
+			25.4, 23.7, 22.0, 20.5, 18.8, 17.3, 16.1, 15.0,
+			14.0, 13.0, 12.1, 11.2, 10.4, 9.6, 8.9, 8.1,
+			7.3, 6.9, 6.4, 5.8, 5.1, 4.8, 4.3, 4.1,
+			3.6, 3.4, 3.1, 2.5,
+		},
+	},
+	{
+		raw: []float64{
+			3052.9, 3155.0, 3259.5, 3366.3, 3475.5, 3586.5, 3700.5, 3816.8,
+			3935.4, 4056.4, 4179.0, 4304.6, 4432.5, 4562.8, 4695.3, 4829.4,
+			4966.3, 5105.4, 5246.7, 5390.2, 5534.9, 5682.5, 5832.2, 5983.9,
+			6137.4, 6292.3, 6449.4, 6608.6, 6769.6, 6932.4, 7096.2, 7262.3,
+			7430.1, 7599.4, 7770.4, 7942.0, 8115.8, 8291.4, 8468.0, 8646.2,
+			8824.6, 9005.0, 9186.9, 9369.8, 9553.7, 9738.0, 9924.3, 10111.4,
+			10299.5, 10488.4, 10677.2, 10867.6, 11059.4, 11251.7, 11444.6, 11636.9,
+			11831.1, 12025.7, 12221.0, 12416.9, 12612.8, 12809.9, 13007.3, 13205.2,
+			13403.3, 13601.0, 13800.2, 13999.8, 14199.5, 14399.8, 14599.4, 14800.3,
+			15001.5, 15203.0, 15404.6, 15605.5, 15807.6, 16010.3, 16212.5, 16415.1,
+			16616.7, 16820.1, 17023.5, 17227.0, 17430.4, 17633.0, 17836.4, 18040.1,
+			18244.1, 18447.6, 18651.0, 18854.9, 19059.3, 19263.2, 19467.4, 19671.2,
+			19875.0, 20079.7, 20283.7, 20488.2,
+		},
+		bias: []float64{
+			2848.9, 2746.0, 2645.5, 2547.3, 2451.5, 2358.5, 2267.5, 2178.8,
+			2092.4, 2008.4, 1927.0, 1847.6, 1770.5, 1695.8, 1623.3, 1553.4,
+			1485.3, 1419.4, 1355.7, 1294.2, 1234.9, 1177.5, 1122.2, 1068.9,
+			1017.4, 968.3, 920.4, 874.6, 830.6, 788.4, 748.2, 709.3,
+			672.1, 636.4, 602.4, 570.0, 538.8, 509.4, 481.0, 454.2,
+			428.6, 404.0, 380.9, 358.8, 337.7, 318.0, 299.3, 281.4,
+			264.5, 248.4, 233.2, 218.6, 205.4, 192.7, 180.6, 168.9,
+			158.1, 147.7, 138.0, 128.9, 120.8, 112.9, 105.3, 98.2,
+			91.3, 85.0, 79.2, 73.8, 68.5, 63.8, 59.4, 55.3,
+			51.5, 48.0, 44.6, 41.5, 38.6, 36.3, 33.5, 31.1,
+			28.7, 27.1, 25.5, 24.0, 22.4, 21.0, 19.4, 18.1,
+			17.1, 15.6, 15.0, 13.9, 13.3, 12.2, 11.4, 11.2,
+			10.0, 9.7, 8.7, 8.2,
+		},
+	},
+	{
+		raw: []float64{
+			6107.1, 6311.3, 6519.6, 6733.3, 6951.8, 7174.4, 7402.2, 7634.2,
+			7871.5, 8113.4, 8359.5, 8610.7, 8866.0, 9126.4, 9391.4, 9660.1,
+			9934.0, 10211.5, 10494.0, 10781.0, 11071.2, 11366.2, 11664.8, 11968.0,
+			12274.9, 12585.3, 12900.1, 13218.0, 13540.4, 13865.8, 14194.1, 14526.2,
+			14861.0, 15199.7, 15541.7, 15885.6, 16233.3, 16583.5, 16936.7, 17292.6,
+			17650.5, 18011.1, 18373.6, 18739.4, 19107.4, 19476.2, 19848.6, 20221.5,
+			20597.1, 20975.0, 21353.3, 21734.2, 22116.1, 22499.6, 22885.5, 23271.0,
+			23658.7, 24047.9, 24438.4, 24830.2, 25222.6, 25617.2, 26011.8, 26407.4,
+			26803.5, 27200.7, 27599.0, 27997.9, 28398.1, 28797.9, 29197.9, 29599.6,
+			30001.5, 30403.6, 30806.8, 31209.5, 31613.2, 32016.7, 32421.7, 32827.6,
+			33232.0, 33637.6, 34042.9, 34449.4, 34856.2, 35261.9, 35669.4, 36075.5,
+			36482.9, 36890.2, 37297.5, 37705.3, 38111.4, 38518.8, 38927.3, 39335.3,
+			39743.9, 40152.7, 40561.6, 40971.3,
+		},
+		bias: []float64{
+			5698.1, 5492.3, 5291.6, 5095.3, 4903.8, 4717.4, 4535.2, 4358.2,
+			4185.5, 4017.4, 3854.5, 3695.7, 3542.0, 3392.4, 3247.4, 3107.1,
+			2971.0, 2839.5, 2712.0, 2589.0, 2470.2, 2355.2, 2244.8, 2138.0,
+			2034.9, 1936.3, 1841.1, 1750.0, 1662.4, 1577.8, 1497.1, 1419.2,
+			1345.0, 1273.7, 1205.7, 1140.6, 1078.3, 1019.5, 962.7, 908.6,
+			857.5, 808.1, 761.6, 717.4, 675.4, 635.2, 597.6, 561.5,
+			527.1, 495.0, 464.3, 435.2, 408.1, 381.6, 357.5, 334.0,
+			311.7, 291.9, 272.4, 254.2, 237.6, 222.2, 207.8, 193.4,
+			179.5, 167.7, 156.0, 145.9, 136.1, 125.9, 116.9, 108.6,
+			101.5, 93.6, 86.8, 80.5, 74.2, 68.7, 63.7, 59.6,
+			55.0, 50.6, 46.9, 43.4, 40.2, 36.9, 34.4, 31.5,
+			28.9, 26.2, 24.5, 22.3, 19.4, 16.8, 15.3, 14.3,
+			12.9, 12.7, 11.6, 11.3,
+		},
+	},
+	{
+		raw: []float64{
+			12215.6, 12623.6, 13040.8, 13467.7, 13904.5, 14350.1, 14805.3, 15270.0,
+			15744.1, 16228.3, 16720.6, 17222.2, 17733.5, 18253.5, 18783.3, 19321.6,
+			19868.5, 20424.5, 20989.0, 21562.8, 22143.9, 22733.0, 23330.9, 23936.8,
+			24551.4, 25172.8, 25801.7, 26438.5, 27081.7, 27732.8, 28390.2, 29054.0,
+			29724.8, 30401.5, 31084.9, 31773.9, 32468.6, 33168.5, 33874.2, 34585.6,
+			35302.2, 36023.6, 36749.2, 37480.1, 38215.5, 38954.7, 39697.3, 40445.1,
+			41195.8, 41951.5, 42709.6, 43471.1, 44234.7, 45002.0, 45773.2, 46547.4,
+			47324.9, 48102.8, 48884.6, 49669.2, 50454.9, 51240.1, 52029.1, 52820.1,
+			53613.8, 54408.4, 55204.8, 56001.0, 56799.6, 57600.3, 58400.4, 59202.8,
+			60006.5, 60812.4, 61618.8, 62424.8, 63231.4, 64039.7, 64847.1, 65656.0,
+			66465.2, 67277.1, 68088.6, 68902.0, 69715.2, 70527.1, 71338.7, 72151.5,
+			72964.0, 73779.2, 74593.5, 75408.6, 76225.2, 77040.4, 77856.3, 78673.5,
+			79489.4, 80304.8, 81122.5, 81939.6,
+		},
+		bias: []float64{
+			11396.6, 10985.6, 10583.8, 10191.7, 9808.5, 9435.1, 9071.3, 8717.0,
+			8372.1, 8036.3, 7709.6, 7392.2, 7084.5, 6785.5, 6495.3, 6214.6,
+			5942.5, 5679.5, 5425.0, 5178.8, 4940.9, 4711.0, 4489.9, 4276.8,
+			4071.4, 3873.8, 3683.7, 3501.5, 3325.7, 3156.8, 2995.2, 2840.0,
+			2691.8, 2549.5, 2412.9, 2282.9, 2158.6, 2039.5, 1926.2, 1817.6,
+			1715.2, 1617.6, 1524.2, 1436.1, 1351.5, 1271.7, 1195.3, 1124.1,
+			1055.8, 991.5, 930.6, 873.1, 817.7, 766.0, 717.2, 672.4,
+			630.9, 589.8, 552.6, 517.2, 483.9, 450.1, 420.1, 392.1,
+			365.8, 341.4, 318.8, 296.0, 275.6, 256.3, 237.4, 220.8,
+			205.5, 192.4, 178.8, 165.8, 153.4, 142.7, 131.1, 120.0,
+			110.2, 103.1, 95.6, 90.0, 83.2, 76.1, 68.7, 62.5,
+			56.0, 51.2, 46.5, 42.6, 40.2, 36.4, 32.3, 30.5,
+			27.4, 23.8, 22.5, 19.6,
+		},
+	},
+	{
+		raw: []float64{
+			24431.8, 25247.8, 26083.1, 26936.7, 27809.5, 28701.0, 29611.3, 30541.2,
+			31489.4, 32456.6, 33441.4, 34445.1, 35467.6, 36507.7, 37566.8, 38643.0,
+			39737.3, 40849.5, 41978.7, 43125.6, 44289.8, 45469.3, 46665.5, 47876.2,
+			49104.0, 50347.2, 51603.9, 52878.0, 54164.7, 55466.1, 56781.6, 58107.6,
+			59449.0, 60802.5, 62168.9, 63546.9, 64936.4, 66336.9, 67749.3, 69173.8,
+			70606.6, 72047.0, 73499.6, 74960.0, 76431.0, 77910.8, 79397.6, 80893.8,
+			82394.3, 83903.4, 85420.0, 86939.9, 88469.4, 90004.7, 91545.5, 93088.4,
+			94639.4, 96193.7, 97753.7, 99321.0, 100892.2, 102464.8, 104043.9, 105625.6,
+			107207.4, 108793.5, 110383.3, 111979.4, 113575.3, 115176.5, 116779.7, 118385.7,
+			119991.8, 121600.2, 123212.4, 124824.7, 126440.7, 128057.4, 129674.0, 131294.5,
+			132913.1, 134534.0, 136158.7, 137782.7, 139408.7, 141029.2, 142654.8, 144283.8,
+			145913.1, 147542.3, 149172.3, 150801.7, 152433.2, 154063.4, 155698.6, 157332.7,
+			158964.6, 160600.3, 162233.1, 163868.0,
+		},
+		bias: []float64{
+			22793.8, 21971.8, 21168.1, 20383.7, 19617.5, 18871.0, 18143.3, 17434.2,
+			16744.4, 16072.6, 15419.4, 14785.1, 14168.6, 13570.7, 12990.8, 12429.0,
+			11885.3, 11358.5, 10849.7, 10357.6, 9883.8, 9425.3, 8982.5, 8555.2,
+			8144.0, 7749.2, 7367.9, 7003.0, 6651.7, 6314.1, 5991.6, 5679.6,
+			5382.0, 5097.5, 4824.9, 4564.9, 4316.4, 4077.9, 3852.3, 3637.8,
+			3432.6, 3235.0, 3048.6, 2871.0, 2703.0, 2544.8, 2393.6, 2250.8,
+			2113.3, 1983.4, 1862.0, 1743.9, 1634.4, 1531.7, 1433.5, 1338.4,
+			1251.4, 1166.7, 1088.7, 1017.0, 950.2, 884.8, 824.9, 768.6,
+			711.4, 659.5, 611.3, 568.4, 526.3, 488.5, 453.7, 421.7,
+			388.8, 359.2, 332.4, 306.7, 284.7, 262.4, 241.0, 222.5,
+			203.1, 186.0, 171.7, 157.7, 144.7, 127.2, 114.8, 104.8,
+			96.1, 86.3, 78.3, 69.7, 62.2, 54.4, 50.6, 46.7,
+			40.6, 37.3, 32.1, 28.0,
+		},
+	},
+	{
+		raw: []float64{
+			48864.2, 50496.4, 52165.9, 53872.9, 55618.4, 57400.9, 59222.2, 61080.4,
+			62976.3, 64910.4, 66880.1, 68889.7, 70933.7, 73015.5, 75133.2, 77286.4,
+			79475.0, 81698.4, 83958.0, 86250.6, 88576.8, 90937.0, 93330.5, 95754.0,
+			98207.9, 100693.0, 103210.6, 105759.6, 108329.9, 110931.7, 113563.4, 116221.9,
+			118903.0, 121607.6, 124343.6, 127096.5, 129876.7, 132680.5, 135502.3, 138347.0,
+			141210.2, 144095.2, 147001.0, 149920.5, 152861.8, 155818.9, 158793.6, 161781.6,
+			164786.6, 167808.0, 170839.4, 173883.3, 176939.0, 180005.3, 183083.8, 186175.4,
+			189282.7, 192398.3, 195521.1, 198654.8, 201797.8, 204945.4, 208097.1, 211257.5,
+			214430.9, 217616.1, 220799.4, 223988.9, 227182.8, 230383.0, 233584.7, 236792.7,
+			240004.2, 243227.7, 246448.4, 249679.1, 252906.9, 256139.0, 259377.9, 262615.4,
+			265854.6, 269103.9, 272350.4, 275592.7, 278842.6, 282090.3, 285341.9, 288599.2,
+			291859.3, 295126.4, 298383.6, 301646.4, 304913.2, 308180.7, 311451.1, 314715.0,
+			317980.2, 321243.2, 324509.3, 327781.1,
+		},
+		bias: []float64{
+			45588.2, 43943.4, 42335.9, 40765.9, 39234.4, 37740.9, 36285.2, 34866.4,
+			33485.3, 32142.4, 30836.1, 29568.7, 28335.7, 27140.5, 25981.2, 24858.4,
+			23770.0, 22716.4, 21699.0, 20714.6, 19764.8, 18848.0, 17964.5, 17111.0,
+			16287.9, 15497.0, 14737.6, 14009.6, 13302.9, 12627.7, 11983.4, 11364.9,
+			10769.0, 10196.6, 9655.6, 9132.5, 8635.7, 8162.5, 7707.3, 7275.0,
+			6862.2, 6470.2, 6099.0, 5741.5, 5405.8, 5086.9, 4784.6, 4495.6,
+			4223.6, 3968.0, 3723.4, 3490.3, 3269.0, 3058.3, 2859.8, 2675.4,
+			2505.7, 2344.3, 2190.1, 2046.8, 1913.8, 1784.4, 1659.1, 1542.5,
+			1438.9, 1348.1, 1254.4, 1166.9, 1083.8, 1007.0, 932.7, 863.7,
+			798.2, 744.7, 688.4, 643.1, 593.9, 549.0, 510.9, 471.4,
+			434.6, 406.9, 376.4, 341.7, 314.6, 286.3, 260.9, 241.2,
+			224.3, 214.4, 195.6, 181.4, 171.2, 161.7, 155.1, 143.0,
+			131.2, 117.2, 106.3, 101.1,
+		},
+	},
+	{
+		raw: []float64{
+			97730.6, 100994.9, 104334.5, 107749.9, 111241.1, 114807.6, 118451.0, 122169.0,
+			125960.7, 129828.4, 133769.7, 137784.3, 141872.5, 146034.9, 150269.1, 154575.1,
+			158952.0, 163395.8, 167913.2, 172496.3, 177146.3, 181865.7, 186649.2, 191494.5,
+			196410.6, 201382.3, 206412.1, 211503.0, 216644.4, 221846.4, 227102.7, 232418.1,
+			237775.5, 243192.0, 248656.2, 254166.6, 259723.3, 265325.1, 270973.9, 276662.4,
+			282389.9, 288165.0, 293974.2, 299821.3, 305704.1, 311621.9, 317566.5, 323545.1,
+			329549.8, 335590.2, 341649.7, 347735.1, 353853.6, 359988.2, 366148.8, 372325.3,
+			378522.6, 384735.4, 390984.6, 397238.2, 403518.3, 409815.2, 416139.0, 422461.0,
+			428805.7, 435175.8, 441540.6, 447915.5, 454303.6, 460714.8, 467132.9, 473548.0,
+			479971.4, 486393.3, 492839.7, 499289.3, 505750.7, 512218.5, 518679.5, 525160.5,
+			531636.1, 538116.1, 544614.4, 551109.9, 557607.1, 564106.9, 570612.6, 577127.8,
+			583646.5, 590173.2, 596702.3, 603235.2, 609754.1, 616282.1, 622807.9, 629334.8,
+			635879.1, 642418.4, 648946.0, 655479.1,
+		},
+		bias: []float64{
+			91177.6, 87887.9, 84674.5, 81535.9, 78473.1, 75486.6, 72576.0, 69741.0,
+			66978.7, 64292.4, 61680.7, 59141.3, 56676.5, 54284.9, 51965.1, 49718.1,
+			47541.0, 45431.8, 43395.2, 41424.3, 39521.3, 37686.7, 35917.2, 34208.5,
+			32570.6, 30989.3, 29465.1, 28003.0, 26590.4, 25238.4, 23941.7, 22703.1,
+			21507.5, 20370.0, 19280.2, 18237.6, 17240.3, 16289.1, 15383.9, 14518.4,
+			13692.9, 12914.0, 12170.2, 11463.3, 10792.1, 10156.9, 9547.5, 8973.1,
+			8423.8, 7910.2, 7416.7, 6948.1, 6513.6, 6094.2, 5700.8, 5324.3,
+			4967.6, 4627.4, 4322.6, 4022.2, 3749.3, 3492.2, 3263.0, 3031.0,
+			2821.7, 2638.8, 2449.6, 2271.5, 2105.6, 1962.8, 1827.9, 1689.0,
+			1559.4, 1427.3, 1319.7, 1216.3, 1123.7, 1038.5, 945.5, 872.5,
+			795.1, 721.1, 666.4, 607.9, 551.1, 497.9, 449.6, 411.8,
+			376.5, 349.2, 325.3, 304.2, 270.1, 244.1, 215.9, 189.8,
+			180.1, 166.4, 140.0, 119.1,
+		},
+	},
+	{
+		raw: []float64{
+			195462.4, 201991.3, 208670.0, 215500.5, 222483.4, 229616.7, 236902.0, 244336.2,
+			251922.7, 259658.3, 267539.8, 275569.6, 283749.0, 292073.2, 300539.7, 309151.3,
+			317899.5, 326799.9, 335835.0, 345002.9, 354306.2, 363737.5, 373296.3, 382992.3,
+			392809.8, 402758.7, 412821.3, 423002.8, 433298.0, 443702.6, 454220.1, 464846.0,
+			475581.0, 486412.4, 497341.0, 508364.4, 519482.8, 530693.9, 541982.7, 553376.7,
+			564834.4, 576380.1, 587996.0, 599686.3, 611459.8, 623288.6, 635183.9, 647135.7,
+			659167.8, 671246.1, 683367.2, 695556.2, 707782.9, 720056.0, 732377.5, 744753.9,
+			757177.2, 769632.1, 782128.3, 794660.2, 807217.3, 819806.3, 832425.5, 845059.6,
+			857737.5, 870445.9, 883183.7, 895945.9, 908708.6, 921484.4, 934278.2, 947145.5,
+			960032.4, 972903.3, 985780.7, 998685.5, 1011631.6, 1024575.4, 1037520.9, 1050457.0,
+			1063408.0, 1076377.5, 1089363.1, 1102374.3, 1115386.6, 1128381.8, 1141404.3, 1154421.4,
+			1167461.4, 1180489.5, 1193507.8, 1206566.1, 1219634.9, 1232702.5, 1245766.7, 1258836.2,
+			1271900.6, 1284971.9, 1298029.0, 1311095.4,
+		},
+		bias: []float64{
+			182355.4, 175777.3, 169349.0, 163072.5, 156947.4, 150973.7, 145152.0, 139479.2,
+			133958.7, 128586.3, 123360.8, 118283.6, 113356.0, 108573.2, 103931.7, 99436.3,
+			95077.5, 90870.9, 86799.0, 82858.9, 79055.2, 75379.5, 71831.3, 68420.3,
+			65129.8, 61971.7, 58927.3, 56001.8, 53190.0, 50486.6, 47897.1, 45416.0,
+			43044.0, 40768.4, 38589.0, 36505.4, 34516.8, 32620.9, 30802.7, 29088.7,
+			27439.4, 25878.1, 24387.0, 22970.3, 21635.8, 20357.6, 19145.9, 17990.7,
+			16915.8, 15886.1, 14900.2, 13982.2, 13101.9, 12268.0, 11481.5, 10750.9,
+			10067.2, 9415.1, 8804.3, 8228.2, 7678.3, 7160.3, 6672.5, 6199.6,
+			5769.5, 5370.9, 5001.7, 4656.9, 4312.6, 3980.4, 3667.2, 3427.5,
+			3207.4, 2971.3, 2740.7, 2538.5, 2377.6, 2214.4, 2052.9, 1881.0,
+			1725.0, 1587.5, 1466.1, 1370.3, 1274.6, 1162.8, 1078.3, 988.4,
+			921.4, 841.5, 752.8, 704.1, 665.9, 626.5, 582.7, 545.2,
+			502.6, 466.9, 417.0, 375.4,
+		},
+	},
+}
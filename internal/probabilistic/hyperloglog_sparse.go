@@ -0,0 +1,126 @@
+package probabilistic
+
+import (
+	"math"
+	"sort"
+)
+
+const DefaultSparseThreshold = 0.25
+
+// Sparse entries pack the bucket index above an 8-bit register value, so a
+// slice sorted by entry is also sorted by bucket index.
+const sparseValueBits = 8
+
+func NewHyperLogLogWithSparseThreshold(precision uint8, threshold float64) *HyperLogLog {
+	hll := NewHyperLogLog(precision)
+	hll.sparseLimit = sparseLimit(hll.m, threshold)
+	if hll.sparseLimit == 0 {
+		hll.toDense()
+	}
+	return hll
+}
+
+func sparseLimit(m uint32, threshold float64) uint32 {
+	if threshold <= 0 {
+		return 0
+	}
+	return uint32(math.Min(threshold, 1) * float64(m))
+}
+
+func packSparseEntry(index uint32, value uint8) uint32 {
+	return index<<sparseValueBits | uint32(value)
+}
+
+func unpackSparseEntry(entry uint32) (uint32, uint8) {
+	return entry >> sparseValueBits, uint8(entry)
+}
+
+func (hll *HyperLogLog) IsSparse() bool {
+	hll.mutex.RLock()
+	defer hll.mutex.RUnlock()
+
+	return hll.sparse != nil
+}
+
+func (hll *HyperLogLog) sparseSearch(index uint32) int {
+	return sort.Search(len(hll.sparse), func(i int) bool {
+		return hll.sparse[i]>>sparseValueBits >= index
+	})
+}
+
+func (hll *HyperLogLog) bucket(index uint32) uint8 {
+	if hll.sparse == nil {
+		return hll.buckets[index]
+	}
+
+	i := hll.sparseSearch(index)
+	if i < len(hll.sparse) && hll.sparse[i]>>sparseValueBits == index {
+		return uint8(hll.sparse[i])
+	}
+	return 0
+}
+
+func (hll *HyperLogLog) setBucket(index uint32, value uint8) {
+	if hll.sparse == nil {
+		hll.buckets[index] = value
+		return
+	}
+
+	i := hll.sparseSearch(index)
+	if i < len(hll.sparse) && hll.sparse[i]>>sparseValueBits == index {
+		hll.sparse[i] = packSparseEntry(index, value)
+		return
+	}
+
+	hll.sparse = append(hll.sparse, 0)
+	copy(hll.sparse[i+1:], hll.sparse[i:])
+	hll.sparse[i] = packSparseEntry(index, value)
+	if uint32(len(hll.sparse)) > hll.sparseLimit {
+		hll.toDense()
+	}
+}
+
+func (hll *HyperLogLog) toDense() {
+	hll.buckets = hll.denseBuckets()
+	hll.sparse = nil
+}
+
+func (hll *HyperLogLog) resetBuckets() {
+	if hll.sparseLimit > 0 {
+		hll.sparse = make([]uint32, 0)
+		hll.buckets = nil
+		return
+	}
+	hll.buckets = make([]uint8, hll.m)
+}
+
+func (hll *HyperLogLog) denseBuckets() []uint8 {
+	if hll.sparse == nil {
+		return hll.buckets
+	}
+
+	buckets := make([]uint8, hll.m)
+	for _, entry := range hll.sparse {
+		index, value := unpackSparseEntry(entry)
+		buckets[index] = value
+	}
+	return buckets
+}
+
+func (hll *HyperLogLog) populatedEntries() []BucketEntry {
+	entries := make([]BucketEntry, 0)
+	if hll.sparse != nil {
+		for _, entry := range hll.sparse {
+			index, value := unpackSparseEntry(entry)
+			entries = append(entries, BucketEntry{Index: index, Value: value})
+		}
+		return entries
+	}
+
+	for i, value := range hll.buckets {
+		if value != 0 {
+			entries = append(entries, BucketEntry{Index: uint32(i), Value: value})
+		}
+	}
+	return entries
+}
@@ -0,0 +1,212 @@
+package probabilistic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	kserrors "github.com/asmit27rai/kubesight/pkg/errors"
+)
+
+func hllTestItems(n int) [][]byte {
+	items := make([][]byte, n)
+	for i := range items {
+		items[i] = []byte(fmt.Sprintf("item-%d", i))
+	}
+	return items
+}
+
+func TestHyperLogLogSparseMatchesDense(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 1000, 10000} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			sparse := NewHyperLogLog(14)
+			dense := NewHyperLogLogWithSparseThreshold(14, 0)
+			for _, item := range hllTestItems(n) {
+				sparse.Add(item)
+				dense.Add(item)
+			}
+
+			if got, want := sparse.Count(), dense.Count(); got != want {
+				t.Fatalf("sparse count = %d, dense count = %d", got, want)
+			}
+			if got, want := sparse.MerkleRoot(), dense.MerkleRoot(); got != want {
+				t.Fatalf("sparse and dense registers differ")
+			}
+		})
+	}
+}
+
+func TestHyperLogLogSparseConvertsAtThreshold(t *testing.T) {
+	hll := NewHyperLogLog(10)
+	if !hll.GetStats().IsSparse {
+		t.Fatal("new HyperLogLog should start sparse")
+	}
+
+	for _, item := range hllTestItems(1000) {
+		hll.Add(item)
+	}
+
+	stats := hll.GetStats()
+	if stats.IsSparse {
+		t.Fatalf("HyperLogLog with %d populated buckets should be dense", stats.Buckets-stats.EmptyBuckets)
+	}
+	if populated := stats.Buckets - stats.EmptyBuckets; populated <= sparseLimit(stats.Buckets, DefaultSparseThreshold) {
+		t.Fatalf("converted to dense with only %d populated buckets", populated)
+	}
+}
+
+func TestHyperLogLogSparseMerge(t *testing.T) {
+	items := hllTestItems(2000)
+
+	left := NewHyperLogLog(14)
+	right := NewHyperLogLog(14)
+	union := NewHyperLogLogWithSparseThreshold(14, 0)
+	for i, item := range items {
+		if i%2 == 0 {
+			left.Add(item)
+		} else {
+			right.Add(item)
+		}
+		union.Add(item)
+	}
+
+	if err := left.Merge(right); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if got, want := left.Count(), union.Count(); got != want {
+		t.Fatalf("merged count = %d, want %d", got, want)
+	}
+}
+
+func TestHyperLogLogSelfMerge(t *testing.T) {
+	for _, threshold := range []float64{DefaultSparseThreshold, 0} {
+		t.Run(fmt.Sprintf("sparse_threshold=%v", threshold), func(t *testing.T) {
+			hll := NewHyperLogLogWithSparseThreshold(14, threshold)
+			for _, item := range hllTestItems(1000) {
+				hll.Add(item)
+			}
+			before := hll.Count()
+
+			done := make(chan error, 1)
+			go func() { done <- hll.Merge(hll) }()
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("Merge() error = %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Merge() with itself did not return")
+			}
+
+			if got := hll.Count(); got != before {
+				t.Fatalf("count after self-merge = %d, want %d", got, before)
+			}
+		})
+	}
+}
+
+func TestHyperLogLogConcurrentOppositeMerges(t *testing.T) {
+	left := NewHyperLogLogWithSparseThreshold(12, 0)
+	right := NewHyperLogLogWithSparseThreshold(12, 0)
+	union := NewHyperLogLogWithSparseThreshold(12, 0)
+	for i, item := range hllTestItems(5000) {
+		if i%2 == 0 {
+			left.Add(item)
+		} else {
+			right.Add(item)
+		}
+		union.Add(item)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			left.Merge(right)
+		}()
+		go func() {
+			defer wg.Done()
+			right.Merge(left)
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("concurrent merges in opposite directions deadlocked")
+	}
+
+	for name, hll := range map[string]*HyperLogLog{"left": left, "right": right} {
+		if got, want := hll.Count(), union.Count(); got != want {
+			t.Errorf("%s count = %d, want the union count %d", name, got, want)
+		}
+	}
+}
+
+func TestHyperLogLogMergePrecisionMismatch(t *testing.T) {
+	err := NewHyperLogLog(12).Merge(NewHyperLogLog(14))
+	if !errors.Is(err, ErrPrecisionMismatch) {
+		t.Fatalf("Merge() error = %v, want errors.Is ErrPrecisionMismatch", err)
+	}
+
+	var mismatch *kserrors.ErrPrecisionMismatch
+	if !errors.As(err, &mismatch) || mismatch.Expected != 12 || mismatch.Actual != 14 {
+		t.Fatalf("Merge() error = %#v, want expected 12, actual 14", err)
+	}
+}
+
+func TestHyperLogLogSparseAccuracy(t *testing.T) {
+	hll := NewHyperLogLog(14)
+	for _, item := range hllTestItems(100) {
+		hll.Add(item)
+	}
+
+	if relErr := math.Abs(float64(hll.Count())-100) / 100; relErr > 0.05 {
+		t.Fatalf("count = %d, relative error %.3f exceeds 5%%", hll.Count(), relErr)
+	}
+}
+
+func TestHyperLogLogSparseAllocatesLess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping allocation comparison in short mode")
+	}
+
+	sparse := testing.Benchmark(BenchmarkHyperLogLogSparse100)
+	dense := testing.Benchmark(BenchmarkHyperLogLogDense100)
+
+	ratio := float64(dense.AllocedBytesPerOp()) / float64(sparse.AllocedBytesPerOp())
+	if ratio < 10 {
+		t.Fatalf("sparse allocates %d B/op, dense %d B/op: %.1fx less, want at least 10x",
+			sparse.AllocedBytesPerOp(), dense.AllocedBytesPerOp(), ratio)
+	}
+}
+
+func benchmarkHyperLogLog100(b *testing.B, threshold float64) {
+	items := hllTestItems(100)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		hll := NewHyperLogLogWithSparseThreshold(14, threshold)
+		for _, item := range items {
+			hll.Add(item)
+		}
+	}
+}
+
+func BenchmarkHyperLogLogSparse100(b *testing.B) {
+	benchmarkHyperLogLog100(b, DefaultSparseThreshold)
+}
+
+func BenchmarkHyperLogLogDense100(b *testing.B) {
+	benchmarkHyperLogLog100(b, 0)
+}
@@ -0,0 +1,300 @@
+package probabilistic
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+const DefaultKLLK = 200
+
+type KLLSketch struct {
+	k          int
+	compactors [][]float64
+	size       int
+	maxSize    int
+	count      uint64
+	min        float64
+	max        float64
+	rng        *rand.Rand
+	mutex      sync.RWMutex
+}
+
+type kllState struct {
+	K          int
+	Compactors [][]float64
+	Count      uint64
+	Min        float64
+	Max        float64
+}
+
+func NewKLLSketch(k int) *KLLSketch {
+	if k < 8 {
+		k = DefaultKLLK
+	}
+
+	sketch := &KLLSketch{
+		k:   k,
+		min: math.Inf(1),
+		max: math.Inf(-1),
+		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	sketch.grow()
+
+	return sketch
+}
+
+func (s *KLLSketch) Update(value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.compactors[0] = append(s.compactors[0], value)
+	s.size++
+	s.count++
+	s.min = math.Min(s.min, value)
+	s.max = math.Max(s.max, value)
+
+	if s.size >= s.maxSize {
+		s.compress()
+	}
+}
+
+func (s *KLLSketch) Merge(other *KLLSketch) error {
+	if other == nil {
+		return fmt.Errorf("cannot merge nil KLL sketch")
+	}
+	if s == other {
+		return fmt.Errorf("cannot merge KLL sketch with itself")
+	}
+
+	s.mutex.Lock()
+	other.mutex.RLock()
+	defer s.mutex.Unlock()
+	defer other.mutex.RUnlock()
+
+	for len(s.compactors) < len(other.compactors) {
+		s.grow()
+	}
+
+	for h, items := range other.compactors {
+		s.compactors[h] = append(s.compactors[h], items...)
+	}
+
+	s.count += other.count
+	s.min = math.Min(s.min, other.min)
+	s.max = math.Max(s.max, other.max)
+	s.updateSize()
+
+	for s.size >= s.maxSize {
+		s.compress()
+	}
+
+	return nil
+}
+
+type weightedItem struct {
+	value  float64
+	weight uint64
+}
+
+func (s *KLLSketch) Quantile(q float64) float64 {
+	return s.Quantiles([]float64{q})[0]
+}
+
+func (s *KLLSketch) Quantiles(qs []float64) []float64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]float64, len(qs))
+	if s.count == 0 {
+		for i := range result {
+			result[i] = math.NaN()
+		}
+		return result
+	}
+
+	items, totalWeight := s.sortedItems()
+	for i, q := range qs {
+		result[i] = s.quantile(items, totalWeight, q)
+	}
+
+	return result
+}
+
+func (s *KLLSketch) CDF(value float64) float64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.count == 0 {
+		return math.NaN()
+	}
+
+	items, totalWeight := s.sortedItems()
+	below := uint64(0)
+	for _, item := range items {
+		if item.value > value {
+			break
+		}
+		below += item.weight
+	}
+
+	return float64(below) / float64(totalWeight)
+}
+
+func (s *KLLSketch) sortedItems() ([]weightedItem, uint64) {
+	items := make([]weightedItem, 0, s.size)
+	totalWeight := uint64(0)
+	for h, compactor := range s.compactors {
+		weight := uint64(1) << uint(h)
+		for _, value := range compactor {
+			items = append(items, weightedItem{value: value, weight: weight})
+			totalWeight += weight
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].value < items[j].value
+	})
+
+	return items, totalWeight
+}
+
+func (s *KLLSketch) quantile(items []weightedItem, totalWeight uint64, q float64) float64 {
+	if q <= 0 {
+		return s.min
+	}
+	if q >= 1 {
+		return s.max
+	}
+
+	target := q * float64(totalWeight)
+	cumulative := uint64(0)
+	for _, item := range items {
+		cumulative += item.weight
+		if float64(cumulative) >= target {
+			return item.value
+		}
+	}
+
+	return s.max
+}
+
+func (s *KLLSketch) Count() uint64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.count
+}
+
+func (s *KLLSketch) Serialize() ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	state := kllState{
+		K:          s.k,
+		Compactors: s.compactors,
+		Count:      s.count,
+		Min:        s.min,
+		Max:        s.max,
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return nil, fmt.Errorf("failed to encode KLL sketch: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func DeserializeKLLSketch(data []byte) (*KLLSketch, error) {
+	var state kllState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state); err != nil {
+		return nil, fmt.Errorf("failed to decode KLL sketch: %v", err)
+	}
+
+	sketch := NewKLLSketch(state.K)
+	for len(sketch.compactors) < len(state.Compactors) {
+		sketch.grow()
+	}
+	for h, items := range state.Compactors {
+		sketch.compactors[h] = append(sketch.compactors[h], items...)
+	}
+
+	sketch.count = state.Count
+	sketch.min = state.Min
+	sketch.max = state.Max
+	sketch.updateSize()
+
+	for sketch.size >= sketch.maxSize {
+		sketch.compress()
+	}
+
+	return sketch, nil
+}
+
+func (s *KLLSketch) grow() {
+	s.compactors = append(s.compactors, make([]float64, 0))
+
+	s.maxSize = 0
+	for h := range s.compactors {
+		s.maxSize += s.capacity(h)
+	}
+}
+
+func (s *KLLSketch) capacity(h int) int {
+	depth := len(s.compactors) - h - 1
+	return int(math.Ceil(float64(s.k)*math.Pow(2.0/3.0, float64(depth)))) + 1
+}
+
+func (s *KLLSketch) compress() {
+	for h := 0; h < len(s.compactors); h++ {
+		if len(s.compactors[h]) < s.capacity(h) {
+			continue
+		}
+		if h+1 >= len(s.compactors) {
+			s.grow()
+		}
+
+		s.compactors[h+1] = append(s.compactors[h+1], s.compact(h)...)
+		s.updateSize()
+
+		if s.size < s.maxSize {
+			break
+		}
+	}
+}
+
+func (s *KLLSketch) compact(h int) []float64 {
+	items := s.compactors[h]
+	sort.Float64s(items)
+
+	var leftover []float64
+	if len(items)%2 == 1 {
+		leftover = []float64{items[len(items)-1]}
+		items = items[:len(items)-1]
+	}
+
+	offset := s.rng.Intn(2)
+	promoted := make([]float64, 0, len(items)/2)
+	for i := offset; i < len(items); i += 2 {
+		promoted = append(promoted, items[i])
+	}
+
+	s.compactors[h] = append(make([]float64, 0, s.capacity(h)), leftover...)
+	return promoted
+}
+
+func (s *KLLSketch) updateSize() {
+	s.size = 0
+	for _, compactor := range s.compactors {
+		s.size += len(compactor)
+	}
+}
@@ -0,0 +1,99 @@
+package probabilistic
+
+import (
+	"container/list"
+	"hash/fnv"
+	"sync"
+)
+
+const DefaultRegistryCapacity = 100000
+
+type ItemRegistry struct {
+	capacity int
+	items    map[uint32]*list.Element
+	order    *list.List
+	mutex    sync.Mutex
+}
+
+type registryEntry struct {
+	fingerprint uint32
+	key         string
+}
+
+func NewItemRegistry(capacity int) *ItemRegistry {
+	if capacity <= 0 {
+		capacity = DefaultRegistryCapacity
+	}
+
+	return &ItemRegistry{
+		capacity: capacity,
+		items:    make(map[uint32]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func Fingerprint(key string) uint32 {
+	hasher := fnv.New32a()
+	hasher.Write([]byte(key))
+	return hasher.Sum32()
+}
+
+func (r *ItemRegistry) Register(key string) uint32 {
+	fingerprint := Fingerprint(key)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if element, exists := r.items[fingerprint]; exists {
+		element.Value.(*registryEntry).key = key
+		r.order.MoveToFront(element)
+		return fingerprint
+	}
+
+	r.items[fingerprint] = r.order.PushFront(&registryEntry{fingerprint: fingerprint, key: key})
+
+	for r.order.Len() > r.capacity {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		delete(r.items, oldest.Value.(*registryEntry).fingerprint)
+	}
+
+	return fingerprint
+}
+
+func (r *ItemRegistry) Lookup(fingerprint uint32) (string, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	element, exists := r.items[fingerprint]
+	if !exists {
+		return "", false
+	}
+	return element.Value.(*registryEntry).key, true
+}
+
+func (r *ItemRegistry) Keys() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	keys := make([]string, 0, r.order.Len())
+	for element := r.order.Front(); element != nil; element = element.Next() {
+		keys = append(keys, element.Value.(*registryEntry).key)
+	}
+	return keys
+}
+
+func (r *ItemRegistry) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.order.Len()
+}
+
+func (r *ItemRegistry) Clear() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.items = make(map[uint32]*list.Element)
+	r.order.Init()
+}
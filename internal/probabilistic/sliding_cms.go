@@ -0,0 +1,128 @@
+package probabilistic
+
+import (
+	"sync"
+	"time"
+)
+
+type SlidingWindowCMS struct {
+	windowSize time.Duration
+	windows    []*CountMinSketch
+	starts     []time.Time
+	current    int
+	mutex      sync.RWMutex
+}
+
+func NewSlidingWindowCMS(width, depth uint32, numWindows int, windowSize time.Duration) *SlidingWindowCMS {
+	if numWindows <= 0 {
+		numWindows = 1
+	}
+	if windowSize <= 0 {
+		windowSize = time.Minute
+	}
+
+	s := &SlidingWindowCMS{
+		windowSize: windowSize,
+		windows:    make([]*CountMinSketch, numWindows),
+		starts:     make([]time.Time, numWindows),
+	}
+	for i := range s.windows {
+		s.windows[i] = NewCountMinSketch(width, depth)
+	}
+
+	return s
+}
+
+func (s *SlidingWindowCMS) Update(item []byte, count uint32, timestamp time.Time) bool {
+	slotStart := timestamp.Truncate(s.windowSize)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.starts[s.current].IsZero() {
+		s.starts[s.current] = slotStart
+	}
+
+	currentStart := s.starts[s.current]
+	if slotStart.After(currentStart) {
+		steps := int(slotStart.Sub(currentStart) / s.windowSize)
+		if steps >= len(s.windows) {
+			s.clearAll()
+			s.starts[s.current] = slotStart
+		} else {
+			for i := 0; i < steps; i++ {
+				s.rotate()
+			}
+		}
+		s.windows[s.current].Update(item, count)
+		return true
+	}
+
+	back := int(currentStart.Sub(slotStart) / s.windowSize)
+	if back >= len(s.windows) {
+		return false
+	}
+	idx := (s.current - back + len(s.windows)) % len(s.windows)
+	if !s.starts[idx].Equal(slotStart) {
+		return false
+	}
+	s.windows[idx].Update(item, count)
+	return true
+}
+
+func (s *SlidingWindowCMS) Estimate(item []byte) uint32 {
+	return s.EstimateSince(item, time.Time{})
+}
+
+func (s *SlidingWindowCMS) EstimateSince(item []byte, since time.Time) uint32 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	total := uint32(0)
+	for i, window := range s.windows {
+		if s.starts[i].IsZero() || !s.starts[i].Add(s.windowSize).After(since) {
+			continue
+		}
+		total += window.Estimate(item)
+	}
+	return total
+}
+
+func (s *SlidingWindowCMS) Rotate() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rotate()
+}
+
+func (s *SlidingWindowCMS) WindowSize() time.Duration {
+	return s.windowSize
+}
+
+func (s *SlidingWindowCMS) Span() time.Duration {
+	return s.windowSize * time.Duration(len(s.windows))
+}
+
+func (s *SlidingWindowCMS) Clear() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.clearAll()
+}
+
+func (s *SlidingWindowCMS) rotate() {
+	previousStart := s.starts[s.current]
+	s.current = (s.current + 1) % len(s.windows)
+	s.windows[s.current].Clear()
+	s.starts[s.current] = time.Time{}
+	if !previousStart.IsZero() {
+		s.starts[s.current] = previousStart.Add(s.windowSize)
+	}
+}
+
+func (s *SlidingWindowCMS) clearAll() {
+	for i, window := range s.windows {
+		window.Clear()
+		s.starts[i] = time.Time{}
+	}
+}
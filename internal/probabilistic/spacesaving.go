@@ -0,0 +1,94 @@
+package probabilistic
+
+import (
+	"sort"
+	"sync"
+)
+
+type SpaceSaving struct {
+	capacity int
+	counters map[string]*SpaceSavingItem
+	total    uint64
+	mutex    sync.RWMutex
+}
+
+type SpaceSavingItem struct {
+	Item  string `json:"item"`
+	Count uint64 `json:"count"`
+	Error uint64 `json:"error"`
+}
+
+func NewSpaceSaving(capacity int) *SpaceSaving {
+	if capacity <= 0 {
+		capacity = 1
+	}
+
+	return &SpaceSaving{
+		capacity: capacity,
+		counters: make(map[string]*SpaceSavingItem, capacity),
+	}
+}
+
+func (ss *SpaceSaving) Offer(item string, count uint64) {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	ss.total += count
+
+	if counter, exists := ss.counters[item]; exists {
+		counter.Count += count
+		return
+	}
+
+	if len(ss.counters) < ss.capacity {
+		ss.counters[item] = &SpaceSavingItem{Item: item, Count: count}
+		return
+	}
+
+	var minCounter *SpaceSavingItem
+	for _, counter := range ss.counters {
+		if minCounter == nil || counter.Count < minCounter.Count {
+			minCounter = counter
+		}
+	}
+
+	delete(ss.counters, minCounter.Item)
+	ss.counters[item] = &SpaceSavingItem{
+		Item:  item,
+		Count: minCounter.Count + count,
+		Error: minCounter.Count,
+	}
+}
+
+func (ss *SpaceSaving) TopK(k int) []SpaceSavingItem {
+	ss.mutex.RLock()
+	defer ss.mutex.RUnlock()
+
+	items := make([]SpaceSavingItem, 0, len(ss.counters))
+	for _, counter := range ss.counters {
+		items = append(items, *counter)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Item < items[j].Item
+	})
+
+	if len(items) > k {
+		return items[:k]
+	}
+	return items
+}
+
+func (ss *SpaceSaving) Total() uint64 {
+	ss.mutex.RLock()
+	defer ss.mutex.RUnlock()
+
+	return ss.total
+}
+
+func (ss *SpaceSaving) EstimateError() float64 {
+	return 1 / float64(ss.capacity)
+}
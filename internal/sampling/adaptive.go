@@ -1,8 +1,11 @@
 package sampling
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
+	"path"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +21,7 @@ type AdaptiveSampler struct {
 	rng             *rand.Rand
 	totalProcessed  uint64
 	totalSampled    uint64
+	compaction      CompactionStats
 }
 
 type SamplingConfig struct {
@@ -26,9 +30,31 @@ type SamplingConfig struct {
 	WindowSize     time.Duration      `json:"window_size"`
 	ReservoirSize  int                `json:"reservoir_size"`
 	StratumWeights map[string]float64 `json:"stratum_weights"`
+
+	MetricPriorities map[string]int `json:"metric_priorities"`
+
+	AnomalyFns []AnomalyFn `json:"-"`
+}
+
+var DefaultMetricPriorities = map[string]int{
+	"pod_restarts": 5,
+	"error_rate":   4,
+	"cpu_usage":    3,
+	"memory_usage": 3,
+	"disk_usage":   2,
+	"network_*":    1,
 }
 
 func NewAdaptiveSampler(config SamplingConfig) *AdaptiveSampler {
+	if config.MetricPriorities == nil {
+		config.MetricPriorities = DefaultMetricPriorities
+	}
+	config.MetricPriorities = copyMetricPriorities(config.MetricPriorities)
+	if config.AnomalyFns == nil {
+		config.AnomalyFns = DefaultAnomalyFns
+	}
+	config.AnomalyFns = append([]AnomalyFn{}, config.AnomalyFns...)
+
 	return &AdaptiveSampler{
 		config:          config,
 		reservoirs:      make(map[string]*ReservoirSampler),
@@ -44,20 +70,14 @@ func (as *AdaptiveSampler) ShouldSample(metric *metrics.MetricPoint) bool {
 	as.mutex.Lock()
 	defer as.mutex.Unlock()
 
-	as.totalProcessed++
-
-	samplingRate := as.calculateSamplingRate(metric)
-
-	shouldSample := as.rng.Float64() < samplingRate
-	if shouldSample {
-		as.totalSampled++
-	}
-
-	return shouldSample
+	return as.shouldSample(metric)
 }
 
 func (as *AdaptiveSampler) Sample(metric *metrics.MetricPoint) (*metrics.MetricPoint, bool) {
-	if !as.ShouldSample(metric) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	if !as.shouldSample(metric) {
 		return nil, false
 	}
 
@@ -75,11 +95,60 @@ func (as *AdaptiveSampler) GetEffectiveSamplingRate() float64 {
 	as.mutex.RLock()
 	defer as.mutex.RUnlock()
 
-	if as.totalProcessed == 0 {
-		return as.config.BaseRate
+	return as.effectiveSamplingRate()
+}
+
+func (as *AdaptiveSampler) SetBaseRate(rate float64) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	as.config.BaseRate = rate
+}
+
+func (as *AdaptiveSampler) GetMetricPriorities() map[string]int {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	return copyMetricPriorities(as.config.MetricPriorities)
+}
+
+func (as *AdaptiveSampler) SetMetricPriorities(priorities map[string]int) error {
+	if err := ValidateMetricPriorities(priorities); err != nil {
+		return err
 	}
 
-	return float64(as.totalSampled) / float64(as.totalProcessed)
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	as.config.MetricPriorities = copyMetricPriorities(priorities)
+	return nil
+}
+
+func ValidateMetricPriorities(priorities map[string]int) error {
+	for pattern, priority := range priorities {
+		if pattern == "" {
+			return fmt.Errorf("metric priority pattern must not be empty")
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid metric priority pattern %q: %v", pattern, err)
+		}
+		if priority < 0 {
+			return fmt.Errorf("metric priority for %q must not be negative: %d", pattern, priority)
+		}
+	}
+	return nil
+}
+
+func copyMetricPriorities(priorities map[string]int) map[string]int {
+	copied := make(map[string]int, len(priorities))
+	for pattern, priority := range priorities {
+		copied[pattern] = priority
+	}
+	return copied
+}
+
+func (as *AdaptiveSampler) AnomalyDetector() *AnomalyDetector {
+	return as.anomalyDetector
 }
 
 func (as *AdaptiveSampler) GetSamples(stratum string) []*metrics.MetricPoint {
@@ -103,6 +172,15 @@ func (as *AdaptiveSampler) GetAllSamples() map[string][]*metrics.MetricPoint {
 	return result
 }
 
+func (as *AdaptiveSampler) ClearReservoirs() int {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	cleared := len(as.reservoirs)
+	as.reservoirs = make(map[string]*ReservoirSampler)
+	return cleared
+}
+
 func (as *AdaptiveSampler) GetStats() SamplingStats {
 	as.mutex.RLock()
 	defer as.mutex.RUnlock()
@@ -110,7 +188,7 @@ func (as *AdaptiveSampler) GetStats() SamplingStats {
 	return SamplingStats{
 		TotalProcessed:        as.totalProcessed,
 		TotalSampled:          as.totalSampled,
-		EffectiveSamplingRate: as.GetEffectiveSamplingRate(),
+		EffectiveSamplingRate: as.effectiveSamplingRate(),
 		ActiveReservoirs:      len(as.reservoirs),
 		BaseRate:              as.config.BaseRate,
 		AnomalyRate:           as.config.AnomalyRate,
@@ -126,6 +204,27 @@ type SamplingStats struct {
 	AnomalyRate           float64 `json:"anomaly_rate"`
 }
 
+func (as *AdaptiveSampler) shouldSample(metric *metrics.MetricPoint) bool {
+	as.totalProcessed++
+
+	samplingRate := as.calculateSamplingRate(metric)
+
+	shouldSample := as.rng.Float64() < samplingRate
+	if shouldSample {
+		as.totalSampled++
+	}
+
+	return shouldSample
+}
+
+func (as *AdaptiveSampler) effectiveSamplingRate() float64 {
+	if as.totalProcessed == 0 {
+		return as.config.BaseRate
+	}
+
+	return float64(as.totalSampled) / float64(as.totalProcessed)
+}
+
 func (as *AdaptiveSampler) calculateSamplingRate(metric *metrics.MetricPoint) float64 {
 	baseRate := as.config.BaseRate
 
@@ -138,20 +237,45 @@ func (as *AdaptiveSampler) calculateSamplingRate(metric *metrics.MetricPoint) fl
 		baseRate *= weight
 	}
 
-	if stats, exists := as.statistics[stratum]; exists {
+	stats := as.statistics[stratum]
+	if stats != nil {
 		variance := stats.GetVariance()
 		baseRate *= (1.0 + variance/100.0)
 	}
 
-	if metric.MetricName == "cpu_usage" || metric.MetricName == "memory_usage" {
-		if metric.Value > 0.8 {
-			baseRate *= 2.0
-		}
+	if score := as.anomalyScore(metric, stats); score > 0 && as.config.BaseRate > 0 {
+		baseRate *= 1 + score*(as.config.AnomalyRate/as.config.BaseRate-1)
+	}
+
+	if priority, maxPriority := as.metricPriority(metric.MetricName); maxPriority > 0 {
+		baseRate *= 1.0 + float64(priority)/float64(maxPriority)
 	}
 
 	return math.Min(math.Max(baseRate, 0.001), 1.0)
 }
 
+func (as *AdaptiveSampler) metricPriority(metricName string) (int, int) {
+	priority, matched, maxPriority := 0, false, 0
+	for pattern, p := range as.config.MetricPriorities {
+		if p > maxPriority {
+			maxPriority = p
+		}
+		if pattern == metricName {
+			priority, matched = p, true
+		}
+	}
+	if matched {
+		return priority, maxPriority
+	}
+
+	for pattern, p := range as.config.MetricPriorities {
+		if ok, _ := path.Match(pattern, metricName); ok && p > priority {
+			priority = p
+		}
+	}
+	return priority, maxPriority
+}
+
 func (as *AdaptiveSampler) getStratum(metric *metrics.MetricPoint) string {
 	return metric.ClusterID + "/" + metric.Namespace + "/" + metric.MetricName
 }
@@ -231,7 +355,7 @@ func (ws *WindowStats) GetVariance() float64 {
 func (ws *WindowStats) cleanup(currentTime time.Time) {
 	cutoff := currentTime.Add(-ws.windowSize)
 
-	keepFrom := 0
+	keepFrom := len(ws.timestamps)
 	for i, ts := range ws.timestamps {
 		if ts.After(cutoff) {
 			keepFrom = i
@@ -239,6 +363,14 @@ func (ws *WindowStats) cleanup(currentTime time.Time) {
 		}
 	}
 
+	if keepFrom == len(ws.timestamps) {
+		ws.values = make([]float64, 0)
+		ws.timestamps = make([]time.Time, 0)
+		ws.sum = 0
+		ws.sumSquares = 0
+		return
+	}
+
 	if keepFrom > 0 {
 		for i := 0; i < keepFrom; i++ {
 			ws.sum -= ws.values[i]
@@ -287,7 +419,101 @@ func (ad *AnomalyDetector) IsAnomaly(metric *metrics.MetricPoint) bool {
 }
 
 func (ad *AnomalyDetector) setDefaultThresholds() {
-	ad.thresholds = map[string]AnomalyThreshold{
+	ad.thresholds = defaultAnomalyThresholds()
+}
+
+func (ad *AnomalyDetector) Thresholds() []AnomalyThreshold {
+	ad.mutex.RLock()
+	defer ad.mutex.RUnlock()
+
+	thresholds := make([]AnomalyThreshold, 0, len(ad.thresholds))
+	for _, threshold := range ad.thresholds {
+		thresholds = append(thresholds, threshold)
+	}
+	sort.Slice(thresholds, func(i, j int) bool {
+		return thresholds[i].MetricName < thresholds[j].MetricName
+	})
+	return thresholds
+}
+
+func (ad *AnomalyDetector) Threshold(metricName string) (AnomalyThreshold, bool) {
+	ad.mutex.RLock()
+	defer ad.mutex.RUnlock()
+
+	threshold, exists := ad.thresholds[metricName]
+	return threshold, exists
+}
+
+func (ad *AnomalyDetector) SetThreshold(threshold AnomalyThreshold) error {
+	if err := ValidateAnomalyThreshold(threshold); err != nil {
+		return err
+	}
+
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+
+	ad.thresholds[threshold.MetricName] = threshold
+	return nil
+}
+
+func (ad *AnomalyDetector) RevertThreshold(metricName string) (AnomalyThreshold, bool) {
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+
+	if threshold, exists := defaultAnomalyThresholds()[metricName]; exists {
+		ad.thresholds[metricName] = threshold
+		return threshold, true
+	}
+	delete(ad.thresholds, metricName)
+	return AnomalyThreshold{}, false
+}
+
+func (ad *AnomalyDetector) ResetThresholds() {
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+
+	ad.setDefaultThresholds()
+}
+
+type AnomalyThresholdUpdate struct {
+	UpperBound *float64 `json:"upper_bound,omitempty"`
+	LowerBound *float64 `json:"lower_bound,omitempty"`
+	ZScore     *float64 `json:"z_score,omitempty"`
+}
+
+func (u AnomalyThresholdUpdate) Apply(threshold AnomalyThreshold) AnomalyThreshold {
+	if u.UpperBound != nil {
+		threshold.UpperBound = *u.UpperBound
+	}
+	if u.LowerBound != nil {
+		threshold.LowerBound = *u.LowerBound
+	}
+	if u.ZScore != nil {
+		threshold.ZScore = *u.ZScore
+	}
+	return threshold
+}
+
+func ValidateAnomalyThreshold(threshold AnomalyThreshold) error {
+	if threshold.MetricName == "" {
+		return fmt.Errorf("anomaly threshold metric name must not be empty")
+	}
+	if math.IsNaN(threshold.UpperBound) || math.IsNaN(threshold.LowerBound) || math.IsNaN(threshold.ZScore) {
+		return fmt.Errorf("anomaly threshold for %s must not contain NaN", threshold.MetricName)
+	}
+	if threshold.LowerBound > threshold.UpperBound {
+		return fmt.Errorf("anomaly threshold for %s has lower bound %g above upper bound %g",
+			threshold.MetricName, threshold.LowerBound, threshold.UpperBound)
+	}
+	if threshold.ZScore < 0 {
+		return fmt.Errorf("anomaly threshold z-score for %s must not be negative: %g",
+			threshold.MetricName, threshold.ZScore)
+	}
+	return nil
+}
+
+func defaultAnomalyThresholds() map[string]AnomalyThreshold {
+	return map[string]AnomalyThreshold{
 		"cpu_usage": {
 			MetricName: "cpu_usage",
 			UpperBound: 0.9,
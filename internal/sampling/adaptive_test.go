@@ -0,0 +1,77 @@
+package sampling
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+func TestWindowStatsEvictsExpiredValues(t *testing.T) {
+	ws := NewWindowStats(time.Second)
+	for _, value := range []float64{1, 2, 3} {
+		ws.Add(value, time.Now())
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	ws.Add(10, time.Now())
+
+	ws.mutex.RLock()
+	defer ws.mutex.RUnlock()
+
+	if len(ws.values) != 1 || len(ws.timestamps) != 1 {
+		t.Fatalf("window holds %d values and %d timestamps, want 1 of each", len(ws.values), len(ws.timestamps))
+	}
+	if ws.values[0] != 10 {
+		t.Fatalf("remaining value = %v, want 10", ws.values[0])
+	}
+	if ws.sum != 10 || ws.sumSquares != 100 {
+		t.Fatalf("sum = %v, sumSquares = %v, want 10 and 100", ws.sum, ws.sumSquares)
+	}
+}
+
+func TestAdaptiveSamplerConcurrentSampleCounts(t *testing.T) {
+	const calls = 10000
+
+	sampler := NewAdaptiveSampler(SamplingConfig{
+		BaseRate:      0.5,
+		AnomalyRate:   1.0,
+		WindowSize:    time.Minute,
+		ReservoirSize: calls,
+	})
+
+	namespaces := []string{"default", "kube-system", "monitoring"}
+	metricNames := []string{"cpu_usage", "memory_usage"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sampler.Sample(&metrics.MetricPoint{
+				Timestamp:  time.Now(),
+				ClusterID:  "test-cluster",
+				Namespace:  namespaces[i%len(namespaces)],
+				PodName:    fmt.Sprintf("pod-%d", i%50),
+				MetricName: metricNames[i%len(metricNames)],
+				Value:      float64(i%100) / 100,
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	retained := 0
+	for _, samples := range sampler.GetAllSamples() {
+		retained += len(samples)
+	}
+
+	stats := sampler.GetStats()
+	if stats.TotalProcessed != calls {
+		t.Fatalf("TotalProcessed = %d, want %d", stats.TotalProcessed, calls)
+	}
+	if stats.TotalSampled != uint64(retained) {
+		t.Fatalf("TotalSampled = %d, but reservoirs hold %d samples", stats.TotalSampled, retained)
+	}
+}
@@ -0,0 +1,79 @@
+package sampling
+
+import (
+	"math"
+	"path"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+type AnomalyFn struct {
+	MetricName string
+	Fn         func(value float64, stats *WindowStats) float64
+}
+
+var DefaultAnomalyFns = []AnomalyFn{
+	{MetricName: "cpu_usage", Fn: ThresholdFn(0.8)},
+	{MetricName: "memory_usage", Fn: ThresholdFn(0.8)},
+}
+
+func ThresholdFn(upper float64) func(float64, *WindowStats) float64 {
+	return func(value float64, _ *WindowStats) float64 {
+		if value > upper {
+			return 1
+		}
+		return 0
+	}
+}
+
+func ZScoreFn(k float64) func(float64, *WindowStats) float64 {
+	return func(value float64, stats *WindowStats) float64 {
+		if stats == nil || k <= 0 {
+			return 0
+		}
+		stdDev := math.Sqrt(stats.GetVariance())
+		if stdDev == 0 {
+			return 0
+		}
+		return math.Abs(value-stats.GetMean()) / stdDev / k
+	}
+}
+
+func PercentileFn(p float64) func(float64, *WindowStats) float64 {
+	threshold := p / 100
+	return func(value float64, stats *WindowStats) float64 {
+		if stats == nil || threshold <= 0 || threshold >= 1 {
+			return 0
+		}
+		values, _ := stats.snapshot()
+		if len(values) == 0 {
+			return 0
+		}
+
+		below := 0
+		for _, v := range values {
+			if v < value {
+				below++
+			}
+		}
+		rank := float64(below) / float64(len(values))
+		if rank < threshold {
+			return 0
+		}
+		return (rank - threshold) / (1 - threshold)
+	}
+}
+
+func (as *AdaptiveSampler) anomalyScore(metric *metrics.MetricPoint, stats *WindowStats) float64 {
+	score := 0.0
+	for _, anomalyFn := range as.config.AnomalyFns {
+		if anomalyFn.Fn == nil {
+			continue
+		}
+		if matched, err := path.Match(anomalyFn.MetricName, metric.MetricName); err != nil || !matched {
+			continue
+		}
+		score = math.Max(score, anomalyFn.Fn(metric.Value, stats))
+	}
+	return math.Min(math.Max(score, 0), 1)
+}
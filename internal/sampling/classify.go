@@ -0,0 +1,181 @@
+package sampling
+
+import (
+	"math"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+type AnomalyClass string
+
+const (
+	AnomalyNone  AnomalyClass = "none"
+	AnomalySpike AnomalyClass = "spike"
+	AnomalyShift AnomalyClass = "shift"
+	AnomalyDrift AnomalyClass = "drift"
+)
+
+const (
+	spikeSigma     = 3.0
+	shiftSigma     = 2.0
+	shiftMinPoints = 5
+	driftMinPoints = 10
+
+	DriftSlopeThreshold = 0.1
+)
+
+type AnomalyScore struct {
+	MetricName string  `json:"metric_name"`
+	Value      float64 `json:"value"`
+	Mean       float64 `json:"mean"`
+	StdDev     float64 `json:"std_dev"`
+	ZScore     float64 `json:"z_score"`
+	Points     int     `json:"points"`
+	Anomalous  bool    `json:"anomalous"`
+	Class      string  `json:"class"`
+}
+
+func ClassifyAnomaly(metric *metrics.MetricPoint, stats *WindowStats) AnomalyClass {
+	series := anomalySeries(metric, stats)
+
+	if isDrift(series) {
+		return AnomalyDrift
+	}
+	if isShift(series) {
+		return AnomalyShift
+	}
+
+	mean, stdDev := meanStdDev(series[:len(series)-1])
+	if stdDev > 0 && math.Abs(series[len(series)-1]-mean)/stdDev > spikeSigma {
+		return AnomalySpike
+	}
+	return AnomalyNone
+}
+
+func (as *AdaptiveSampler) ScoreAnomaly(metric *metrics.MetricPoint) AnomalyScore {
+	as.mutex.RLock()
+	stats := as.statistics[as.getStratum(metric)]
+	as.mutex.RUnlock()
+
+	series := anomalySeries(metric, stats)
+	mean, stdDev := meanStdDev(series[:len(series)-1])
+
+	zScore := 0.0
+	if stdDev > 0 {
+		zScore = (metric.Value - mean) / stdDev
+	}
+
+	class := ClassifyAnomaly(metric, stats)
+	return AnomalyScore{
+		MetricName: metric.MetricName,
+		Value:      metric.Value,
+		Mean:       mean,
+		StdDev:     stdDev,
+		ZScore:     zScore,
+		Points:     len(series),
+		Anomalous:  class != AnomalyNone || as.anomalyDetector.IsAnomaly(metric),
+		Class:      string(class),
+	}
+}
+
+func anomalySeries(metric *metrics.MetricPoint, stats *WindowStats) []float64 {
+	var values []float64
+	var timestamps []time.Time
+	if stats != nil {
+		values, timestamps = stats.snapshot()
+	}
+
+	last := len(values) - 1
+	if last >= 0 && values[last] == metric.Value && timestamps[last].Equal(metric.Timestamp) {
+		return values
+	}
+	return append(values, metric.Value)
+}
+
+func isDrift(series []float64) bool {
+	if len(series) <= driftMinPoints {
+		return false
+	}
+
+	tail := series[len(series)-driftMinPoints-1:]
+	rising := tail[1] > tail[0]
+	for i := 1; i < len(tail); i++ {
+		if tail[i] == tail[i-1] || (tail[i] > tail[i-1]) != rising {
+			return false
+		}
+	}
+
+	_, stdDev := meanStdDev(series)
+	if stdDev == 0 {
+		return false
+	}
+	return math.Abs(indexSlope(tail))/stdDev > DriftSlopeThreshold
+}
+
+func isShift(series []float64) bool {
+	if len(series) <= shiftMinPoints+2 {
+		return false
+	}
+
+	split := len(series) - shiftMinPoints - 1
+	mean, stdDev := meanStdDev(series[:split])
+	if stdDev == 0 {
+		return false
+	}
+
+	above := series[split] > mean
+	for _, value := range series[split:] {
+		if math.Abs(value-mean)/stdDev <= shiftSigma || (value > mean) != above {
+			return false
+		}
+	}
+	return true
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	if len(values) < 2 {
+		return mean, 0
+	}
+
+	squares := 0.0
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+func indexSlope(values []float64) float64 {
+	n := float64(len(values))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, value := range values {
+		x := float64(i)
+		sumX += x
+		sumY += value
+		sumXY += x * value
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
+
+func (ws *WindowStats) snapshot() ([]float64, []time.Time) {
+	ws.mutex.RLock()
+	defer ws.mutex.RUnlock()
+
+	return append([]float64(nil), ws.values...), append([]time.Time(nil), ws.timestamps...)
+}
@@ -0,0 +1,77 @@
+package sampling
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type CompactionStats struct {
+	LastRun                time.Time `json:"last_run"`
+	Runs                   uint64    `json:"runs"`
+	StaleReservoirsEvicted uint64    `json:"stale_reservoirs_evicted"`
+	StaleStatsEvicted      uint64    `json:"stale_stats_evicted"`
+}
+
+func (as *AdaptiveSampler) StartCompactor(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reservoirs, stats := as.Compact(time.Now())
+			if reservoirs > 0 || stats > 0 {
+				slog.Debug("Sampler compaction evicted state", "reservoirs", reservoirs, "window_stats", stats)
+			}
+		}
+	}
+}
+
+func (as *AdaptiveSampler) Compact(now time.Time) (int, int) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	statsEvicted := 0
+	for stratum, stats := range as.statistics {
+		stats.mutex.Lock()
+		stats.cleanup(now)
+		stale := stats.windowSize > 0 && (len(stats.timestamps) == 0 ||
+			!stats.timestamps[len(stats.timestamps)-1].After(now.Add(-stats.windowSize)))
+		stats.mutex.Unlock()
+
+		if stale {
+			delete(as.statistics, stratum)
+			statsEvicted++
+		}
+	}
+
+	reservoirsEvicted := 0
+	reservoirCutoff := now.Add(-2 * as.config.WindowSize)
+	for stratum, reservoir := range as.reservoirs {
+		if as.config.WindowSize > 0 && reservoir.LastUpdate().Before(reservoirCutoff) {
+			delete(as.reservoirs, stratum)
+			reservoirsEvicted++
+		}
+	}
+
+	as.compaction.LastRun = now
+	as.compaction.Runs++
+	as.compaction.StaleStatsEvicted += uint64(statsEvicted)
+	as.compaction.StaleReservoirsEvicted += uint64(reservoirsEvicted)
+
+	return reservoirsEvicted, statsEvicted
+}
+
+func (as *AdaptiveSampler) GetCompactionStats() CompactionStats {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	return as.compaction
+}
@@ -0,0 +1,127 @@
+package sampling
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const DefaultResolutionWindowCapacity = 1000
+
+var DefaultResolutionTiers = []time.Duration{time.Minute, 5 * time.Minute, time.Hour}
+
+type MultiResolutionSampler struct {
+	tiers []resolutionTier
+}
+
+type resolutionTier struct {
+	resolution time.Duration
+	retention  time.Duration
+	sampler    *TimeBasedSampler
+}
+
+type ResolutionTierInfo struct {
+	Resolution time.Duration `json:"resolution"`
+	Retention  time.Duration `json:"retention"`
+	Windows    int           `json:"windows"`
+}
+
+func NewMultiResolutionSampler(resolutions []time.Duration, windowCapacity int) *MultiResolutionSampler {
+	if windowCapacity <= 0 {
+		windowCapacity = DefaultResolutionWindowCapacity
+	}
+
+	sorted := make([]time.Duration, 0, len(resolutions))
+	seen := make(map[time.Duration]bool)
+	for _, resolution := range resolutions {
+		if resolution < time.Second || seen[resolution] {
+			continue
+		}
+		seen[resolution] = true
+		sorted = append(sorted, resolution)
+	}
+	if len(sorted) == 0 {
+		sorted = append(sorted, DefaultResolutionTiers...)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	mrs := &MultiResolutionSampler{tiers: make([]resolutionTier, len(sorted))}
+	for i, resolution := range sorted {
+		retention := tierRetention(resolution)
+		maxWindows := int(math.Ceil(float64(retention) / float64(resolution)))
+		mrs.tiers[i] = resolutionTier{
+			resolution: resolution,
+			retention:  retention,
+			sampler:    NewTimeBasedSampler(resolution, maxWindows, ReservoirConfig{Capacity: windowCapacity}),
+		}
+	}
+
+	return mrs
+}
+
+func tierRetention(resolution time.Duration) time.Duration {
+	switch {
+	case resolution < 5*time.Minute:
+		return time.Hour
+	case resolution < time.Hour:
+		return 24 * time.Hour
+	default:
+		return 30 * 24 * time.Hour
+	}
+}
+
+func (mrs *MultiResolutionSampler) Add(metric *metrics.MetricPoint) {
+	for _, tier := range mrs.tiers {
+		tier.sampler.Add(metric)
+	}
+}
+
+func (mrs *MultiResolutionSampler) Clear() {
+	for _, tier := range mrs.tiers {
+		tier.sampler.Clear()
+	}
+}
+
+func (mrs *MultiResolutionSampler) QueryAtResolution(timestamp time.Time, resolution time.Duration) []*metrics.MetricPoint {
+	return mrs.tierFor(timestamp, resolution).sampler.GetWindowSamples(timestamp)
+}
+
+func (mrs *MultiResolutionSampler) QueryRange(start, end time.Time, resolution time.Duration) []*metrics.MetricPoint {
+	return mrs.tierFor(start, resolution).sampler.GetRangeSamples(start, end)
+}
+
+func (mrs *MultiResolutionSampler) Finest() time.Duration {
+	return mrs.tiers[0].resolution
+}
+
+func (mrs *MultiResolutionSampler) Coarsest() time.Duration {
+	return mrs.tiers[len(mrs.tiers)-1].resolution
+}
+
+func (mrs *MultiResolutionSampler) Retention(resolution time.Duration) time.Duration {
+	return mrs.tierFor(time.Now(), resolution).retention
+}
+
+func (mrs *MultiResolutionSampler) Tiers() []ResolutionTierInfo {
+	info := make([]ResolutionTierInfo, len(mrs.tiers))
+	for i, tier := range mrs.tiers {
+		info[i] = ResolutionTierInfo{
+			Resolution: tier.resolution,
+			Retention:  tier.retention,
+			Windows:    tier.sampler.WindowCount(),
+		}
+	}
+	return info
+}
+
+func (mrs *MultiResolutionSampler) tierFor(timestamp time.Time, resolution time.Duration) resolutionTier {
+	age := time.Since(timestamp)
+	for _, tier := range mrs.tiers {
+		if tier.resolution >= resolution && age <= tier.retention {
+			return tier
+		}
+	}
+	return mrs.tiers[len(mrs.tiers)-1]
+}
@@ -3,6 +3,7 @@ package sampling
 import (
 	"math"
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
@@ -10,19 +11,21 @@ import (
 )
 
 type ReservoirSampler struct {
-	capacity int
-	samples  []*metrics.MetricPoint
-	count    uint64
-	mutex    sync.RWMutex
-	rng      *rand.Rand
+	capacity   int
+	samples    []*metrics.MetricPoint
+	count      uint64
+	lastUpdate time.Time
+	mutex      sync.RWMutex
+	rng        *rand.Rand
 }
 
 func NewReservoirSampler(capacity int) *ReservoirSampler {
 	return &ReservoirSampler{
-		capacity: capacity,
-		samples:  make([]*metrics.MetricPoint, 0, capacity),
-		count:    0,
-		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		capacity:   capacity,
+		samples:    make([]*metrics.MetricPoint, 0, capacity),
+		count:      0,
+		lastUpdate: time.Now(),
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -31,6 +34,7 @@ func (rs *ReservoirSampler) Add(metric *metrics.MetricPoint) *metrics.MetricPoin
 	defer rs.mutex.Unlock()
 
 	rs.count++
+	rs.lastUpdate = time.Now()
 
 	if len(rs.samples) < rs.capacity {
 		sample := *metric
@@ -76,6 +80,13 @@ func (rs *ReservoirSampler) Count() uint64 {
 	return rs.count
 }
 
+func (rs *ReservoirSampler) LastUpdate() time.Time {
+	rs.mutex.RLock()
+	defer rs.mutex.RUnlock()
+
+	return rs.lastUpdate
+}
+
 func (rs *ReservoirSampler) Clear() {
 	rs.mutex.Lock()
 	defer rs.mutex.Unlock()
@@ -85,8 +96,8 @@ func (rs *ReservoirSampler) Clear() {
 }
 
 func (rs *ReservoirSampler) GetRandomSample() *metrics.MetricPoint {
-	rs.mutex.RLock()
-	defer rs.mutex.RUnlock()
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
 
 	if len(rs.samples) == 0 {
 		return nil
@@ -268,15 +279,20 @@ func (tbs *TimeBasedSampler) Add(metric *metrics.MetricPoint) {
 
 	windowKey := metric.Timestamp.Unix() / int64(tbs.windowSize.Seconds())
 
-	if _, exists := tbs.windows[windowKey]; !exists {
-		tbs.windows[windowKey] = NewReservoirSampler(tbs.samplerConfig.Capacity)
+	reservoir, exists := tbs.windows[windowKey]
+	if !exists {
+		reservoir = NewReservoirSampler(tbs.samplerConfig.Capacity)
+		tbs.windows[windowKey] = reservoir
 
 		if len(tbs.windows) > tbs.maxWindows {
 			tbs.cleanupOldWindows()
 		}
+		if _, kept := tbs.windows[windowKey]; !kept {
+			return
+		}
 	}
 
-	tbs.windows[windowKey].Add(metric)
+	reservoir.Add(metric)
 }
 
 func (tbs *TimeBasedSampler) GetWindowSamples(timestamp time.Time) []*metrics.MetricPoint {
@@ -290,6 +306,48 @@ func (tbs *TimeBasedSampler) GetWindowSamples(timestamp time.Time) []*metrics.Me
 	return nil
 }
 
+func (tbs *TimeBasedSampler) GetRangeSamples(start, end time.Time) []*metrics.MetricPoint {
+	tbs.mutex.RLock()
+	defer tbs.mutex.RUnlock()
+
+	windowSeconds := int64(tbs.windowSize.Seconds())
+	first := start.Unix() / windowSeconds
+	last := end.Unix() / windowSeconds
+
+	keys := make([]int64, 0, len(tbs.windows))
+	for key := range tbs.windows {
+		if key >= first && key <= last {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	var result []*metrics.MetricPoint
+	for _, key := range keys {
+		for _, sample := range tbs.windows[key].GetSamples() {
+			if !sample.Timestamp.Before(start) && !sample.Timestamp.After(end) {
+				result = append(result, sample)
+			}
+		}
+	}
+
+	return result
+}
+
+func (tbs *TimeBasedSampler) Clear() {
+	tbs.mutex.Lock()
+	defer tbs.mutex.Unlock()
+
+	tbs.windows = make(map[int64]*ReservoirSampler)
+}
+
+func (tbs *TimeBasedSampler) WindowCount() int {
+	tbs.mutex.RLock()
+	defer tbs.mutex.RUnlock()
+
+	return len(tbs.windows)
+}
+
 func (tbs *TimeBasedSampler) GetRecentSamples(numWindows int) []*metrics.MetricPoint {
 	tbs.mutex.RLock()
 	defer tbs.mutex.RUnlock()
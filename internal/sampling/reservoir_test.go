@@ -0,0 +1,69 @@
+package sampling
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+func TestReservoirSamplerConcurrentGetRandomSample(t *testing.T) {
+	rs := NewReservoirSampler(100)
+	for i := 0; i < 100; i++ {
+		rs.Add(&metrics.MetricPoint{
+			Timestamp:  time.Now(),
+			PodName:    fmt.Sprintf("pod-%d", i),
+			MetricName: "cpu_usage",
+			Value:      float64(i),
+		})
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				sample := rs.GetRandomSample()
+				if sample == nil {
+					t.Error("GetRandomSample() returned nil from a full reservoir")
+					return
+				}
+				if sample.Value < 0 || sample.Value >= 100 {
+					t.Errorf("GetRandomSample() value = %v, want a value from the reservoir", sample.Value)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if rs.Size() != 100 {
+		t.Fatalf("reservoir size = %d after concurrent reads, want 100", rs.Size())
+	}
+}
+
+func TestReservoirSamplerGetRandomSampleEmpty(t *testing.T) {
+	if sample := NewReservoirSampler(10).GetRandomSample(); sample != nil {
+		t.Fatalf("GetRandomSample() on empty reservoir = %+v, want nil", sample)
+	}
+}
+
+func TestTimeBasedSamplerDropsMetricsOlderThanRetainedWindows(t *testing.T) {
+	tbs := NewTimeBasedSampler(time.Minute, 3, ReservoirConfig{Capacity: 10})
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		tbs.Add(&metrics.MetricPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: float64(i)})
+	}
+	tbs.Add(&metrics.MetricPoint{Timestamp: start, Value: -1})
+
+	if samples := tbs.GetWindowSamples(start); len(samples) != 0 {
+		t.Fatalf("evicted window holds %d samples, want 0", len(samples))
+	}
+	if windows := tbs.WindowCount(); windows > 3 {
+		t.Fatalf("WindowCount() = %d, want at most 3", windows)
+	}
+}
@@ -0,0 +1,100 @@
+package sampling
+
+import "math"
+
+const (
+	chiSquaredMinExpected = 5
+	gammaMaxIterations    = 200
+	gammaEpsilon          = 1e-12
+	gammaTiny             = 1e-300
+)
+
+type ChiSquaredResult struct {
+	Statistic        float64 `json:"chi_squared"`
+	DegreesOfFreedom int     `json:"degrees_of_freedom"`
+	PValue           float64 `json:"p_value"`
+}
+
+func ChiSquaredUniform(values []float64, lower, upper float64, bins int) ChiSquaredResult {
+	if bins > len(values)/chiSquaredMinExpected {
+		bins = len(values) / chiSquaredMinExpected
+	}
+	if bins < 2 || upper <= lower {
+		return ChiSquaredResult{PValue: 1}
+	}
+
+	observed := make([]float64, bins)
+	width := (upper - lower) / float64(bins)
+	for _, value := range values {
+		bucket := int((value - lower) / width)
+		if bucket < 0 {
+			bucket = 0
+		}
+		if bucket >= bins {
+			bucket = bins - 1
+		}
+		observed[bucket]++
+	}
+
+	expected := float64(len(values)) / float64(bins)
+	statistic := 0.0
+	for _, count := range observed {
+		diff := count - expected
+		statistic += diff * diff / expected
+	}
+
+	return ChiSquaredResult{
+		Statistic:        statistic,
+		DegreesOfFreedom: bins - 1,
+		PValue:           ChiSquaredPValue(statistic, bins-1),
+	}
+}
+
+func ChiSquaredPValue(statistic float64, degreesOfFreedom int) float64 {
+	if degreesOfFreedom <= 0 || statistic <= 0 {
+		return 1
+	}
+	return upperIncompleteGamma(float64(degreesOfFreedom)/2, statistic/2)
+}
+
+func upperIncompleteGamma(a, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+
+	if x < a+1 {
+		sum := 1 / a
+		term := sum
+		for n := 1; n < gammaMaxIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*gammaEpsilon {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*prefix)
+	}
+
+	b := x + 1 - a
+	c := 1 / gammaTiny
+	d := 1 / b
+	h := d
+	for i := 1; i < gammaMaxIterations; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < gammaTiny {
+			d = gammaTiny
+		}
+		c = b + an/c
+		if math.Abs(c) < gammaTiny {
+			c = gammaTiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < gammaEpsilon {
+			break
+		}
+	}
+	return math.Min(1, h*prefix)
+}
@@ -0,0 +1,94 @@
+package sampling
+
+import (
+	"strings"
+	"time"
+)
+
+type Trend struct {
+	RatePerHour  float64   `json:"rate_per_hour"`
+	CurrentValue float64   `json:"current_value"`
+	RSquared     float64   `json:"r_squared"`
+	Points       int       `json:"points"`
+	LastSeen     time.Time `json:"last_seen"`
+}
+
+func FitLinearTrend(timestamps []time.Time, values []float64) Trend {
+	n := len(values)
+	if n == 0 || len(timestamps) != n {
+		return Trend{}
+	}
+
+	origin := timestamps[0]
+	last := timestamps[0]
+	for _, ts := range timestamps {
+		if ts.Before(origin) {
+			origin = ts
+		}
+		if ts.After(last) {
+			last = ts
+		}
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i, value := range values {
+		x := timestamps[i].Sub(origin).Hours()
+		sumX += x
+		sumY += value
+		sumXY += x * value
+		sumXX += x * x
+	}
+
+	fn := float64(n)
+	meanY := sumY / fn
+	denominator := fn*sumXX - sumX*sumX
+
+	if n < 2 || denominator == 0 {
+		return Trend{CurrentValue: meanY, Points: n, LastSeen: last}
+	}
+
+	slope := (fn*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / fn
+
+	var ssTot, ssRes float64
+	for i, value := range values {
+		x := timestamps[i].Sub(origin).Hours()
+		predicted := intercept + slope*x
+		ssRes += (value - predicted) * (value - predicted)
+		ssTot += (value - meanY) * (value - meanY)
+	}
+
+	rSquared := 0.0
+	if ssTot > 0 {
+		rSquared = 1 - ssRes/ssTot
+	}
+
+	return Trend{
+		RatePerHour:  slope,
+		CurrentValue: intercept + slope*last.Sub(origin).Hours(),
+		RSquared:     rSquared,
+		Points:       n,
+		LastSeen:     last,
+	}
+}
+
+func (as *AdaptiveSampler) LinearTrend(metricName string) Trend {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	var timestamps []time.Time
+	var values []float64
+
+	for stratum, stats := range as.statistics {
+		if !strings.HasSuffix(stratum, "/"+metricName) {
+			continue
+		}
+
+		stats.mutex.RLock()
+		timestamps = append(timestamps, stats.timestamps...)
+		values = append(values, stats.values...)
+		stats.mutex.RUnlock()
+	}
+
+	return FitLinearTrend(timestamps, values)
+}
@@ -0,0 +1,227 @@
+package slo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/engine"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+type SLO struct {
+	Name          string        `json:"name"`
+	MetricName    string        `json:"metric_name"`
+	Percentile    float64       `json:"percentile"`
+	GoodThreshold float64       `json:"good_threshold"`
+	Target        float64       `json:"target"`
+	Window        time.Duration `json:"window"`
+}
+
+type ErrorBudget struct {
+	Name             string    `json:"name"`
+	ConsumedPercent  float64   `json:"consumed_percent"`
+	RemainingMinutes float64   `json:"remaining_minutes"`
+	BadMinutes       float64   `json:"bad_minutes"`
+	Evaluations      int       `json:"evaluations"`
+	OnTrack          bool      `json:"on_track"`
+	LastEvaluated    time.Time `json:"last_evaluated"`
+}
+
+type evaluation struct {
+	timestamp time.Time
+	good      bool
+}
+
+type trackedSLO struct {
+	definition  SLO
+	evaluations []evaluation
+	startedAt   time.Time
+}
+
+type SLOTracker struct {
+	queryEngine *engine.QueryEngine
+	interval    time.Duration
+	slos        map[string]*trackedSLO
+	mutex       sync.RWMutex
+}
+
+func NewSLOTracker(queryEngine *engine.QueryEngine, interval time.Duration) *SLOTracker {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &SLOTracker{
+		queryEngine: queryEngine,
+		interval:    interval,
+		slos:        make(map[string]*trackedSLO),
+	}
+}
+
+func (t *SLOTracker) Register(slo SLO) error {
+	if slo.Name == "" {
+		return fmt.Errorf("slo name is required")
+	}
+	if slo.MetricName == "" {
+		return fmt.Errorf("slo %s: metric_name is required", slo.Name)
+	}
+	if slo.Target <= 0 || slo.Target >= 1 {
+		return fmt.Errorf("slo %s: target must be between 0 and 1", slo.Name)
+	}
+	if slo.Window <= 0 {
+		return fmt.Errorf("slo %s: window must be positive", slo.Name)
+	}
+	if slo.Percentile <= 0 || slo.Percentile > 100 {
+		slo.Percentile = 95
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.slos[slo.Name] = &trackedSLO{
+		definition: slo,
+		startedAt:  time.Now(),
+	}
+	return nil
+}
+
+func (t *SLOTracker) Start(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.evaluateAll(time.Now())
+		}
+	}
+}
+
+func (t *SLOTracker) List() []SLO {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	result := make([]SLO, 0, len(t.slos))
+	for _, tracked := range t.slos {
+		result = append(result, tracked.definition)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func (t *SLOTracker) Budget(name string) (*ErrorBudget, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	tracked, exists := t.slos[name]
+	if !exists {
+		return nil, fmt.Errorf("slo not found: %s", name)
+	}
+
+	return t.calculateBudget(tracked, time.Now()), nil
+}
+
+func (t *SLOTracker) evaluateAll(now time.Time) {
+	t.mutex.RLock()
+	definitions := make([]SLO, 0, len(t.slos))
+	for _, tracked := range t.slos {
+		definitions = append(definitions, tracked.definition)
+	}
+	t.mutex.RUnlock()
+
+	for _, definition := range definitions {
+		good, ok := t.evaluate(definition)
+		if !ok {
+			continue
+		}
+
+		t.mutex.Lock()
+		if tracked, exists := t.slos[definition.Name]; exists {
+			tracked.evaluations = append(tracked.evaluations, evaluation{timestamp: now, good: good})
+			tracked.evaluations = trimEvaluations(tracked.evaluations, now.Add(-definition.Window))
+		}
+		t.mutex.Unlock()
+	}
+}
+
+func (t *SLOTracker) evaluate(definition SLO) (bool, bool) {
+	request := &metrics.QueryRequest{
+		ID:        fmt.Sprintf("slo_%s_%d", definition.Name, time.Now().UnixNano()),
+		Query:     fmt.Sprintf("PERCENTILE(%g) %s", definition.Percentile, definition.MetricName),
+		QueryType: metrics.Percentile,
+		Filters:   map[string]string{"metric_name": definition.MetricName},
+		TimeRange: metrics.TimeRange{Start: time.Now().Add(-t.interval)},
+	}
+
+	result, err := t.queryEngine.ExecuteQuery(request)
+	if err != nil {
+		slog.Warn("SLO evaluation failed", "slo", definition.Name, "error", err)
+		return false, false
+	}
+
+	switch value := result.Result.(type) {
+	case *metrics.PercentileResult:
+		return value.Value < definition.GoodThreshold, true
+	case *metrics.HistogramResult:
+		return value.Value < definition.GoodThreshold, true
+	default:
+		return false, false
+	}
+}
+
+func (t *SLOTracker) calculateBudget(tracked *trackedSLO, now time.Time) *ErrorBudget {
+	definition := tracked.definition
+	windowMinutes := definition.Window.Minutes()
+	totalBudget := (1 - definition.Target) * windowMinutes
+
+	badMinutes := 0.0
+	var lastEvaluated time.Time
+	for _, eval := range tracked.evaluations {
+		if !eval.good {
+			badMinutes += t.interval.Minutes()
+		}
+		lastEvaluated = eval.timestamp
+	}
+
+	consumedPercent := 100.0
+	if totalBudget > 0 {
+		consumedPercent = badMinutes / totalBudget * 100
+	}
+
+	elapsed := now.Sub(tracked.startedAt)
+	if elapsed > definition.Window {
+		elapsed = definition.Window
+	}
+	elapsedPercent := elapsed.Minutes() / windowMinutes * 100
+
+	remaining := totalBudget - badMinutes
+
+	return &ErrorBudget{
+		Name:             definition.Name,
+		ConsumedPercent:  consumedPercent,
+		RemainingMinutes: remaining,
+		BadMinutes:       badMinutes,
+		Evaluations:      len(tracked.evaluations),
+		OnTrack:          remaining > 0 && consumedPercent <= elapsedPercent,
+		LastEvaluated:    lastEvaluated,
+	}
+}
+
+func trimEvaluations(evaluations []evaluation, cutoff time.Time) []evaluation {
+	keepFrom := len(evaluations)
+	for i, eval := range evaluations {
+		if eval.timestamp.After(cutoff) {
+			keepFrom = i
+			break
+		}
+	}
+	return evaluations[keepFrom:]
+}
@@ -0,0 +1,190 @@
+package stream
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+type MetricAlias struct {
+	From []string `json:"from"`
+	To   string   `json:"to"`
+}
+
+type MetricFanOut struct {
+	From string   `json:"from"`
+	To   []string `json:"to"`
+}
+
+type MetricAliases struct {
+	Aliases []MetricAlias  `json:"aliases"`
+	FanOuts []MetricFanOut `json:"fan_outs"`
+}
+
+func (p *Processor) GetMetricAliases() MetricAliases {
+	p.aliasMutex.RLock()
+	defer p.aliasMutex.RUnlock()
+
+	return MetricAliases{
+		Aliases: append([]MetricAlias{}, p.metricAliases...),
+		FanOuts: append([]MetricFanOut{}, p.metricFanOuts...),
+	}
+}
+
+func (p *Processor) SetMetricAlias(alias MetricAlias) error {
+	if err := validateMetricAlias(alias); err != nil {
+		return err
+	}
+
+	p.aliasMutex.Lock()
+	p.metricAliases = replaceAlias(p.metricAliases, alias)
+	p.aliasMutex.Unlock()
+
+	slog.Info("Metric alias updated", "from", alias.From, "to", alias.To)
+	return nil
+}
+
+func (p *Processor) SetMetricFanOut(fanOut MetricFanOut) error {
+	if err := validateMetricFanOut(fanOut); err != nil {
+		return err
+	}
+
+	p.aliasMutex.Lock()
+	p.metricFanOuts = replaceFanOut(p.metricFanOuts, fanOut)
+	p.aliasMutex.Unlock()
+
+	slog.Info("Metric fan-out updated", "from", fanOut.From, "to", fanOut.To)
+	return nil
+}
+
+func (p *Processor) RemoveMetricAliases(metricName string) bool {
+	p.aliasMutex.Lock()
+	defer p.aliasMutex.Unlock()
+
+	removed := false
+
+	aliases := p.metricAliases[:0]
+	for _, alias := range p.metricAliases {
+		if alias.To == metricName {
+			removed = true
+			continue
+		}
+		aliases = append(aliases, alias)
+	}
+	p.metricAliases = aliases
+
+	fanOuts := p.metricFanOuts[:0]
+	for _, fanOut := range p.metricFanOuts {
+		if fanOut.From == metricName {
+			removed = true
+			continue
+		}
+		fanOuts = append(fanOuts, fanOut)
+	}
+	p.metricFanOuts = fanOuts
+
+	if removed {
+		slog.Info("Metric aliases removed", "metric_name", metricName)
+	}
+	return removed
+}
+
+func (p *Processor) resolveAlias(metric *metrics.MetricPoint) {
+	p.aliasMutex.RLock()
+	defer p.aliasMutex.RUnlock()
+
+	for _, alias := range p.metricAliases {
+		for _, from := range alias.From {
+			if metric.MetricName == from {
+				metric.MetricName = alias.To
+				break
+			}
+		}
+	}
+}
+
+func (p *Processor) fanOut(metric *metrics.MetricPoint) []*metrics.MetricPoint {
+	p.aliasMutex.RLock()
+	defer p.aliasMutex.RUnlock()
+
+	expanded := []*metrics.MetricPoint{metric}
+	for _, fanOut := range p.metricFanOuts {
+		for _, source := range expanded {
+			if source.MetricName != fanOut.From {
+				continue
+			}
+			for _, name := range fanOut.To {
+				if name == source.MetricName {
+					continue
+				}
+				expanded = append(expanded, copyMetricAs(source, name))
+			}
+		}
+	}
+	return expanded
+}
+
+func copyMetricAs(metric *metrics.MetricPoint, metricName string) *metrics.MetricPoint {
+	copied := *metric
+	copied.MetricName = metricName
+	if metric.Labels != nil {
+		copied.Labels = make(map[string]string, len(metric.Labels))
+		for key, value := range metric.Labels {
+			copied.Labels[key] = value
+		}
+	}
+	return &copied
+}
+
+func replaceAlias(aliases []MetricAlias, alias MetricAlias) []MetricAlias {
+	alias.From = append([]string{}, alias.From...)
+	for i, existing := range aliases {
+		if existing.To == alias.To {
+			aliases[i] = alias
+			return aliases
+		}
+	}
+	return append(aliases, alias)
+}
+
+func replaceFanOut(fanOuts []MetricFanOut, fanOut MetricFanOut) []MetricFanOut {
+	fanOut.To = append([]string{}, fanOut.To...)
+	for i, existing := range fanOuts {
+		if existing.From == fanOut.From {
+			fanOuts[i] = fanOut
+			return fanOuts
+		}
+	}
+	return append(fanOuts, fanOut)
+}
+
+func validateMetricAlias(alias MetricAlias) error {
+	if alias.To == "" {
+		return fmt.Errorf("metric alias requires a target metric name")
+	}
+	if len(alias.From) == 0 {
+		return fmt.Errorf("metric alias %s requires at least one source metric name", alias.To)
+	}
+	for _, from := range alias.From {
+		if from == "" || from == alias.To {
+			return fmt.Errorf("invalid source metric name %q for alias %s", from, alias.To)
+		}
+	}
+	return nil
+}
+
+func validateMetricFanOut(fanOut MetricFanOut) error {
+	if fanOut.From == "" {
+		return fmt.Errorf("metric fan-out requires a source metric name")
+	}
+	if len(fanOut.To) == 0 {
+		return fmt.Errorf("metric fan-out %s requires at least one target metric name", fanOut.From)
+	}
+	for _, to := range fanOut.To {
+		if to == "" {
+			return fmt.Errorf("invalid target metric name %q for fan-out %s", to, fanOut.From)
+		}
+	}
+	return nil
+}
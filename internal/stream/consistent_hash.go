@@ -0,0 +1,95 @@
+package stream
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+const DefaultVirtualNodes = 150
+
+type ConsistentHashRouter struct {
+	virtualNodes int
+	shards       int
+	ring         []ringNode
+	mutex        sync.RWMutex
+}
+
+type ringNode struct {
+	hash  uint64
+	shard int
+}
+
+func NewConsistentHashRouter(shards, virtualNodes int) *ConsistentHashRouter {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+
+	router := &ConsistentHashRouter{virtualNodes: virtualNodes}
+	router.Resize(shards)
+	return router
+}
+
+func (chr *ConsistentHashRouter) Resize(shards int) {
+	if shards < 0 {
+		shards = 0
+	}
+
+	ring := make([]ringNode, 0, shards*chr.virtualNodes)
+	for shard := 0; shard < shards; shard++ {
+		for replica := 0; replica < chr.virtualNodes; replica++ {
+			ring = append(ring, ringNode{
+				hash:  hashKey(strconv.Itoa(shard) + "#" + strconv.Itoa(replica)),
+				shard: shard,
+			})
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool {
+		return ring[i].hash < ring[j].hash
+	})
+
+	chr.mutex.Lock()
+	defer chr.mutex.Unlock()
+
+	chr.shards = shards
+	chr.ring = ring
+}
+
+func (chr *ConsistentHashRouter) Shards() int {
+	chr.mutex.RLock()
+	defer chr.mutex.RUnlock()
+
+	return chr.shards
+}
+
+func (chr *ConsistentHashRouter) Route(key string) int {
+	chr.mutex.RLock()
+	defer chr.mutex.RUnlock()
+
+	if len(chr.ring) == 0 {
+		return 0
+	}
+
+	hash := hashKey(key)
+	idx := sort.Search(len(chr.ring), func(i int) bool {
+		return chr.ring[i].hash >= hash
+	})
+	if idx == len(chr.ring) {
+		idx = 0
+	}
+	return chr.ring[idx].shard
+}
+
+func hashKey(key string) uint64 {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(key))
+
+	h := hasher.Sum64()
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
@@ -0,0 +1,142 @@
+package stream
+
+import (
+	"fmt"
+	"testing"
+)
+
+const (
+	spreadShards = 8
+	spreadKeys   = 100_000
+)
+
+func routerTestKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("cluster-%d/ns-%d/pod-%d/main/cpu_usage", i%3, i%17, i)
+	}
+	return keys
+}
+
+// shardLoads routes every key and returns the number of keys per shard.
+func shardLoads(router *ConsistentHashRouter, keys []string) []int {
+	loads := make([]int, router.Shards())
+	for _, key := range keys {
+		loads[router.Route(key)]++
+	}
+	return loads
+}
+
+func TestConsistentHashRouterSpread(t *testing.T) {
+	keys := routerTestKeys(spreadKeys)
+
+	tests := []struct {
+		name         string
+		virtualNodes int
+		maxDeviation float64
+	}{
+		{name: "default virtual nodes", virtualNodes: 0, maxDeviation: 0.20},
+		{name: "more virtual nodes", virtualNodes: 1000, maxDeviation: 0.10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loads := shardLoads(NewConsistentHashRouter(spreadShards, tt.virtualNodes), keys)
+
+			mean := float64(spreadKeys) / spreadShards
+			for shard, load := range loads {
+				if deviation := (float64(load) - mean) / mean; deviation > tt.maxDeviation || deviation < -tt.maxDeviation {
+					t.Errorf("shard %d got %d keys (%+.1f%% from the mean of %.0f), want within ±%.0f%%",
+						shard, load, 100*deviation, mean, 100*tt.maxDeviation)
+				}
+			}
+		})
+	}
+}
+
+func TestConsistentHashRouterResizeMovesFewKeys(t *testing.T) {
+	keys := routerTestKeys(spreadKeys)
+	router := NewConsistentHashRouter(spreadShards, 0)
+
+	before := make([]int, len(keys))
+	for i, key := range keys {
+		before[i] = router.Route(key)
+	}
+
+	router.Resize(spreadShards + 1)
+
+	moved := 0
+	for i, key := range keys {
+		after := router.Route(key)
+		if after == before[i] {
+			continue
+		}
+		if after != spreadShards {
+			t.Fatalf("key %q moved from shard %d to existing shard %d, want only moves to the new shard", key, before[i], after)
+		}
+		moved++
+	}
+
+	// Ideally 1/9 of the keys move to the new shard.
+	if ratio := float64(moved) / float64(len(keys)); ratio < 0.05 || ratio > 0.20 {
+		t.Errorf("%.1f%% of keys moved after adding a shard, want about %.1f%%", 100*ratio, 100.0/(spreadShards+1))
+	}
+}
+
+func TestConsistentHashRouterEdgeCases(t *testing.T) {
+	empty := NewConsistentHashRouter(0, 0)
+	if got := empty.Route("key"); got != 0 {
+		t.Errorf("Route() on an empty ring = %d, want 0", got)
+	}
+
+	single := NewConsistentHashRouter(1, 0)
+	for _, key := range routerTestKeys(100) {
+		if got := single.Route(key); got != 0 {
+			t.Fatalf("Route(%q) with one shard = %d, want 0", key, got)
+		}
+	}
+
+	router := NewConsistentHashRouter(spreadShards, 0)
+	if first, second := router.Route("stable-key"), router.Route("stable-key"); first != second {
+		t.Errorf("Route() is not deterministic: %d then %d", first, second)
+	}
+}
+
+func BenchmarkConsistentHashRoute(b *testing.B) {
+	keys := routerTestKeys(spreadKeys)
+	router := NewConsistentHashRouter(spreadShards, 0)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		router.Route(keys[i%len(keys)])
+	}
+	b.StopTimer()
+
+	// Report how evenly the keys spread so regressions in the ring layout
+	// show up next to the routing cost.
+	loads := shardLoads(router, keys)
+	minLoad, maxLoad := loads[0], loads[0]
+	for _, load := range loads {
+		minLoad = min(minLoad, load)
+		maxLoad = max(maxLoad, load)
+	}
+	mean := float64(spreadKeys) / spreadShards
+	b.ReportMetric(float64(maxLoad)/mean, "max/mean")
+	b.ReportMetric(float64(minLoad)/mean, "min/mean")
+}
+
+func BenchmarkConsistentHashRouteParallel(b *testing.B) {
+	keys := routerTestKeys(spreadKeys)
+	router := NewConsistentHashRouter(spreadShards, 0)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			router.Route(keys[i%len(keys)])
+			i++
+		}
+	})
+}
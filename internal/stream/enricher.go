@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+type LabelEnricher struct {
+	namespaceLabels map[string]map[string]string
+	mutex           sync.RWMutex
+}
+
+func NewLabelEnricher(namespaceLabels map[string]map[string]string) *LabelEnricher {
+	return &LabelEnricher{
+		namespaceLabels: copyNamespaceLabels(namespaceLabels),
+	}
+}
+
+func LoadNamespaceLabels(path string) (map[string]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read namespace labels file: %v", err)
+	}
+
+	var namespaceLabels map[string]map[string]string
+	if err := yaml.Unmarshal(data, &namespaceLabels); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace labels file: %v", err)
+	}
+
+	return namespaceLabels, nil
+}
+
+func (le *LabelEnricher) Enrich(metric *metrics.MetricPoint) {
+	le.mutex.RLock()
+	defer le.mutex.RUnlock()
+
+	inherited, exists := le.namespaceLabels[metric.Namespace]
+	if !exists || len(inherited) == 0 {
+		return
+	}
+
+	if metric.Labels == nil {
+		metric.Labels = make(map[string]string, len(inherited))
+	}
+	for key, value := range inherited {
+		if _, exists := metric.Labels[key]; !exists {
+			metric.Labels[key] = value
+		}
+	}
+}
+
+func (le *LabelEnricher) NamespaceLabels() map[string]map[string]string {
+	le.mutex.RLock()
+	defer le.mutex.RUnlock()
+
+	return copyNamespaceLabels(le.namespaceLabels)
+}
+
+func (le *LabelEnricher) SetNamespaceLabels(namespaceLabels map[string]map[string]string) {
+	copied := copyNamespaceLabels(namespaceLabels)
+
+	le.mutex.Lock()
+	defer le.mutex.Unlock()
+
+	le.namespaceLabels = copied
+}
+
+func copyNamespaceLabels(namespaceLabels map[string]map[string]string) map[string]map[string]string {
+	copied := make(map[string]map[string]string, len(namespaceLabels))
+	for namespace, labels := range namespaceLabels {
+		copied[namespace] = make(map[string]string, len(labels))
+		for key, value := range labels {
+			copied[namespace][key] = value
+		}
+	}
+	return copied
+}
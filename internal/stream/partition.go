@@ -0,0 +1,134 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type PartitionConsumer struct {
+	topic     string
+	kind      string
+	partition int
+	reader    *kafka.Reader
+	processed uint64
+	errors    uint64
+	lastRead  atomic.Value
+
+	clusterMutex    sync.Mutex
+	clusterMessages map[string]uint64
+}
+
+type PartitionStats struct {
+	Topic             string    `json:"topic"`
+	Partition         int       `json:"partition"`
+	Offset            int64     `json:"offset"`
+	Lag               int64     `json:"lag"`
+	MessagesProcessed uint64    `json:"messages_processed"`
+	ProcessingErrors  uint64    `json:"processing_errors"`
+	LastReadTime      time.Time `json:"last_read_time"`
+
+	ClusterMessages map[string]uint64 `json:"cluster_messages,omitempty"`
+}
+
+func NewPartitionConsumer(brokers []string, topic, kind string, partition int, startOffset int64) (*PartitionConsumer, error) {
+	if partition < 0 {
+		return nil, fmt.Errorf("invalid partition %d for topic %s", partition, topic)
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   brokers,
+		Topic:     topic,
+		Partition: partition,
+		MinBytes:  10e3,
+		MaxBytes:  10e6,
+	})
+
+	if err := reader.SetOffset(startOffset); err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("failed to set offset for %s/%d: %v", topic, partition, err)
+	}
+
+	return &PartitionConsumer{
+		topic:           topic,
+		kind:            kind,
+		partition:       partition,
+		reader:          reader,
+		clusterMessages: make(map[string]uint64),
+	}, nil
+}
+
+func (pc *PartitionConsumer) Run(ctx context.Context, handle func(kind string, message kafka.Message) error) error {
+	slog.Info("Starting partition consumer", "topic", pc.topic, "partition", pc.partition)
+	defer pc.reader.Close()
+
+	for {
+		message, err := pc.reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			slog.Error("Error reading from partition", "topic", pc.topic, "partition", pc.partition, "error", err)
+			atomic.AddUint64(&pc.errors, 1)
+			continue
+		}
+
+		pc.lastRead.Store(time.Now())
+		pc.recordCluster(message.Key)
+
+		if err := handle(pc.kind, message); err != nil {
+			slog.Warn("Error processing message",
+				"topic", pc.topic,
+				"partition", pc.partition,
+				"offset", message.Offset,
+				"error", err)
+			atomic.AddUint64(&pc.errors, 1)
+			continue
+		}
+
+		atomic.AddUint64(&pc.processed, 1)
+	}
+}
+
+func (pc *PartitionConsumer) Stats() PartitionStats {
+	stats := PartitionStats{
+		Topic:             pc.topic,
+		Partition:         pc.partition,
+		Offset:            pc.reader.Offset(),
+		Lag:               pc.reader.Lag(),
+		MessagesProcessed: atomic.LoadUint64(&pc.processed),
+		ProcessingErrors:  atomic.LoadUint64(&pc.errors),
+	}
+	if lastRead, ok := pc.lastRead.Load().(time.Time); ok {
+		stats.LastReadTime = lastRead
+	}
+
+	pc.clusterMutex.Lock()
+	if len(pc.clusterMessages) > 0 {
+		stats.ClusterMessages = make(map[string]uint64, len(pc.clusterMessages))
+		for cluster, count := range pc.clusterMessages {
+			stats.ClusterMessages[cluster] = count
+		}
+	}
+	pc.clusterMutex.Unlock()
+
+	return stats
+}
+
+func (pc *PartitionConsumer) recordCluster(key []byte) {
+	if len(key) == 0 {
+		return
+	}
+
+	cluster, _, _ := strings.Cut(string(key), "/")
+
+	pc.clusterMutex.Lock()
+	pc.clusterMessages[cluster]++
+	pc.clusterMutex.Unlock()
+}
@@ -0,0 +1,166 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	PreAggregationBucket = time.Second
+
+	preAggregatedLabel = "_pre_aggregated"
+)
+
+type PreAggregate struct {
+	Point *metrics.MetricPoint
+	Min   float64
+	Max   float64
+	Sum   float64
+	Count int
+}
+
+type PreAggregator struct {
+	patterns []string
+	bucket   time.Duration
+	buckets  map[string]*preAggBucket
+	mutex    sync.Mutex
+}
+
+type preAggBucket struct {
+	start    time.Time
+	template metrics.MetricPoint
+	min      float64
+	max      float64
+	sum      float64
+	count    int
+	emit     func(PreAggregate)
+}
+
+func NewPreAggregator(patterns []string, bucket time.Duration) (*PreAggregator, error) {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pre-aggregation pattern %q: %v", pattern, err)
+		}
+	}
+	if bucket <= 0 {
+		bucket = PreAggregationBucket
+	}
+
+	return &PreAggregator{
+		patterns: append([]string{}, patterns...),
+		bucket:   bucket,
+		buckets:  make(map[string]*preAggBucket),
+	}, nil
+}
+
+func (pa *PreAggregator) Matches(metricName string) bool {
+	return matchesAnyPattern(pa.patterns, metricName)
+}
+
+func (pa *PreAggregator) Add(metric *metrics.MetricPoint, emit func(PreAggregate)) {
+	key := preAggregationKey(metric)
+	start := metric.Timestamp.Truncate(pa.bucket)
+
+	pa.mutex.Lock()
+	current, exists := pa.buckets[key]
+	var flushed *preAggBucket
+	if exists && !current.start.Equal(start) {
+		flushed = current
+		exists = false
+	}
+	if !exists {
+		current = &preAggBucket{
+			start:    start,
+			template: *metric,
+			min:      metric.Value,
+			max:      metric.Value,
+			emit:     emit,
+		}
+		pa.buckets[key] = current
+	}
+	current.min = min(current.min, metric.Value)
+	current.max = max(current.max, metric.Value)
+	current.sum += metric.Value
+	current.count++
+	pa.mutex.Unlock()
+
+	if flushed != nil {
+		flushed.emit(flushed.aggregate())
+	}
+}
+
+func (pa *PreAggregator) Flush(now time.Time) int {
+	pa.mutex.Lock()
+	var ready []*preAggBucket
+	for key, bucket := range pa.buckets {
+		if now.Before(bucket.start.Add(pa.bucket)) {
+			continue
+		}
+		ready = append(ready, bucket)
+		delete(pa.buckets, key)
+	}
+	pa.mutex.Unlock()
+
+	for _, bucket := range ready {
+		bucket.emit(bucket.aggregate())
+	}
+	return len(ready)
+}
+
+func (pa *PreAggregator) FlushAll() int {
+	pa.mutex.Lock()
+	ready := make([]*preAggBucket, 0, len(pa.buckets))
+	for _, bucket := range pa.buckets {
+		ready = append(ready, bucket)
+	}
+	pa.buckets = make(map[string]*preAggBucket)
+	pa.mutex.Unlock()
+
+	for _, bucket := range ready {
+		bucket.emit(bucket.aggregate())
+	}
+	return len(ready)
+}
+
+func (pa *PreAggregator) Run(ctx context.Context) {
+	ticker := time.NewTicker(pa.bucket)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			pa.FlushAll()
+			return
+		case now := <-ticker.C:
+			pa.Flush(now)
+		}
+	}
+}
+
+func (bucket *preAggBucket) aggregate() PreAggregate {
+	point := copyMetricAs(&bucket.template, bucket.template.MetricName)
+	point.Timestamp = bucket.start
+	point.Value = bucket.sum / float64(bucket.count)
+	if point.Labels == nil {
+		point.Labels = make(map[string]string)
+	}
+	point.Labels[preAggregatedLabel] = "true"
+
+	return PreAggregate{
+		Point: point,
+		Min:   bucket.min,
+		Max:   bucket.max,
+		Sum:   bucket.sum,
+		Count: bucket.count,
+	}
+}
+
+func preAggregationKey(metric *metrics.MetricPoint) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s",
+		metric.ClusterID, metric.Namespace, metric.PodName, metric.ContainerName, metric.MetricName)
+}
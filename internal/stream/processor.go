@@ -3,22 +3,52 @@ package stream
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
 	"math/rand"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/segmentio/kafka-go"
 
 	"github.com/asmit27rai/kubesight/internal/engine"
+	"github.com/asmit27rai/kubesight/internal/sampling"
 	"github.com/asmit27rai/kubesight/pkg/metrics"
 )
 
 type Processor struct {
-	config      ProcessorConfig
-	readers     map[string]*kafka.Reader
-	queryEngine *engine.QueryEngine
-	stats       ProcessorStats
+	config        ProcessorConfig
+	readers       map[string]*kafka.Reader
+	routedReaders map[string]*kafka.Reader
+	partitions    []*PartitionConsumer
+	groups        []*consumerGroup
+	queryEngine   *engine.QueryEngine
+	validator     *MetricValidator
+	enricher      *LabelEnricher
+	stats         ProcessorStats
+
+	validationMutex sync.Mutex
+
+	metricFilters MetricFilters
+	filterMutex   sync.RWMutex
+
+	normalizationRules []NormalizationRule
+	normalizationMutex sync.RWMutex
+
+	categoryRules map[string]string
+
+	metricAliases []MetricAlias
+	metricFanOuts []MetricFanOut
+	aliasMutex    sync.RWMutex
+
+	preAggregator *PreAggregator
+
+	topicCounters sync.Map
 }
 
 type ProcessorConfig struct {
@@ -27,8 +57,63 @@ type ProcessorConfig struct {
 	QueryEngine  *engine.QueryEngine
 	BatchSize    int
 	BatchTimeout time.Duration
+
+	PartitionAssignment  map[string][]int
+	PartitionStartOffset string
+
+	Router RouterConfig
+
+	AllowedUnits        []string
+	AllowNegativeValues []string
+
+	MetricAllowlist []string
+	MetricBlocklist []string
+
+	NamespaceLabels map[string]map[string]string
+
+	NormalizationRules []NormalizationRule
+
+	CategoryRules map[string]string
+
+	MetricAliases []MetricAlias
+	MetricFanOuts []MetricFanOut
+
+	PreAggregateMetrics []string
+
+	ConsumerGroups   []ConsumerGroupConfig
+	SamplingDefaults sampling.SamplingConfig
+}
+
+type ConsumerGroupConfig struct {
+	GroupID       string
+	Topic         string
+	SamplingRate  float64
+	ReservoirSize int
+}
+
+type MetricFilters struct {
+	Allowlist []string `json:"allowlist"`
+	Blocklist []string `json:"blocklist"`
+}
+
+type NormalizationRule struct {
+	MetricName  string  `json:"metric_name"`
+	Unit        string  `json:"unit,omitempty"`
+	TargetUnit  string  `json:"target_unit"`
+	ScaleFactor float64 `json:"scale_factor"`
 }
 
+const normalizedLabel = "_normalized"
+
+type consumerGroup struct {
+	config  ConsumerGroupConfig
+	kind    string
+	reader  *kafka.Reader
+	sampler *sampling.AdaptiveSampler
+}
+
+var DefaultAllowNegativeValues = []string{"network_in", "network_out", "network_latency_delta"}
+
 type Topics struct {
 	Metrics string
 	Logs    string
@@ -40,6 +125,13 @@ type ProcessorStats struct {
 	ProcessingErrors  uint64
 	LastProcessedTime time.Time
 	ProcessingRate    float64
+	BlockedMessages   uint64
+
+	ValidationFailures map[string]uint64
+
+	ProcessingRates map[string]float64
+	ErrorRates      map[string]float64
+	TopicStats      map[string]TopicStats
 }
 
 func NewProcessor(config ProcessorConfig) (*Processor, error) {
@@ -53,52 +145,149 @@ func NewProcessor(config ProcessorConfig) (*Processor, error) {
 	if config.BatchTimeout <= 0 {
 		config.BatchTimeout = 5 * time.Second
 	}
+	if config.AllowNegativeValues == nil {
+		config.AllowNegativeValues = DefaultAllowNegativeValues
+	}
+
+	if _, err := parsePartitionStartOffset(config.PartitionStartOffset); err != nil {
+		return nil, err
+	}
+
+	filters, err := normalizeMetricFilters(MetricFilters{
+		Allowlist: config.MetricAllowlist,
+		Blocklist: config.MetricBlocklist,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range config.NormalizationRules {
+		if err := validateNormalizationRule(rule); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, alias := range config.MetricAliases {
+		if err := validateMetricAlias(alias); err != nil {
+			return nil, err
+		}
+	}
+	for _, fanOut := range config.MetricFanOuts {
+		if err := validateMetricFanOut(fanOut); err != nil {
+			return nil, err
+		}
+	}
 
 	processor := &Processor{
-		config:      config,
-		readers:     make(map[string]*kafka.Reader),
-		queryEngine: config.QueryEngine,
-		stats:       ProcessorStats{LastProcessedTime: time.Now()},
+		config:        config,
+		readers:       make(map[string]*kafka.Reader),
+		routedReaders: make(map[string]*kafka.Reader),
+		queryEngine:   config.QueryEngine,
+		validator:     NewMetricValidator(config.AllowedUnits),
+		enricher:      NewLabelEnricher(config.NamespaceLabels),
+		stats: ProcessorStats{
+			LastProcessedTime:  time.Now(),
+			ValidationFailures: make(map[string]uint64),
+		},
+		metricFilters: filters,
+
+		normalizationRules: append([]NormalizationRule{}, config.NormalizationRules...),
+
+		categoryRules: make(map[string]string, len(config.CategoryRules)),
 	}
 
-	processor.initializeReaders()
+	for prefix, category := range config.CategoryRules {
+		processor.categoryRules[prefix] = category
+	}
+	if len(config.PreAggregateMetrics) > 0 {
+		preAggregator, err := NewPreAggregator(config.PreAggregateMetrics, PreAggregationBucket)
+		if err != nil {
+			return nil, err
+		}
+		processor.preAggregator = preAggregator
+	}
+	for _, alias := range config.MetricAliases {
+		processor.metricAliases = replaceAlias(processor.metricAliases, alias)
+	}
+	for _, fanOut := range config.MetricFanOuts {
+		processor.metricFanOuts = replaceFanOut(processor.metricFanOuts, fanOut)
+	}
+
+	if err := processor.initializeReaders(); err != nil {
+		return nil, err
+	}
 
 	return processor, nil
 }
 
 func (p *Processor) Start(ctx context.Context) error {
-	log.Println("🚀 Starting stream processor...")
+	slog.Info("Starting stream processor")
 
-	errCh := make(chan error, len(p.readers))
+	errCh := make(chan error, len(p.readers)+len(p.routedReaders)+len(p.partitions)+len(p.groups))
 
 	for topic, reader := range p.readers {
 		go func(topic string, reader *kafka.Reader) {
-			log.Printf("📡 Starting consumer for topic: %s", topic)
+			slog.Info("Starting consumer", "topic", topic)
 			errCh <- p.processStream(ctx, topic, reader)
 		}(topic, reader)
 	}
 
+	for topic, reader := range p.routedReaders {
+		go func(topic string, reader *kafka.Reader) {
+			slog.Info("Starting routed consumer", "topic", topic)
+			errCh <- p.processStream(ctx, "metrics", reader)
+		}(topic, reader)
+	}
+
+	for _, consumer := range p.partitions {
+		go func(consumer *PartitionConsumer) {
+			errCh <- consumer.Run(ctx, p.processPartitionMessage)
+		}(consumer)
+	}
+
+	for _, group := range p.groups {
+		go func(group *consumerGroup) {
+			slog.Info("Starting consumer group",
+				"group_id", group.config.GroupID,
+				"topic", group.config.Topic,
+				"sampling_rate", group.config.SamplingRate)
+			errCh <- p.processGroupStream(ctx, group)
+		}(group)
+	}
+
 	go p.reportStatistics(ctx)
+	go p.trackTopicRates(ctx)
+	if p.preAggregator != nil {
+		go p.preAggregator.Run(ctx)
+	}
 
 	select {
 	case err := <-errCh:
 		if err != nil {
-			log.Printf("Stream processing error: %v", err)
+			slog.Error("Stream processing error", "error", err)
 			return err
 		}
 	case <-ctx.Done():
-		log.Println("Stream processor shutting down...")
+		slog.Info("Stream processor shutting down")
 	}
 
 	for topic, reader := range p.readers {
-		log.Printf("Closing reader for topic: %s", topic)
+		slog.Debug("Closing reader", "topic", topic)
+		reader.Close()
+	}
+	for topic, reader := range p.routedReaders {
+		slog.Debug("Closing routed reader", "topic", topic)
 		reader.Close()
 	}
+	for _, group := range p.groups {
+		slog.Debug("Closing consumer group reader", "group_id", group.config.GroupID)
+		group.reader.Close()
+	}
 
 	return nil
 }
 
-func (p *Processor) initializeReaders() {
+func (p *Processor) initializeReaders() error {
 	readerConfig := kafka.ReaderConfig{
 		Brokers:        p.config.KafkaBrokers,
 		GroupID:        "kubesight-query-engine",
@@ -108,28 +297,135 @@ func (p *Processor) initializeReaders() {
 		StartOffset:    kafka.LastOffset,
 	}
 
-	if p.config.Topics.Metrics != "" {
-		metricsConfig := readerConfig
-		metricsConfig.Topic = p.config.Topics.Metrics
-		p.readers["metrics"] = kafka.NewReader(metricsConfig)
+	topics := map[string]string{
+		"metrics": p.config.Topics.Metrics,
+		"logs":    p.config.Topics.Logs,
+		"events":  p.config.Topics.Events,
 	}
 
-	if p.config.Topics.Logs != "" {
-		logsConfig := readerConfig
-		logsConfig.Topic = p.config.Topics.Logs
-		p.readers["logs"] = kafka.NewReader(logsConfig)
+	grouped := make(map[string]bool)
+	for _, groupConfig := range p.config.ConsumerGroups {
+		group, err := p.newConsumerGroup(groupConfig, readerConfig, topics)
+		if err != nil {
+			return err
+		}
+		p.groups = append(p.groups, group)
+		grouped[groupConfig.Topic] = true
 	}
 
-	if p.config.Topics.Events != "" {
-		eventsConfig := readerConfig
-		eventsConfig.Topic = p.config.Topics.Events
-		p.readers["events"] = kafka.NewReader(eventsConfig)
+	for _, kind := range []string{"metrics", "logs", "events"} {
+		topic := topics[kind]
+		if topic == "" || grouped[topic] {
+			continue
+		}
+
+		if partitions, assigned := p.config.PartitionAssignment[topic]; assigned && len(partitions) > 0 {
+			for _, partition := range partitions {
+				consumer, err := NewPartitionConsumer(p.config.KafkaBrokers, topic, kind, partition, p.partitionStartOffset())
+				if err != nil {
+					return err
+				}
+				p.partitions = append(p.partitions, consumer)
+			}
+			continue
+		}
+
+		config := readerConfig
+		config.Topic = topic
+		p.readers[kind] = kafka.NewReader(config)
 	}
 
-	log.Printf("Initialized %d Kafka readers", len(p.readers))
+	for _, rule := range p.config.Router.Rules {
+		if rule.Topic == "" || rule.Topic == p.config.Topics.Metrics {
+			continue
+		}
+		if _, exists := p.routedReaders[rule.Topic]; exists {
+			continue
+		}
+
+		config := readerConfig
+		config.Topic = rule.Topic
+		p.routedReaders[rule.Topic] = kafka.NewReader(config)
+	}
+
+	slog.Info("Initialized Kafka readers",
+		"readers", len(p.readers),
+		"routed_readers", len(p.routedReaders),
+		"partition_consumers", len(p.partitions),
+		"consumer_groups", len(p.groups))
+	return nil
+}
+
+func (p *Processor) newConsumerGroup(config ConsumerGroupConfig, readerConfig kafka.ReaderConfig, topics map[string]string) (*consumerGroup, error) {
+	if config.GroupID == "" || config.Topic == "" {
+		return nil, fmt.Errorf("consumer group requires both group_id and topic")
+	}
+	if config.SamplingRate <= 0 || config.SamplingRate > 1 {
+		return nil, fmt.Errorf("consumer group %s: sampling rate must be in (0, 1]", config.GroupID)
+	}
+
+	kind := "metrics"
+	for k, topic := range topics {
+		if topic == config.Topic {
+			kind = k
+		}
+	}
+
+	samplingConfig := p.config.SamplingDefaults
+	samplingConfig.BaseRate = config.SamplingRate
+	if samplingConfig.AnomalyRate < config.SamplingRate {
+		samplingConfig.AnomalyRate = config.SamplingRate
+	}
+	if config.ReservoirSize > 0 {
+		samplingConfig.ReservoirSize = config.ReservoirSize
+	}
+
+	groupReaderConfig := readerConfig
+	groupReaderConfig.GroupID = config.GroupID
+	groupReaderConfig.Topic = config.Topic
+
+	return &consumerGroup{
+		config:  config,
+		kind:    kind,
+		reader:  kafka.NewReader(groupReaderConfig),
+		sampler: sampling.NewAdaptiveSampler(samplingConfig),
+	}, nil
+}
+
+const (
+	PartitionOffsetEarliest = "earliest"
+	PartitionOffsetLatest   = "latest"
+)
+
+func (p *Processor) partitionStartOffset() int64 {
+	offset, _ := parsePartitionStartOffset(p.config.PartitionStartOffset)
+	return offset
+}
+
+func parsePartitionStartOffset(value string) (int64, error) {
+	switch value {
+	case "", PartitionOffsetLatest:
+		return kafka.LastOffset, nil
+	case PartitionOffsetEarliest:
+		return kafka.FirstOffset, nil
+	}
+
+	offset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid partition start offset %q: want %q, %q or a non-negative offset", value, PartitionOffsetEarliest, PartitionOffsetLatest)
+	}
+	return offset, nil
 }
 
 func (p *Processor) processStream(ctx context.Context, topic string, reader *kafka.Reader) error {
+	return p.consume(ctx, topic, reader, nil)
+}
+
+func (p *Processor) processGroupStream(ctx context.Context, group *consumerGroup) error {
+	return p.consume(ctx, group.kind, group.reader, group.sampler)
+}
+
+func (p *Processor) consume(ctx context.Context, topic string, reader *kafka.Reader, sampler *sampling.AdaptiveSampler) error {
 	for {
 		select {
 		case <-ctx.Done():
@@ -143,13 +439,16 @@ func (p *Processor) processStream(ctx context.Context, topic string, reader *kaf
 				if err == context.DeadlineExceeded {
 					continue
 				}
-				log.Printf("Error reading from topic %s: %v", topic, err)
+				slog.Error("Error reading from topic", "topic", topic, "error", err)
+				p.recordTopicResult(topic, err)
 				p.stats.ProcessingErrors++
 				continue
 			}
 
-			if err := p.processMessage(topic, message); err != nil {
-				log.Printf("Error processing message from topic %s: %v", topic, err)
+			err = p.dispatchMessage(topic, message, sampler)
+			p.recordTopicResult(topic, err)
+			if err != nil {
+				slog.Warn("Error processing message", "topic", topic, "error", err)
 				p.stats.ProcessingErrors++
 			} else {
 				p.stats.MessagesProcessed++
@@ -159,35 +458,103 @@ func (p *Processor) processStream(ctx context.Context, topic string, reader *kaf
 	}
 }
 
+func (p *Processor) processPartitionMessage(topic string, message kafka.Message) error {
+	err := p.processMessage(topic, message)
+	p.recordTopicResult(topic, err)
+	if err != nil {
+		p.stats.ProcessingErrors++
+		return err
+	}
+
+	p.stats.MessagesProcessed++
+	p.stats.LastProcessedTime = time.Now()
+	return nil
+}
+
 func (p *Processor) processMessage(topic string, message kafka.Message) error {
+	return p.dispatchMessage(topic, message, nil)
+}
+
+func (p *Processor) dispatchMessage(topic string, message kafka.Message, sampler *sampling.AdaptiveSampler) error {
 	switch topic {
 	case "metrics":
-		return p.processMetricMessage(message)
+		return p.processMetricMessage(message, sampler)
 	case "logs":
 		return p.processLogMessage(message)
 	case "events":
-		return p.processEventMessage(message)
+		return p.processEventMessage(message, sampler)
 	default:
 		return fmt.Errorf("unknown topic: %s", topic)
 	}
 }
 
-func (p *Processor) processMetricMessage(message kafka.Message) error {
+func (p *Processor) processMetricMessage(message kafka.Message, sampler *sampling.AdaptiveSampler) error {
 	var metric metrics.MetricPoint
 
 	if err := json.Unmarshal(message.Value, &metric); err != nil {
+		var timestampErr *metrics.TimestampError
+		if errors.As(err, &timestampErr) {
+			p.recordValidationFailure("timestamp")
+		}
 		return fmt.Errorf("failed to unmarshal metric: %v", err)
 	}
 
+	p.resolveAlias(&metric)
+
+	if !p.metricAllowed(metric.MetricName) {
+		p.stats.BlockedMessages++
+		return nil
+	}
+
+	p.normalize(&metric)
+	p.enricher.Enrich(&metric)
+	p.categorize(&metric)
+
+	if err := p.validator.Validate(&metric); err != nil {
+		var validationErrs ValidationErrors
+		if errors.As(err, &validationErrs) {
+			for _, fieldErr := range validationErrs {
+				p.recordValidationFailure(fieldErr.Field)
+			}
+		}
+		return fmt.Errorf("invalid metric: %v", err)
+	}
+
 	if err := p.validateMetric(&metric); err != nil {
 		return fmt.Errorf("invalid metric: %v", err)
 	}
 
-	p.queryEngine.ProcessMetric(&metric)
+	if p.preAggregator != nil && p.preAggregator.Matches(metric.MetricName) {
+		p.preAggregator.Add(&metric, func(aggregate PreAggregate) {
+			slog.Debug("Emitting pre-aggregated metric",
+				"metric_name", aggregate.Point.MetricName,
+				"count", aggregate.Count,
+				"min", aggregate.Min,
+				"max", aggregate.Max)
+			p.ingestFannedOut(aggregate.Point, sampler)
+		})
+		return nil
+	}
+
+	p.ingestFannedOut(&metric, sampler)
 
 	return nil
 }
 
+func (p *Processor) ingestFannedOut(metric *metrics.MetricPoint, sampler *sampling.AdaptiveSampler) {
+	for _, expanded := range p.fanOut(metric) {
+		p.ingest(expanded, sampler)
+	}
+}
+
+func (p *Processor) ingest(metric *metrics.MetricPoint, sampler *sampling.AdaptiveSampler) {
+	if sampler == nil {
+		p.queryEngine.ProcessMetric(metric)
+		return
+	}
+	p.queryEngine.ProcessMetricWithSampler(metric, sampler)
+}
+
 func (p *Processor) processLogMessage(message kafka.Message) error {
 	var logEntry metrics.LogEntry
 
@@ -195,12 +562,15 @@ func (p *Processor) processLogMessage(message kafka.Message) error {
 		return fmt.Errorf("failed to unmarshal log entry: %v", err)
 	}
 
-	log.Printf("Processed log entry: %s/%s - %s", logEntry.Namespace, logEntry.PodName, logEntry.Level)
+	slog.Debug("Processed log entry",
+		"namespace", logEntry.Namespace,
+		"pod_name", logEntry.PodName,
+		"level", logEntry.Level)
 
 	return nil
 }
 
-func (p *Processor) processEventMessage(message kafka.Message) error {
+func (p *Processor) processEventMessage(message kafka.Message, sampler *sampling.AdaptiveSampler) error {
 	var event metrics.KubernetesEvent
 
 	if err := json.Unmarshal(message.Value, &event); err != nil {
@@ -223,29 +593,222 @@ func (p *Processor) processEventMessage(message kafka.Message) error {
 		},
 	}
 
-	p.queryEngine.ProcessMetric(eventMetric)
+	p.ingest(eventMetric, sampler)
 
 	return nil
 }
 
+func (p *Processor) categorize(metric *metrics.MetricPoint) {
+	if metric.Category != "" {
+		return
+	}
+
+	longest := -1
+	for prefix, category := range p.categoryRules {
+		if len(prefix) > longest && strings.HasPrefix(metric.MetricName, prefix) {
+			metric.Category = category
+			longest = len(prefix)
+		}
+	}
+}
+
 func (p *Processor) validateMetric(metric *metrics.MetricPoint) error {
-	if metric.Timestamp.IsZero() {
-		return fmt.Errorf("timestamp is required")
+	if metric.Value < 0 && !p.allowsNegativeValues(metric.MetricName) {
+		return fmt.Errorf("negative values not allowed for metric: %s", metric.MetricName)
 	}
-	if metric.ClusterID == "" {
-		return fmt.Errorf("cluster_id is required")
+	return nil
+}
+
+func (p *Processor) allowsNegativeValues(metricName string) bool {
+	return matchesAnyPattern(p.config.AllowNegativeValues, metricName)
+}
+
+func (p *Processor) GetMetricFilters() MetricFilters {
+	p.filterMutex.RLock()
+	defer p.filterMutex.RUnlock()
+
+	return MetricFilters{
+		Allowlist: append([]string{}, p.metricFilters.Allowlist...),
+		Blocklist: append([]string{}, p.metricFilters.Blocklist...),
 	}
-	if metric.MetricName == "" {
-		return fmt.Errorf("metric_name is required")
+}
+
+func (p *Processor) SetMetricFilters(filters MetricFilters) error {
+	normalized, err := normalizeMetricFilters(filters)
+	if err != nil {
+		return err
 	}
-	if metric.Value < 0 {
-		if metric.MetricName != "network_in" && metric.MetricName != "network_out" {
-			return fmt.Errorf("negative values not allowed for metric: %s", metric.MetricName)
+
+	p.filterMutex.Lock()
+	p.metricFilters = normalized
+	p.filterMutex.Unlock()
+
+	slog.Info("Metric filters updated",
+		"allowlist", normalized.Allowlist,
+		"blocklist", normalized.Blocklist)
+	return nil
+}
+
+func (p *Processor) GetNamespaceLabels() map[string]map[string]string {
+	return p.enricher.NamespaceLabels()
+}
+
+func (p *Processor) SetNamespaceLabels(namespaceLabels map[string]map[string]string) {
+	p.enricher.SetNamespaceLabels(namespaceLabels)
+	slog.Info("Namespace labels updated", "namespaces", len(namespaceLabels))
+}
+
+func (p *Processor) SetMetricPriorities(priorities map[string]int) error {
+	if err := sampling.ValidateMetricPriorities(priorities); err != nil {
+		return err
+	}
+
+	for _, group := range p.groups {
+		if err := group.sampler.SetMetricPriorities(priorities); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Processor) SetAnomalyThreshold(threshold sampling.AnomalyThreshold) error {
+	if err := sampling.ValidateAnomalyThreshold(threshold); err != nil {
+		return err
+	}
+
+	for _, group := range p.groups {
+		if err := group.sampler.AnomalyDetector().SetThreshold(threshold); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+func (p *Processor) RevertAnomalyThreshold(metricName string) {
+	for _, group := range p.groups {
+		group.sampler.AnomalyDetector().RevertThreshold(metricName)
+	}
+}
+
+func (p *Processor) ResetAnomalyThresholds() {
+	for _, group := range p.groups {
+		group.sampler.AnomalyDetector().ResetThresholds()
+	}
+}
+
+func (p *Processor) GetNormalizationRules() []NormalizationRule {
+	p.normalizationMutex.RLock()
+	defer p.normalizationMutex.RUnlock()
+
+	return append([]NormalizationRule{}, p.normalizationRules...)
+}
+
+func (p *Processor) AddNormalizationRule(rule NormalizationRule) error {
+	if err := validateNormalizationRule(rule); err != nil {
+		return err
+	}
+
+	p.normalizationMutex.Lock()
+	p.normalizationRules = append(p.normalizationRules, rule)
+	p.normalizationMutex.Unlock()
+
+	slog.Info("Normalization rule added",
+		"metric_name", rule.MetricName,
+		"unit", rule.Unit,
+		"target_unit", rule.TargetUnit,
+		"scale_factor", rule.ScaleFactor)
+	return nil
+}
+
+func (p *Processor) normalize(metric *metrics.MetricPoint) {
+	if metric.Labels[normalizedLabel] == "true" {
+		return
+	}
+
+	p.normalizationMutex.RLock()
+	defer p.normalizationMutex.RUnlock()
+
+	applied := false
+	for _, rule := range p.normalizationRules {
+		if matched, err := path.Match(rule.MetricName, metric.MetricName); err != nil || !matched {
+			continue
+		}
+		if rule.Unit != "" && rule.Unit != metric.Unit {
+			continue
+		}
+
+		metric.Value *= rule.ScaleFactor
+		if rule.TargetUnit != "" {
+			metric.Unit = rule.TargetUnit
+		}
+		applied = true
+	}
+
+	if applied {
+		if metric.Labels == nil {
+			metric.Labels = make(map[string]string)
+		}
+		metric.Labels[normalizedLabel] = "true"
+	}
+}
+
+func validateNormalizationRule(rule NormalizationRule) error {
+	if rule.MetricName == "" {
+		return fmt.Errorf("normalization rule requires a metric name")
+	}
+	if _, err := path.Match(rule.MetricName, ""); err != nil {
+		return fmt.Errorf("invalid normalization metric pattern %q: %v", rule.MetricName, err)
+	}
+	if rule.ScaleFactor == 0 || math.IsNaN(rule.ScaleFactor) || math.IsInf(rule.ScaleFactor, 0) {
+		return fmt.Errorf("invalid scale factor for %s: %v", rule.MetricName, rule.ScaleFactor)
+	}
+	return nil
+}
+
+func (p *Processor) metricAllowed(metricName string) bool {
+	p.filterMutex.RLock()
+	defer p.filterMutex.RUnlock()
+
+	if matchesAnyPattern(p.metricFilters.Blocklist, metricName) {
+		return false
+	}
+	if len(p.metricFilters.Allowlist) > 0 {
+		return matchesAnyPattern(p.metricFilters.Allowlist, metricName)
+	}
+	return true
+}
+
+func normalizeMetricFilters(filters MetricFilters) (MetricFilters, error) {
+	normalized := MetricFilters{
+		Allowlist: make([]string, 0, len(filters.Allowlist)),
+		Blocklist: make([]string, 0, len(filters.Blocklist)),
+	}
+
+	for _, pattern := range filters.Allowlist {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return MetricFilters{}, fmt.Errorf("invalid allowlist pattern %q: %v", pattern, err)
+		}
+		normalized.Allowlist = append(normalized.Allowlist, pattern)
+	}
+	for _, pattern := range filters.Blocklist {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return MetricFilters{}, fmt.Errorf("invalid blocklist pattern %q: %v", pattern, err)
+		}
+		normalized.Blocklist = append(normalized.Blocklist, pattern)
+	}
+
+	return normalized, nil
+}
+
+func matchesAnyPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Processor) reportStatistics(ctx context.Context) {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
@@ -261,20 +824,50 @@ func (p *Processor) reportStatistics(ctx context.Context) {
 			p.stats.ProcessingRate = float64(currentCount-lastMessageCount) / 30.0
 			lastMessageCount = currentCount
 
-			log.Printf("Stream Processor Stats: Messages: %d, Errors: %d, Rate: %.2f msg/s",
-				p.stats.MessagesProcessed,
-				p.stats.ProcessingErrors,
-				p.stats.ProcessingRate)
+			slog.Info("Stream processor stats",
+				"messages", p.stats.MessagesProcessed,
+				"errors", p.stats.ProcessingErrors,
+				"rate", p.stats.ProcessingRate)
 		}
 	}
 }
 
+func (p *Processor) recordValidationFailure(field string) {
+	p.validationMutex.Lock()
+	p.stats.ValidationFailures[field]++
+	p.validationMutex.Unlock()
+}
+
 func (p *Processor) GetStats() ProcessorStats {
-	return p.stats
+	stats := p.stats
+
+	p.validationMutex.Lock()
+	stats.ValidationFailures = make(map[string]uint64, len(p.stats.ValidationFailures))
+	for field, count := range p.stats.ValidationFailures {
+		stats.ValidationFailures[field] = count
+	}
+	p.validationMutex.Unlock()
+
+	stats.TopicStats = p.topicStats()
+	stats.ProcessingRates = make(map[string]float64, len(stats.TopicStats))
+	stats.ErrorRates = make(map[string]float64, len(stats.TopicStats))
+	for topic, topicStats := range stats.TopicStats {
+		stats.ProcessingRates[topic] = topicStats.RatePerSec
+		stats.ErrorRates[topic] = topicStats.ErrorRatePerSec
+	}
+	return stats
+}
+
+func (p *Processor) GetPartitionStats() []PartitionStats {
+	stats := make([]PartitionStats, len(p.partitions))
+	for i, consumer := range p.partitions {
+		stats[i] = consumer.Stats()
+	}
+	return stats
 }
 
 type MockDataGenerator struct {
-	writer     *kafka.Writer
+	router     *MetricRouter
 	stopCh     chan struct{}
 	interval   time.Duration
 	clusterIDs []string
@@ -283,15 +876,21 @@ type MockDataGenerator struct {
 	pods       []string
 }
 
-func NewMockDataGenerator(brokers []string, topic string) *MockDataGenerator {
-	writer := &kafka.Writer{
-		Addr:     kafka.TCP(brokers...),
-		Topic:    topic,
-		Balancer: &kafka.LeastBytes{},
+func NewMockDataGenerator(brokers []string, topic string) (*MockDataGenerator, error) {
+	if topic == "" {
+		topic = "k8s-metrics"
 	}
 
+	router, err := NewMetricRouter(brokers, RouterConfig{DefaultTopic: topic})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric router: %v", err)
+	}
+	return NewMockDataGeneratorWithRouter(router), nil
+}
+
+func NewMockDataGeneratorWithRouter(router *MetricRouter) *MockDataGenerator {
 	return &MockDataGenerator{
-		writer:     writer,
+		router:     router,
 		stopCh:     make(chan struct{}),
 		interval:   time.Second,
 		clusterIDs: []string{"prod-cluster", "staging-cluster", "dev-cluster"},
@@ -302,7 +901,7 @@ func NewMockDataGenerator(brokers []string, topic string) *MockDataGenerator {
 }
 
 func (mdg *MockDataGenerator) Start(ctx context.Context) {
-	log.Println("Starting mock data generator...")
+	slog.Info("Starting mock data generator")
 
 	ticker := time.NewTicker(mdg.interval)
 	defer ticker.Stop()
@@ -317,11 +916,11 @@ func (mdg *MockDataGenerator) Start(ctx context.Context) {
 		case <-ticker.C:
 			metric := mdg.generateMetric()
 			if err := mdg.sendMetric(ctx, metric); err != nil {
-				log.Printf("Failed to send mock metric: %v", err)
+				slog.Error("Failed to send mock metric", "error", err)
 			} else {
 				count++
 				if count%100 == 0 {
-					log.Printf("Generated %d mock metrics", count)
+					slog.Debug("Generated mock metrics", "count", count)
 				}
 			}
 		}
@@ -330,7 +929,7 @@ func (mdg *MockDataGenerator) Start(ctx context.Context) {
 
 func (mdg *MockDataGenerator) Stop() {
 	close(mdg.stopCh)
-	mdg.writer.Close()
+	mdg.router.Close()
 }
 
 func (mdg *MockDataGenerator) generateMetric() *metrics.MetricPoint {
@@ -360,16 +959,5 @@ func (mdg *MockDataGenerator) generateMetric() *metrics.MetricPoint {
 }
 
 func (mdg *MockDataGenerator) sendMetric(ctx context.Context, metric *metrics.MetricPoint) error {
-	data, err := json.Marshal(metric)
-	if err != nil {
-		return err
-	}
-
-	message := kafka.Message{
-		Key:   []byte(metric.GetKey()),
-		Value: data,
-		Time:  metric.Timestamp,
-	}
-
-	return mdg.writer.WriteMessages(ctx, message)
+	return mdg.router.Route(ctx, metric)
 }
@@ -0,0 +1,164 @@
+//go:build integration
+
+// Run against a local broker, e.g. the one from docker-compose:
+//
+//	KAFKA_BROKERS=localhost:9092 go test -tags integration ./internal/stream
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/asmit27rai/kubesight/internal/engine"
+	"github.com/asmit27rai/kubesight/internal/sampling"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const integrationMetricCount = 100
+
+func integrationBrokers(t *testing.T) []string {
+	t.Helper()
+
+	value := os.Getenv("KAFKA_BROKERS")
+	if value == "" {
+		t.Skip("KAFKA_BROKERS is not set")
+	}
+	return strings.Split(value, ",")
+}
+
+func createIntegrationTopic(t *testing.T, brokers []string) string {
+	t.Helper()
+
+	conn, err := kafka.Dial("tcp", brokers[0])
+	if err != nil {
+		t.Fatalf("failed to dial broker %s: %v", brokers[0], err)
+	}
+	defer conn.Close()
+
+	controller, err := conn.Controller()
+	if err != nil {
+		t.Fatalf("failed to find controller: %v", err)
+	}
+	controllerConn, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		t.Fatalf("failed to dial controller: %v", err)
+	}
+	defer controllerConn.Close()
+
+	topic := fmt.Sprintf("kubesight-it-%d", time.Now().UnixNano())
+	if err := controllerConn.CreateTopics(kafka.TopicConfig{Topic: topic, NumPartitions: 1, ReplicationFactor: 1}); err != nil {
+		t.Fatalf("failed to create topic %s: %v", topic, err)
+	}
+	t.Cleanup(func() {
+		if conn, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port))); err == nil {
+			conn.DeleteTopics(topic)
+			conn.Close()
+		}
+	})
+	return topic
+}
+
+func publishIntegrationMetrics(t *testing.T, brokers []string, topic string) {
+	t.Helper()
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: 10 * time.Millisecond,
+	}
+	defer writer.Close()
+
+	messages := make([]kafka.Message, 0, integrationMetricCount)
+	for i := 0; i < integrationMetricCount; i++ {
+		metric := &metrics.MetricPoint{
+			Timestamp:     time.Now(),
+			ClusterID:     "integration",
+			Namespace:     "default",
+			PodName:       fmt.Sprintf("pod-%d", i%10),
+			ContainerName: "main",
+			MetricName:    "cpu_usage",
+			Value:         float64(i%100) / 100,
+			Unit:          "percent",
+		}
+		data, err := json.Marshal(metric)
+		if err != nil {
+			t.Fatalf("failed to marshal metric: %v", err)
+		}
+		messages = append(messages, kafka.Message{Key: []byte(metric.GetKey()), Value: data})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := writer.WriteMessages(ctx, messages...); err != nil {
+		t.Fatalf("failed to publish metrics: %v", err)
+	}
+}
+
+func TestProcessorConsumesPublishedMetrics(t *testing.T) {
+	brokers := integrationBrokers(t)
+	topic := createIntegrationTopic(t, brokers)
+	publishIntegrationMetrics(t, brokers, topic)
+
+	queryEngine := engine.NewQueryEngine(engine.QueryEngineConfig{
+		HLLPrecision: 12,
+		CMSWidth:     2048,
+		CMSDepth:     4,
+		BloomSize:    1 << 16,
+		BloomHashes:  4,
+		SamplingConfig: sampling.SamplingConfig{
+			BaseRate:         1.0,
+			AnomalyRate:      1.0,
+			WindowSize:       time.Hour,
+			ReservoirSize:    10000,
+			MetricPriorities: map[string]int{},
+		},
+	})
+
+	processor, err := NewProcessor(ProcessorConfig{
+		KafkaBrokers:         brokers,
+		Topics:               Topics{Metrics: topic},
+		QueryEngine:          queryEngine,
+		PartitionAssignment:  map[string][]int{topic: {0}},
+		PartitionStartOffset: PartitionOffsetEarliest,
+	})
+	if err != nil {
+		t.Fatalf("NewProcessor() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- processor.Start(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Start() error = %v", err)
+		}
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		sampled := queryEngine.GetStats().TotalSampled
+		if sampled >= integrationMetricCount/2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("TotalSampled = %d after 10s, want at least %d of %d published metrics",
+				sampled, integrationMetricCount/2, integrationMetricCount)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if stats := processor.GetStats(); stats.ProcessingErrors != 0 {
+		t.Errorf("ProcessingErrors = %d, want 0", stats.ProcessingErrors)
+	}
+}
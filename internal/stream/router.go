@@ -0,0 +1,113 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+type RoutingRule struct {
+	Name    string
+	MatchFn func(*metrics.MetricPoint) bool
+	Topic   string
+}
+
+type RouterConfig struct {
+	Rules              []RoutingRule
+	DefaultTopic       string
+	PartitionByCluster bool
+}
+
+type MetricRouter struct {
+	config RouterConfig
+	writer *kafka.Writer
+}
+
+func NewMetricRouter(brokers []string, config RouterConfig) (*MetricRouter, error) {
+	if config.DefaultTopic == "" {
+		return nil, fmt.Errorf("default topic is required for metric routing")
+	}
+	for i, rule := range config.Rules {
+		if rule.MatchFn == nil || rule.Topic == "" {
+			return nil, fmt.Errorf("routing rule %d must have a match function and topic", i)
+		}
+	}
+
+	var balancer kafka.Balancer = &kafka.LeastBytes{}
+	if config.PartitionByCluster {
+		balancer = &kafka.Hash{}
+	}
+
+	return &MetricRouter{
+		config: config,
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Balancer: balancer,
+		},
+	}, nil
+}
+
+func DefaultRoutingRules() []RoutingRule {
+	return []RoutingRule{
+		{
+			Name:    "anomalies",
+			MatchFn: func(metric *metrics.MetricPoint) bool { return metric.IsAnomaly() },
+			Topic:   "k8s-anomalies",
+		},
+		{
+			Name:    "kube-system",
+			MatchFn: func(metric *metrics.MetricPoint) bool { return metric.Namespace == "kube-system" },
+			Topic:   "k8s-system-metrics",
+		},
+	}
+}
+
+func (mr *MetricRouter) TopicFor(metric *metrics.MetricPoint) string {
+	for _, rule := range mr.config.Rules {
+		if rule.MatchFn(metric) {
+			return rule.Topic
+		}
+	}
+	return mr.config.DefaultTopic
+}
+
+func (mr *MetricRouter) Topics() []string {
+	seen := map[string]bool{mr.config.DefaultTopic: true}
+	topics := []string{mr.config.DefaultTopic}
+	for _, rule := range mr.config.Rules {
+		if !seen[rule.Topic] {
+			seen[rule.Topic] = true
+			topics = append(topics, rule.Topic)
+		}
+	}
+	return topics
+}
+
+func (mr *MetricRouter) Route(ctx context.Context, metric *metrics.MetricPoint) error {
+	data, err := json.Marshal(metric)
+	if err != nil {
+		return err
+	}
+
+	key := metric.GetKey()
+	if mr.config.PartitionByCluster {
+		key = metric.ClusterID
+	}
+
+	message := kafka.Message{
+		Topic: mr.TopicFor(metric),
+		Key:   []byte(key),
+		Value: data,
+		Time:  metric.Timestamp,
+	}
+
+	return mr.writer.WriteMessages(ctx, message)
+}
+
+func (mr *MetricRouter) Close() error {
+	return mr.writer.Close()
+}
@@ -0,0 +1,152 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	TopicRateInterval = 5 * time.Second
+	topicRateSlots    = 12
+)
+
+type TopicStats struct {
+	MessagesProcessed uint64  `json:"messages_processed"`
+	ProcessingErrors  uint64  `json:"processing_errors"`
+	ConsumerLag       uint64  `json:"consumer_lag"`
+	RatePerSec        float64 `json:"rate_per_sec"`
+	ErrorRatePerSec   float64 `json:"error_rate_per_sec"`
+}
+
+type topicCounter struct {
+	processed atomic.Uint64
+	errors    atomic.Uint64
+
+	mutex sync.Mutex
+	ring  [topicRateSlots]rateSample
+	next  int
+	size  int
+}
+
+type rateSample struct {
+	processed uint64
+	errors    uint64
+	at        time.Time
+}
+
+func (p *Processor) topicCounter(topic string) *topicCounter {
+	if counter, exists := p.topicCounters.Load(topic); exists {
+		return counter.(*topicCounter)
+	}
+	counter, _ := p.topicCounters.LoadOrStore(topic, &topicCounter{})
+	return counter.(*topicCounter)
+}
+
+func (p *Processor) recordTopicResult(topic string, err error) {
+	counter := p.topicCounter(topic)
+	if err != nil {
+		counter.errors.Add(1)
+		return
+	}
+	counter.processed.Add(1)
+}
+
+func (p *Processor) trackTopicRates(ctx context.Context) {
+	ticker := time.NewTicker(TopicRateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.topicCounters.Range(func(_, value interface{}) bool {
+				value.(*topicCounter).sample(now)
+				return true
+			})
+		}
+	}
+}
+
+func (tc *topicCounter) sample(now time.Time) {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	tc.ring[tc.next] = rateSample{
+		processed: tc.processed.Load(),
+		errors:    tc.errors.Load(),
+		at:        now,
+	}
+	tc.next = (tc.next + 1) % topicRateSlots
+	if tc.size < topicRateSlots {
+		tc.size++
+	}
+}
+
+func (tc *topicCounter) rates() (float64, float64) {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	if tc.size < 2 {
+		return 0, 0
+	}
+
+	newest := tc.ring[(tc.next-1+topicRateSlots)%topicRateSlots]
+	oldest := tc.ring[(tc.next-tc.size+topicRateSlots)%topicRateSlots]
+	elapsed := newest.at.Sub(oldest.at).Seconds()
+	if elapsed <= 0 {
+		return 0, 0
+	}
+	return float64(newest.processed-oldest.processed) / elapsed,
+		float64(newest.errors-oldest.errors) / elapsed
+}
+
+func (p *Processor) topicStats() map[string]TopicStats {
+	lags := p.consumerLags()
+
+	stats := make(map[string]TopicStats)
+	p.topicCounters.Range(func(key, value interface{}) bool {
+		counter := value.(*topicCounter)
+		rate, errorRate := counter.rates()
+		stats[key.(string)] = TopicStats{
+			MessagesProcessed: counter.processed.Load(),
+			ProcessingErrors:  counter.errors.Load(),
+			ConsumerLag:       lags[key.(string)],
+			RatePerSec:        rate,
+			ErrorRatePerSec:   errorRate,
+		}
+		return true
+	})
+	return stats
+}
+
+func (p *Processor) consumerLags() map[string]uint64 {
+	lags := make(map[string]uint64)
+	addLag := func(topic string, lag int64) {
+		if lag > 0 {
+			lags[topic] += uint64(lag)
+		}
+	}
+
+	for kind, reader := range p.readers {
+		addLag(kind, readerLag(reader))
+	}
+	for _, reader := range p.routedReaders {
+		addLag("metrics", readerLag(reader))
+	}
+	for _, group := range p.groups {
+		addLag(group.kind, readerLag(group.reader))
+	}
+	for _, consumer := range p.partitions {
+		addLag(consumer.kind, consumer.Stats().Lag)
+	}
+	return lags
+}
+
+func readerLag(reader *kafka.Reader) int64 {
+	return reader.Stats().Lag
+}
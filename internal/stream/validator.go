@@ -0,0 +1,119 @@
+package stream
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	maxMetricAge       = 24 * time.Hour
+	maxMetricClockSkew = time.Minute
+)
+
+var (
+	identifierPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+	DefaultAllowedUnits = []string{
+		"percent",
+		"bytes",
+		"bytes_per_sec",
+		"packets_per_sec",
+		"requests_per_sec",
+		"milliseconds",
+		"seconds",
+		"count",
+		"generic",
+	}
+)
+
+type ValidationError struct {
+	Field  string
+	Value  interface{}
+	Reason string
+}
+
+func (ve *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s (%v): %s", ve.Field, ve.Value, ve.Reason)
+}
+
+type ValidationErrors []*ValidationError
+
+func (ve ValidationErrors) Error() string {
+	messages := make([]string, len(ve))
+	for i, err := range ve {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+type MetricValidator struct {
+	allowedUnits map[string]bool
+	now          func() time.Time
+}
+
+func NewMetricValidator(allowedUnits []string) *MetricValidator {
+	if len(allowedUnits) == 0 {
+		allowedUnits = DefaultAllowedUnits
+	}
+
+	units := make(map[string]bool, len(allowedUnits))
+	for _, unit := range allowedUnits {
+		units[unit] = true
+	}
+
+	return &MetricValidator{
+		allowedUnits: units,
+		now:          time.Now,
+	}
+}
+
+func (mv *MetricValidator) Validate(metric *metrics.MetricPoint) error {
+	var errs ValidationErrors
+
+	now := mv.now()
+	if metric.Timestamp.Before(now.Add(-maxMetricAge)) || metric.Timestamp.After(now.Add(maxMetricClockSkew)) {
+		errs = append(errs, &ValidationError{
+			Field:  "timestamp",
+			Value:  metric.Timestamp.Format(time.RFC3339),
+			Reason: fmt.Sprintf("must be within [now-%v, now+%v]", maxMetricAge, maxMetricClockSkew),
+		})
+	}
+
+	if math.IsNaN(metric.Value) || math.IsInf(metric.Value, 0) {
+		errs = append(errs, &ValidationError{Field: "value", Value: metric.Value, Reason: "must be finite"})
+	}
+
+	identifiers := []struct {
+		field string
+		value string
+	}{
+		{"cluster_id", metric.ClusterID},
+		{"namespace", metric.Namespace},
+		{"pod_name", metric.PodName},
+	}
+	for _, id := range identifiers {
+		if id.value == "" {
+			errs = append(errs, &ValidationError{Field: id.field, Value: id.value, Reason: "is required"})
+		} else if !identifierPattern.MatchString(id.value) {
+			errs = append(errs, &ValidationError{Field: id.field, Value: id.value, Reason: "must match [a-z0-9-]+"})
+		}
+	}
+
+	if metric.MetricName == "" {
+		errs = append(errs, &ValidationError{Field: "metric_name", Value: metric.MetricName, Reason: "is required"})
+	}
+
+	if !mv.allowedUnits[metric.Unit] {
+		errs = append(errs, &ValidationError{Field: "unit", Value: metric.Unit, Reason: "is not a known unit"})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
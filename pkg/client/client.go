@@ -0,0 +1,165 @@
+// Package client provides a typed Go client for the KubeSight HTTP API.
+//
+// Create a client pointing at a running server and issue queries:
+//
+//	c := client.NewClient("http://localhost:8080")
+//	result, err := c.ExecuteQuery(ctx, &metrics.QueryRequest{
+//		Query:     "PERCENTILE(95) cpu_usage",
+//		QueryType: metrics.Percentile,
+//		Filters:   map[string]string{"metric_name": "cpu_usage"},
+//	})
+//
+// Set Token to send an "Authorization: Bearer" header with every request:
+//
+//	c := &client.Client{BaseURL: "https://kubesight.example.com", Token: os.Getenv("KUBESIGHT_TOKEN")}
+//	stats, err := c.GetStats(ctx)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/asmit27rai/kubesight/internal/engine"
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+const (
+	apiPrefix      = "/api/v1"
+	defaultTimeout = 30 * time.Second
+)
+
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Token      string
+}
+
+type APIError struct {
+	StatusCode int    `json:"status"`
+	Message    string `json:"error"`
+	Details    string `json:"details,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("kubesight API error %d: %s: %s", e.StatusCode, e.Message, e.Details)
+	}
+	return fmt.Sprintf("kubesight API error %d: %s", e.StatusCode, e.Message)
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+func (c *Client) ExecuteQuery(ctx context.Context, request *metrics.QueryRequest) (*metrics.QueryResult, error) {
+	var result metrics.QueryResult
+	if err := c.do(ctx, http.MethodPost, "/query", request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) BatchQuery(ctx context.Context, requests []metrics.QueryRequest) ([]*metrics.QueryResult, error) {
+	var response struct {
+		Results []*metrics.QueryResult `json:"results"`
+		Count   int                    `json:"count"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/query/batch", requests, &response); err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
+
+func (c *Client) GetStats(ctx context.Context) (*metrics.SystemStats, error) {
+	var stats metrics.SystemStats
+	if err := c.do(ctx, http.MethodGet, "/stats", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (c *Client) GetEngineStats(ctx context.Context) (*engine.QueryEngineStats, error) {
+	var stats engine.QueryEngineStats
+	if err := c.do(ctx, http.MethodGet, "/stats/engine", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (c *Client) Health(ctx context.Context) (map[string]interface{}, error) {
+	var health map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/health", nil, &health); err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+func (c *Client) GenerateTestData(ctx context.Context, count int, clusterID, namespace string) error {
+	body := map[string]interface{}{
+		"count":      count,
+		"cluster_id": clusterID,
+		"namespace":  namespace,
+	}
+	return c.do(ctx, http.MethodPost, "/demo/generate", body, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.BaseURL, "/")+apiPrefix+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %v", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		apiErr.StatusCode = resp.StatusCode
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"fmt"
+
+	"github.com/asmit27rai/kubesight/pkg/metrics"
+)
+
+type ErrMetricNotFound struct {
+	MetricName string
+}
+
+func (e *ErrMetricNotFound) Error() string {
+	return fmt.Sprintf("no samples available for metric: %s", e.MetricName)
+}
+
+func (e *ErrMetricNotFound) Is(target error) bool {
+	t, ok := target.(*ErrMetricNotFound)
+	return ok && (t.MetricName == "" || t.MetricName == e.MetricName)
+}
+
+type ErrInvalidQuery struct {
+	Query  string
+	Reason string
+}
+
+func (e *ErrInvalidQuery) Error() string {
+	if e.Query == "" {
+		return fmt.Sprintf("invalid query: %s", e.Reason)
+	}
+	return fmt.Sprintf("invalid query %q: %s", e.Query, e.Reason)
+}
+
+func (e *ErrInvalidQuery) Is(target error) bool {
+	_, ok := target.(*ErrInvalidQuery)
+	return ok
+}
+
+type ErrUnsupportedQueryType struct {
+	Type metrics.QueryType
+}
+
+func (e *ErrUnsupportedQueryType) Error() string {
+	return fmt.Sprintf("unsupported query type: %s", e.Type)
+}
+
+func (e *ErrUnsupportedQueryType) Is(target error) bool {
+	t, ok := target.(*ErrUnsupportedQueryType)
+	return ok && (t.Type == "" || t.Type == e.Type)
+}
+
+type ErrSamplerFull struct {
+	Stratum string
+}
+
+func (e *ErrSamplerFull) Error() string {
+	return fmt.Sprintf("sampler full for stratum: %s", e.Stratum)
+}
+
+func (e *ErrSamplerFull) Is(target error) bool {
+	t, ok := target.(*ErrSamplerFull)
+	return ok && (t.Stratum == "" || t.Stratum == e.Stratum)
+}
+
+type ErrPrecisionMismatch struct {
+	Expected uint8
+	Actual   uint8
+}
+
+func (e *ErrPrecisionMismatch) Error() string {
+	return fmt.Sprintf("precision mismatch between HyperLogLogs: %d != %d", e.Expected, e.Actual)
+}
+
+func (e *ErrPrecisionMismatch) Is(target error) bool {
+	_, ok := target.(*ErrPrecisionMismatch)
+	return ok
+}
+
+type ErrQueryTooComplex struct {
+	Complexity int
+	Limit      int
+}
+
+func (e *ErrQueryTooComplex) Error() string {
+	return fmt.Sprintf("query complexity %d exceeds limit %d", e.Complexity, e.Limit)
+}
+
+func (e *ErrQueryTooComplex) Is(target error) bool {
+	_, ok := target.(*ErrQueryTooComplex)
+	return ok
+}
+
+type ErrSnapshotVersion struct {
+	Expected uint8
+	Actual   uint8
+}
+
+func (e *ErrSnapshotVersion) Error() string {
+	return fmt.Sprintf("unsupported snapshot format version: %d (expected %d)", e.Actual, e.Expected)
+}
+
+func (e *ErrSnapshotVersion) Is(target error) bool {
+	_, ok := target.(*ErrSnapshotVersion)
+	return ok
+}
@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TimestampError struct {
+	Value interface{}
+	Err   error
+}
+
+func (e *TimestampError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("invalid timestamp: %v", e.Value)
+	}
+	return fmt.Sprintf("invalid timestamp %v: %v", e.Value, e.Err)
+}
+
+func (e *TimestampError) Unwrap() error {
+	return e.Err
+}
+
+func ParseFlexibleTimestamp(v interface{}) (time.Time, error) {
+	switch value := v.(type) {
+	case time.Time:
+		return value, nil
+	case json.Number:
+		return parseNumericTimestamp(value.String())
+	case float64:
+		return unixTimestamp(value), nil
+	case int64:
+		return unixTimestamp(float64(value)), nil
+	case int:
+		return unixTimestamp(float64(value)), nil
+	case string:
+		return parseStringTimestamp(value)
+	default:
+		return time.Time{}, &TimestampError{Value: v, Err: fmt.Errorf("unsupported type %T", v)}
+	}
+}
+
+func parseNumericTimestamp(value string) (time.Time, error) {
+	if integer, err := strconv.ParseInt(value, 10, 64); err == nil {
+		switch {
+		case integer > 1e15 || integer < -1e15:
+			return time.Unix(0, integer).UTC(), nil
+		case integer > 1e12 || integer < -1e12:
+			return time.UnixMilli(integer).UTC(), nil
+		default:
+			return time.Unix(integer, 0).UTC(), nil
+		}
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return time.Time{}, &TimestampError{Value: value, Err: err}
+	}
+	return unixTimestamp(number), nil
+}
+
+func unixTimestamp(value float64) time.Time {
+	switch magnitude := math.Abs(value); {
+	case magnitude > 1e15:
+		return time.Unix(0, int64(value)).UTC()
+	case magnitude > 1e12:
+		return time.UnixMilli(int64(value)).UTC()
+	default:
+		seconds, fraction := math.Modf(value)
+		return time.Unix(int64(seconds), int64(fraction*1e9)).UTC()
+	}
+}
+
+func parseStringTimestamp(value string) (time.Time, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return time.Time{}, &TimestampError{Value: value, Err: fmt.Errorf("empty timestamp")}
+	}
+
+	if t, ok := parseRelativeTimestamp(trimmed); ok {
+		return t, nil
+	}
+
+	if _, err := strconv.ParseFloat(trimmed, 64); err == nil {
+		return parseNumericTimestamp(trimmed)
+	}
+
+	var lastErr error
+	for _, layout := range []string{time.RFC3339Nano, time.RFC3339} {
+		t, err := time.Parse(layout, trimmed)
+		if err == nil {
+			return t, nil
+		}
+		lastErr = err
+	}
+	return time.Time{}, &TimestampError{Value: value, Err: lastErr}
+}
+
+func parseRelativeTimestamp(value string) (time.Time, bool) {
+	now := time.Now()
+	if value == "now" {
+		return now, true
+	}
+
+	offset := strings.TrimPrefix(value, "now")
+	if !strings.HasPrefix(offset, "-") && !strings.HasPrefix(offset, "+") {
+		return time.Time{}, false
+	}
+
+	duration, err := time.ParseDuration(offset)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return now.Add(duration), true
+}
+
+func (mp *MetricPoint) UnmarshalJSON(data []byte) error {
+	type metricPointAlias MetricPoint
+	aux := struct {
+		*metricPointAlias
+		Timestamp json.RawMessage `json:"timestamp"`
+	}{
+		metricPointAlias: (*metricPointAlias)(mp),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Timestamp) == 0 || bytes.Equal(aux.Timestamp, []byte("null")) {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(aux.Timestamp))
+	decoder.UseNumber()
+
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return &TimestampError{Value: string(aux.Timestamp), Err: err}
+	}
+
+	timestamp, err := ParseFlexibleTimestamp(raw)
+	if err != nil {
+		return err
+	}
+	mp.Timestamp = timestamp
+	return nil
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func FuzzParseRelativeTimestamp(f *testing.F) {
+	for _, value := range []string{"now", "now-5m", "now+1h30m", "now-", "now5m", "-5m", "now--5m", "now-1.5h", "now-9999999999h", ""} {
+		f.Add(value)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		before := time.Now()
+		parsed, ok := parseRelativeTimestamp(value)
+		after := time.Now()
+
+		if !ok {
+			if !parsed.IsZero() {
+				t.Errorf("parseRelativeTimestamp(%q) = %v on failure, want zero time", value, parsed)
+			}
+			return
+		}
+		if value != "now" && !strings.HasPrefix(value, "now-") && !strings.HasPrefix(value, "now+") &&
+			!strings.HasPrefix(value, "-") && !strings.HasPrefix(value, "+") {
+			t.Errorf("parseRelativeTimestamp(%q) accepted a value without a relative offset", value)
+		}
+
+		offset, err := time.ParseDuration(strings.TrimPrefix(value, "now"))
+		if value == "now" {
+			offset, err = 0, nil
+		}
+		if err != nil {
+			t.Fatalf("parseRelativeTimestamp(%q) accepted an unparsable offset: %v", value, err)
+		}
+		if parsed.Before(before.Add(offset)) || parsed.After(after.Add(offset)) {
+			t.Errorf("parseRelativeTimestamp(%q) = %v, want now%+v", value, parsed, offset)
+		}
+	})
+}
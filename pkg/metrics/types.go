@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"encoding/json"
+	"math"
 	"time"
 )
 
@@ -11,7 +12,9 @@ type MetricPoint struct {
 	Namespace     string            `json:"namespace"`
 	PodName       string            `json:"pod_name"`
 	ContainerName string            `json:"container_name"`
+	NodeName      string            `json:"node_name,omitempty"`
 	MetricName    string            `json:"metric_name"`
+	Category      string            `json:"category,omitempty"`
 	Value         float64           `json:"value"`
 	Unit          string            `json:"unit"`
 	Labels        map[string]string `json:"labels"`
@@ -49,6 +52,8 @@ type QueryRequest struct {
 	Filters    map[string]string `json:"filters"`
 	ErrorBound float64           `json:"error_bound,omitempty"`
 	Confidence float64           `json:"confidence,omitempty"`
+
+	GroupByLabel string `json:"group_by_label,omitempty"`
 }
 
 type QueryType string
@@ -61,6 +66,9 @@ const (
 	TopK           QueryType = "top_k"
 	Membership     QueryType = "membership"
 	FrequencyCount QueryType = "frequency_count"
+	ApproxJoin     QueryType = "approx_join"
+
+	HistogramIntersection QueryType = "histogram_intersection"
 )
 
 type TimeRange struct {
@@ -77,6 +85,7 @@ type QueryResult struct {
 	SampleSize     int           `json:"sample_size"`
 	ProcessingTime time.Duration `json:"processing_time"`
 	IsApproximate  bool          `json:"is_approximate"`
+	QualityScore   float64       `json:"quality_score"`
 	Timestamp      time.Time     `json:"timestamp"`
 }
 
@@ -100,6 +109,268 @@ type PercentileResult struct {
 	Percentile float64 `json:"percentile"`
 	Value      float64 `json:"value"`
 	SampleSize int     `json:"sample_size"`
+	LowerCI    float64 `json:"lower_ci"`
+	UpperCI    float64 `json:"upper_ci"`
+}
+
+type HistogramResult struct {
+	Percentile float64           `json:"percentile"`
+	Value      float64           `json:"value"`
+	SampleSize int               `json:"sample_size"`
+	Scale      int32             `json:"scale"`
+	Buckets    []HistogramBucket `json:"buckets"`
+}
+
+type HistogramBucket struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count uint64  `json:"count"`
+}
+
+type HistogramIntersectionResult struct {
+	BC         float64           `json:"bc"`
+	Divergence float64           `json:"divergence"`
+	HistogramA []HistogramBucket `json:"histogram_a"`
+	HistogramB []HistogramBucket `json:"histogram_b"`
+}
+
+type QuantileMergeResult struct {
+	P50       float64 `json:"p50"`
+	P90       float64 `json:"p90"`
+	P95       float64 `json:"p95"`
+	P99       float64 `json:"p99"`
+	Count     uint64  `json:"count"`
+	Algorithm string  `json:"algorithm"`
+}
+
+type QuantileQueryResult struct {
+	MetricName string             `json:"metric_name"`
+	Quantiles  map[string]float64 `json:"quantiles"`
+	Count      uint64             `json:"count"`
+	Algorithm  string             `json:"algorithm"`
+}
+
+type CDFResult struct {
+	MetricName string  `json:"metric_name"`
+	Value      float64 `json:"value"`
+	Fraction   float64 `json:"fraction"`
+	Count      uint64  `json:"count"`
+	Algorithm  string  `json:"algorithm"`
+}
+
+type CapacityForecast struct {
+	MetricName        string                `json:"metric_name"`
+	TargetThreshold   float64               `json:"target_threshold"`
+	CurrentValue      float64               `json:"current_value"`
+	RatePerHour       float64               `json:"rate_per_hour"`
+	EstimatedBreachAt *time.Time            `json:"estimated_breach_at"`
+	Confidence        string                `json:"confidence"`
+	RSquared          float64               `json:"r_squared"`
+	TopContributors   []CapacityContributor `json:"top_contributors"`
+}
+
+type CapacityContributor struct {
+	Namespace         string     `json:"namespace"`
+	PodName           string     `json:"pod_name"`
+	CurrentValue      float64    `json:"current_value"`
+	RatePerHour       float64    `json:"rate_per_hour"`
+	EstimatedBreachAt *time.Time `json:"estimated_breach_at"`
+}
+
+type TimeSeriesPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+type NamespaceSummary struct {
+	Namespace   string  `json:"namespace"`
+	MetricName  string  `json:"metric_name"`
+	PodCount    int     `json:"pod_count"`
+	Mean        float64 `json:"mean"`
+	Max         float64 `json:"max"`
+	P95         float64 `json:"p95"`
+	TopPod      string  `json:"top_pod"`
+	TopPodValue float64 `json:"top_pod_value"`
+}
+
+type ClusterSummary struct {
+	ClusterID            string    `json:"cluster_id"`
+	TotalPods            uint64    `json:"total_pods"`
+	TotalNamespaces      int       `json:"total_namespaces"`
+	CPUUsageP95          float64   `json:"cpu_usage_p95"`
+	MemoryUsageP95       float64   `json:"memory_usage_p95"`
+	RestartsTotal        float64   `json:"restarts_total"`
+	AnomalyCountLastHour int       `json:"anomaly_count_last_hour"`
+	LastUpdated          time.Time `json:"last_updated"`
+}
+
+type ContainerSummary struct {
+	ClusterID     string                            `json:"cluster_id"`
+	Namespace     string                            `json:"namespace"`
+	PodName       string                            `json:"pod_name"`
+	ContainerName string                            `json:"container_name"`
+	LastSeen      time.Time                         `json:"last_seen"`
+	Metrics       map[string]ContainerMetricSummary `json:"metrics"`
+}
+
+type ContainerMetricSummary struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Latest float64 `json:"latest"`
+}
+
+type PressureScore struct {
+	ClusterID string             `json:"cluster_id,omitempty"`
+	Namespace string             `json:"namespace,omitempty"`
+	PodName   string             `json:"pod_name"`
+	Score     float64            `json:"score"`
+	Breakdown map[string]float64 `json:"breakdown"`
+	Severity  string             `json:"severity"`
+}
+
+type SimilarPod struct {
+	ClusterID   string  `json:"cluster_id"`
+	Namespace   string  `json:"namespace"`
+	PodName     string  `json:"pod_name"`
+	Divergence  float64 `json:"divergence"`
+	SampleCount int     `json:"sample_count"`
+}
+
+type NoisyPod struct {
+	PodName     string  `json:"pod_name"`
+	Namespace   string  `json:"namespace"`
+	ClusterID   string  `json:"cluster_id"`
+	Variance    float64 `json:"variance"`
+	SampleCount int     `json:"sample_count"`
+}
+
+type JoinCardinalityResult struct {
+	LeftFilter              map[string]string `json:"left_filter"`
+	RightFilter             map[string]string `json:"right_filter"`
+	Identity                string            `json:"identity"`
+	LeftCardinality         uint64            `json:"left_cardinality"`
+	RightCardinality        uint64            `json:"right_cardinality"`
+	UnionCardinality        uint64            `json:"union_cardinality"`
+	IntersectionCardinality uint64            `json:"intersection_cardinality"`
+	JaccardIndex            float64           `json:"jaccard_index"`
+}
+
+type RepresentativenessResult struct {
+	Stratum          string  `json:"stratum"`
+	ChiSquared       float64 `json:"chi_squared"`
+	DegreesOfFreedom int     `json:"degrees_of_freedom"`
+	PValue           float64 `json:"p_value"`
+	SampleCount      int     `json:"sample_count"`
+	IsRepresentative bool    `json:"is_representative"`
+}
+
+type SampleSizeResult struct {
+	Type                string            `json:"type"`
+	Percentile          float64           `json:"percentile"`
+	ErrorBound          float64           `json:"error_bound"`
+	Confidence          float64           `json:"confidence"`
+	Filters             map[string]string `json:"filters,omitempty"`
+	RequiredSamples     int               `json:"required_samples"`
+	CurrentSamples      int               `json:"current_samples"`
+	Deficit             int               `json:"deficit"`
+	SamplesPerSecond    float64           `json:"samples_per_second"`
+	ExpectedWaitSeconds *float64          `json:"expected_wait_seconds"`
+}
+
+type DashboardOverview struct {
+	GeneratedAt     time.Time                    `json:"generated_at"`
+	TopNamespaces   []NamespacePodCount          `json:"top_namespaces"`
+	ClusterHealth   []ClusterHealth              `json:"cluster_health"`
+	RecentAnomalies []*MetricPoint               `json:"recent_anomalies"`
+	Throughput      DashboardThroughput          `json:"throughput"`
+	TimeSeries      map[string][]TimeSeriesPoint `json:"time_series"`
+}
+
+type NamespacePodCount struct {
+	Namespace string `json:"namespace"`
+	PodCount  uint64 `json:"pod_count"`
+}
+
+type ClusterHealth struct {
+	ClusterID string             `json:"cluster_id"`
+	Score     float64            `json:"score"`
+	Breakdown map[string]float64 `json:"breakdown"`
+	Severity  string             `json:"severity"`
+}
+
+type DashboardThroughput struct {
+	TotalReceived     uint64        `json:"total_received"`
+	TotalSampled      uint64        `json:"total_sampled"`
+	ReceivedPerSecond float64       `json:"received_per_second"`
+	SampledPerSecond  float64       `json:"sampled_per_second"`
+	TotalQueries      uint64        `json:"total_queries"`
+	AvgLatency        time.Duration `json:"avg_latency"`
+}
+
+type CategorySummary struct {
+	Category       string `json:"category"`
+	DistinctSeries uint64 `json:"distinct_series"`
+	TotalPoints    uint64 `json:"total_points"`
+	SampleCount    int    `json:"sample_count"`
+}
+
+type Gap struct {
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration"`
+}
+
+type NodePod struct {
+	ClusterID   string             `json:"cluster_id"`
+	Namespace   string             `json:"namespace"`
+	PodName     string             `json:"pod_name"`
+	LastSeen    time.Time          `json:"last_seen"`
+	LastMetrics map[string]float64 `json:"last_metrics"`
+}
+
+type JoinResult struct {
+	Left  string            `json:"left"`
+	Right string            `json:"right"`
+	Pods  []JoinedPodMetric `json:"pods"`
+}
+
+type JoinedPodMetric struct {
+	ClusterID string  `json:"cluster_id"`
+	Namespace string  `json:"namespace"`
+	PodName   string  `json:"pod_name"`
+	MetricA   float64 `json:"metric_a"`
+	MetricB   float64 `json:"metric_b"`
+}
+
+type DiffRequest struct {
+	Base    *QueryRequest `json:"base"`
+	Compare *QueryRequest `json:"compare"`
+}
+
+type DiffResult struct {
+	Base    *QueryResult `json:"base"`
+	Compare *QueryResult `json:"compare"`
+	Diff    interface{}  `json:"diff"`
+}
+
+type ScalarDiff struct {
+	BaseResult          float64  `json:"base_result"`
+	CompareResult       float64  `json:"compare_result"`
+	AbsoluteDiff        float64  `json:"absolute_diff"`
+	RelativeDiffPercent *float64 `json:"relative_diff_percent,omitempty"`
+}
+
+type TopKDiff struct {
+	JaccardOverlap float64  `json:"jaccard_overlap"`
+	Common         []string `json:"common"`
+	Added          []string `json:"added"`
+	Removed        []string `json:"removed"`
+}
+
+type HistogramDiff struct {
+	KLDivergence float64 `json:"kl_divergence"`
 }
 
 type MembershipResult struct {
@@ -143,3 +414,34 @@ func (mp *MetricPoint) FromJSON(data string) error {
 func (mp *MetricPoint) GetKey() string {
 	return mp.ClusterID + "/" + mp.Namespace + "/" + mp.PodName + "/" + mp.MetricName
 }
+
+type HeatmapResult struct {
+	MetricName string      `json:"metric_name"`
+	Namespaces []string    `json:"namespaces"`
+	Pods       []string    `json:"pods"`
+	Values     [][]float64 `json:"values"`
+}
+
+func (hr *HeatmapResult) MarshalJSON() ([]byte, error) {
+	values := make([][]*float64, len(hr.Values))
+	for i, row := range hr.Values {
+		values[i] = make([]*float64, len(row))
+		for j := range row {
+			if !math.IsNaN(row[j]) {
+				values[i][j] = &row[j]
+			}
+		}
+	}
+
+	return json.Marshal(struct {
+		MetricName string       `json:"metric_name"`
+		Namespaces []string     `json:"namespaces"`
+		Pods       []string     `json:"pods"`
+		Values     [][]*float64 `json:"values"`
+	}{
+		MetricName: hr.MetricName,
+		Namespaces: hr.Namespaces,
+		Pods:       hr.Pods,
+		Values:     values,
+	})
+}